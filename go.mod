@@ -1,20 +1,22 @@
-module backend-padel-go
-
-go 1.21
-
-require (
-	github.com/gin-gonic/gin v1.9.1
-	github.com/golang-jwt/jwt/v5 v5.2.0
-	github.com/joho/godotenv v1.5.1
-	gorm.io/driver/mysql v1.5.2
-	gorm.io/gorm v1.25.5
-	github.com/gin-contrib/cors v1.5.0
-	github.com/gin-contrib/logger v0.2.6
-	github.com/mercadopago/sdk-go v1.3.0
-	github.com/gorilla/websocket v1.5.1
-	github.com/swaggo/gin-swagger v1.6.0
-	github.com/swaggo/files v1.0.1
-	github.com/swaggo/swag v1.16.2
-	github.com/go-playground/validator/v10 v10.16.0
-	github.com/google/uuid v1.5.0
-)
+module backend-padel-go
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/golang-jwt/jwt/v5 v5.2.0
+	github.com/joho/godotenv v1.5.1
+	gorm.io/driver/mysql v1.5.2
+	gorm.io/gorm v1.25.5
+	gorm.io/driver/sqlite v1.5.3
+	github.com/mattn/go-sqlite3 v1.14.17
+	github.com/gin-contrib/cors v1.5.0
+	github.com/gin-contrib/logger v0.2.6
+	github.com/mercadopago/sdk-go v1.3.0
+	github.com/gorilla/websocket v1.5.1
+	github.com/swaggo/gin-swagger v1.6.0
+	github.com/swaggo/files v1.0.1
+	github.com/swaggo/swag v1.16.2
+	github.com/go-playground/validator/v10 v10.16.0
+	github.com/google/uuid v1.5.0
+)
@@ -1,84 +1,124 @@
-package config
-
-import (
-	"os"
-	"strconv"
-)
-
-type Config struct {
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
-	MercadoPago MercadoPagoConfig
-}
-
-type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	Charset  string
-}
-
-type JWTConfig struct {
-	SecretKey     string
-	AccessTokenExpiry  int // en horas
-	RefreshTokenExpiry int // en horas
-}
-
-type ServerConfig struct {
-	Port string
-	Mode string
-}
-
-type MercadoPagoConfig struct {
-	AccessToken string
-	WebhookSecret string
-	FrontendURL string
-	BackendURL  string
-}
-
-func Load() *Config {
-	return &Config{
-		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "3306"),
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "padel_db"),
-			Charset:  getEnv("DB_CHARSET", "utf8mb4"),
-		},
-		JWT: JWTConfig{
-			SecretKey:         getEnv("JWT_SECRET", "your-secret-key"),
-			AccessTokenExpiry:  getEnvAsInt("JWT_ACCESS_EXPIRY", 24), // 24 horas
-			RefreshTokenExpiry: getEnvAsInt("JWT_REFRESH_EXPIRY", 168), // 7 días
-		},
-		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Mode: getEnv("GIN_MODE", "debug"),
-		},
-		MercadoPago: MercadoPagoConfig{
-			AccessToken:  getEnv("MERCADOPAGO_ACCESS_TOKEN", ""),
-			WebhookSecret: getEnv("MERCADOPAGO_WEBHOOK_SECRET", ""),
-			FrontendURL:  getEnv("FRONTEND_URL", "http://localhost:3000"),
-			BackendURL:   getEnv("BACKEND_URL", "http://localhost:8080"),
-		},
-	}
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+type Config struct {
+	Database DatabaseConfig
+	JWT      JWTConfig
+	Server   ServerConfig
+	MercadoPago MercadoPagoConfig
+	Payment  PaymentConfig
+	Booking  BookingConfig
+}
+
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	Charset  string
+}
+
+type JWTConfig struct {
+	SecretKey     string
+	AccessTokenExpiry  int // en horas
+	RefreshTokenExpiry int // en horas
+}
+
+type ServerConfig struct {
+	Port string
+	Mode string
+}
+
+type MercadoPagoConfig struct {
+	AccessToken string
+	WebhookSecret string
+	WebhookToleranceSec int // antigüedad máxima del ts firmado de una notificación
+	FrontendURL string
+	BackendURL  string
+}
+
+type PaymentConfig struct {
+	Provider              string // mercadopago o sandbox (simulado, para pruebas y desarrollo local)
+	ReconcileIntervalSec  int    // frecuencia de la conciliación de pagos pendientes con el proveedor
+	ReconcileAfterMinutes int    // antigüedad mínima de un pago pendiente para consultarlo al proveedor
+}
+
+type BookingConfig struct {
+	PendingHoldMinutes     int // tiempo que una reserva pendiente bloquea el turno
+	SeriesPaymentLeadHours int // anticipación con que debe pagarse cada fecha de un turno fijo
+	HoldExpirerIntervalSec int // frecuencia del proceso que libera reservas vencidas
+	CompletionIntervalSec  int // frecuencia del proceso que marca como completadas las reservas terminadas
+	MinDurationMinutes     int
+	MaxDurationMinutes     int
+	SlotGranularityMinutes int // los turnos deben comenzar en múltiplos de este valor
+	MinLeadTimeMinutes     int // anticipación mínima para reservar
+	WaitlistClaimMinutes   int // plazo exclusivo de los notificados de la lista de espera para tomar un turno liberado
+	WaitlistNotifyCount    int // cantidad de usuarios de la lista de espera notificados por cada turno liberado
+}
+
+func Load() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", "3306"),
+			User:     getEnv("DB_USER", "root"),
+			Password: getEnv("DB_PASSWORD", ""),
+			DBName:   getEnv("DB_NAME", "padel_db"),
+			Charset:  getEnv("DB_CHARSET", "utf8mb4"),
+		},
+		JWT: JWTConfig{
+			SecretKey:         getEnv("JWT_SECRET", "your-secret-key"),
+			AccessTokenExpiry:  getEnvAsInt("JWT_ACCESS_EXPIRY", 24), // 24 horas
+			RefreshTokenExpiry: getEnvAsInt("JWT_REFRESH_EXPIRY", 168), // 7 días
+		},
+		Server: ServerConfig{
+			Port: getEnv("PORT", "8080"),
+			Mode: getEnv("GIN_MODE", "debug"),
+		},
+		MercadoPago: MercadoPagoConfig{
+			AccessToken:  getEnv("MERCADOPAGO_ACCESS_TOKEN", ""),
+			WebhookSecret: getEnv("MERCADOPAGO_WEBHOOK_SECRET", ""),
+			WebhookToleranceSec: getEnvAsInt("MERCADOPAGO_WEBHOOK_TOLERANCE", 300),
+			FrontendURL:  getEnv("FRONTEND_URL", "http://localhost:3000"),
+			BackendURL:   getEnv("BACKEND_URL", "http://localhost:8080"),
+		},
+		Payment: PaymentConfig{
+			Provider:              getEnv("PAYMENT_PROVIDER", "mercadopago"),
+			ReconcileIntervalSec:  getEnvAsInt("PAYMENT_RECONCILE_INTERVAL", 300),
+			ReconcileAfterMinutes: getEnvAsInt("PAYMENT_RECONCILE_AFTER", 10),
+		},
+		Booking: BookingConfig{
+			PendingHoldMinutes:     getEnvAsInt("BOOKING_HOLD_MINUTES", 15),
+			SeriesPaymentLeadHours: getEnvAsInt("BOOKING_SERIES_PAYMENT_LEAD", 24),
+			HoldExpirerIntervalSec: getEnvAsInt("BOOKING_HOLD_EXPIRER_INTERVAL", 60),
+			CompletionIntervalSec:  getEnvAsInt("BOOKING_COMPLETION_INTERVAL", 300),
+			MinDurationMinutes:     getEnvAsInt("BOOKING_MIN_DURATION", 60),
+			MaxDurationMinutes:     getEnvAsInt("BOOKING_MAX_DURATION", 180),
+			SlotGranularityMinutes: getEnvAsInt("BOOKING_SLOT_GRANULARITY", 30),
+			MinLeadTimeMinutes:     getEnvAsInt("BOOKING_MIN_LEAD_TIME", 30),
+			WaitlistClaimMinutes:   getEnvAsInt("WAITLIST_CLAIM_MINUTES", 15),
+			WaitlistNotifyCount:    getEnvAsInt("WAITLIST_NOTIFY_COUNT", 3),
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
@@ -1,82 +1,110 @@
-package database
-
-import (
-	"fmt"
-	"log"
-	"os"
-
-	"backend-padel-go/internal/config"
-	"backend-padel-go/internal/models"
-
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
-)
-
-var DB *gorm.DB
-
-func Connect() (*gorm.DB, error) {
-	cfg := config.Load()
-	
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=True&loc=Local",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DBName,
-		cfg.Database.Charset,
-	)
-
-	// Configurar nivel de logging de GORM
-	var logLevel logger.LogLevel
-	if os.Getenv("GIN_MODE") == "release" {
-		logLevel = logger.Silent
-	} else {
-		logLevel = logger.Info
-	}
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	// Configurar pool de conexiones
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
-	}
-
-	// Configurar pool de conexiones
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-
-	DB = db
-	return db, nil
-}
-
-func Migrate(db *gorm.DB) error {
-	log.Println("Starting database migration...")
-
-	// Migrar todos los modelos
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.Court{},
-		&models.BusinessHour{},
-		&models.SpecialHour{},
-		&models.Booking{},
-		&models.Review{},
-		&models.Payment{},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	log.Println("Database migration completed successfully")
-	return nil
-}
-
-func GetDB() *gorm.DB {
-	return DB
-}
+package database
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var DB *gorm.DB
+
+func Connect() (*gorm.DB, error) {
+	cfg := config.Load()
+	
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=True&loc=Local",
+		cfg.Database.User,
+		cfg.Database.Password,
+		cfg.Database.Host,
+		cfg.Database.Port,
+		cfg.Database.DBName,
+		cfg.Database.Charset,
+	)
+
+	// Configurar nivel de logging de GORM
+	var logLevel logger.LogLevel
+	if os.Getenv("GIN_MODE") == "release" {
+		logLevel = logger.Silent
+	} else {
+		logLevel = logger.Info
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logLevel),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Configurar pool de conexiones
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	// Configurar pool de conexiones
+	sqlDB.SetMaxIdleConns(10)
+	sqlDB.SetMaxOpenConns(100)
+
+	DB = db
+	return db, nil
+}
+
+func Migrate(db *gorm.DB) error {
+	log.Println("Starting database migration...")
+
+	// Migrar todos los modelos
+	err := db.AutoMigrate(
+		&models.User{},
+		&models.Club{},
+		&models.ClubBusinessHour{},
+		&models.Court{},
+		&models.BusinessHour{},
+		&models.SpecialHour{},
+		&models.BookingSeries{},
+		&models.Booking{},
+		&models.BookingStatusHistory{},
+		&models.BookingParticipant{},
+		&models.OpenMatch{},
+		&models.PlayerProfile{},
+		&models.MatchResult{},
+		&models.MatchResultPlayer{},
+		&models.RatingHistory{},
+		&models.Tournament{},
+		&models.TournamentPair{},
+		&models.TournamentMatch{},
+		&models.League{},
+		&models.LeagueDivision{},
+		&models.LeagueTeam{},
+		&models.LeagueFixture{},
+		&models.Coach{},
+		&models.Class{},
+		&models.ClassSession{},
+		&models.ClassEnrollment{},
+		&models.ClassPack{},
+		&models.Review{},
+		&models.Payment{},
+		&models.Refund{},
+		&models.WebhookEvent{},
+		&models.PaymentReconciliation{},
+		&models.PricingRule{},
+		&models.WaitlistEntry{},
+		&models.Notification{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	log.Println("Database migration completed successfully")
+	return nil
+}
+
+func GetDB() *gorm.DB {
+	return DB
+}
@@ -1,102 +1,102 @@
-package handlers
-
-import (
-	"net/http"
-
-	"backend-padel-go/internal/models"
-	"backend-padel-go/internal/services"
-
-	"github.com/gin-gonic/gin"
-)
-
-type AuthHandler struct {
-	authService *services.AuthService
-}
-
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
-}
-
-// Register godoc
-// @Summary Register a new user
-// @Description Register a new user with email and password
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param request body models.RegisterRequest true "Register request"
-// @Success 201 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /auth/register [post]
-func (h *AuthHandler) Register(c *gin.Context) {
-	var req models.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
-		return
-	}
-
-	response, err := h.authService.Register(&req)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Registration failed", err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusCreated, models.NewSuccessResponse(response))
-}
-
-// Login godoc
-// @Summary Login user
-// @Description Login user with email and password
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param request body models.LoginRequest true "Login request"
-// @Success 200 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Router /auth/login [post]
-func (h *AuthHandler) Login(c *gin.Context) {
-	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
-		return
-	}
-
-	response, err := h.authService.Login(&req)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Login failed", err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(response))
-}
-
-// RefreshToken godoc
-// @Summary Refresh access token
-// @Description Refresh access token using refresh token
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param request body map[string]string true "Refresh token request"
-// @Success 200 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Router /auth/refresh [post]
-func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	var req struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
-		return
-	}
-
-	response, err := h.authService.RefreshToken(req.RefreshToken)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Token refresh failed", err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(response))
-}
+package handlers
+
+import (
+	"net/http"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService *services.AuthService
+}
+
+func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// Register godoc
+// @Summary Register a new user
+// @Description Register a new user with email and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RegisterRequest true "Register request"
+// @Success 201 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	response, err := h.authService.Register(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Registration failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(response))
+}
+
+// Login godoc
+// @Summary Login user
+// @Description Login user with email and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginRequest true "Login request"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	response, err := h.authService.Login(&req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Login failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(response))
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Refresh access token using refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body map[string]string true "Refresh token request"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	response, err := h.authService.RefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Token refresh failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(response))
+}
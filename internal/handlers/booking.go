@@ -1,202 +1,703 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"backend-padel-go/internal/middleware"
-	"backend-padel-go/internal/models"
-	"backend-padel-go/internal/services"
-
-	"github.com/gin-gonic/gin"
-)
-
-type BookingHandler struct {
-	bookingService *services.BookingService
-}
-
-func NewBookingHandler(bookingService *services.BookingService) *BookingHandler {
-	return &BookingHandler{bookingService: bookingService}
-}
-
-// CreateBooking godoc
-// @Summary Create a new booking
-// @Description Create a new booking for a court
-// @Tags bookings
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body models.CreateBookingRequest true "Create booking request"
-// @Success 201 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /bookings [post]
-func (h *BookingHandler) CreateBooking(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
-		return
-	}
-
-	var req models.CreateBookingRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
-		return
-	}
-
-	booking, err := h.bookingService.CreateBooking(userIDUint, &req)
-	if err != nil {
-		if err.Error() == "court not found" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse("Court not found", err.Error()))
-		} else if err.Error() == "time slot not available" {
-			c.JSON(http.StatusConflict, models.NewErrorResponse("Time slot not available", err.Error()))
-		} else {
-			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create booking", err.Error()))
-		}
-		return
-	}
-
-	c.JSON(http.StatusCreated, models.NewSuccessResponse(booking))
-}
-
-// GetUserBookings godoc
-// @Summary Get user bookings
-// @Description Get all bookings for the authenticated user
-// @Tags bookings
-// @Produce json
-// @Security BearerAuth
-// @Param court_id query int false "Filter by court ID"
-// @Param date query string false "Filter by date (YYYY-MM-DD)"
-// @Param status query string false "Filter by status"
-// @Success 200 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /bookings [get]
-func (h *BookingHandler) GetUserBookings(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
-		return
-	}
-
-	var filters models.GetBookingsRequest
-	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
-		return
-	}
-
-	bookings, err := h.bookingService.GetUserBookings(userIDUint, &filters)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch bookings", err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(bookings))
-}
-
-// GetBookingByID godoc
-// @Summary Get booking by ID
-// @Description Get a specific booking by its ID
-// @Tags bookings
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "Booking ID"
-// @Success 200 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 404 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /bookings/{id} [get]
-func (h *BookingHandler) GetBookingByID(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
-		return
-	}
-
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
-		return
-	}
-
-	booking, err := h.bookingService.GetBookingByID(uint(id), userIDUint)
-	if err != nil {
-		if err.Error() == "booking not found" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch booking", err.Error()))
-		}
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(booking))
-}
-
-// CancelBooking godoc
-// @Summary Cancel booking
-// @Description Cancel a booking
-// @Tags bookings
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "Booking ID"
-// @Success 200 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 404 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /bookings/{id}/cancel [put]
-func (h *BookingHandler) CancelBooking(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
-		return
-	}
-
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
-		return
-	}
-
-	err = h.bookingService.CancelBooking(uint(id), userIDUint)
-	if err != nil {
-		if err.Error() == "booking not found" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
-		} else if err.Error() == "booking already cancelled" || err.Error() == "cannot cancel completed booking" {
-			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel booking", err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel booking", err.Error()))
-		}
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Booking cancelled successfully"}))
-}
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-padel-go/internal/middleware"
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookingHandler struct {
+	bookingService *services.BookingService
+}
+
+func NewBookingHandler(bookingService *services.BookingService) *BookingHandler {
+	return &BookingHandler{bookingService: bookingService}
+}
+
+// CreateBooking godoc
+// @Summary Create a new booking
+// @Description Create a new booking for a court
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateBookingRequest true "Create booking request"
+// @Success 201 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings [post]
+func (h *BookingHandler) CreateBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	booking, err := h.bookingService.CreateBooking(userIDUint, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.As(err, &ruleErr) {
+			status := http.StatusBadRequest
+			if ruleErr.Conflict {
+				status = http.StatusConflict
+			}
+			c.JSON(status, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "court not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Court not found", err.Error()))
+		} else if err.Error() == "time slot not available" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Time slot not available", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(booking))
+}
+
+// GetUserBookings godoc
+// @Summary Get user bookings
+// @Description Get all bookings for the authenticated user
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param court_id query int false "Filter by court ID"
+// @Param date query string false "Filter by date (YYYY-MM-DD)"
+// @Param status query string false "Filter by status"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings [get]
+func (h *BookingHandler) GetUserBookings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var filters models.GetBookingsRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	bookings, err := h.bookingService.GetUserBookings(userIDUint, &filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch bookings", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(bookings))
+}
+
+// GetBookingByID godoc
+// @Summary Get booking by ID
+// @Description Get a specific booking by its ID
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id} [get]
+func (h *BookingHandler) GetBookingByID(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	booking, err := h.bookingService.GetBookingByID(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(booking))
+}
+
+// CancelBooking godoc
+// @Summary Cancel booking
+// @Description Cancel a booking applying the court cancellation policy and refunding the paid amount that corresponds
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.CancellationResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/cancel [put]
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	cancellation, err := h.bookingService.CancelBooking(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "booking already cancelled" || err.Error() == "cannot cancel completed booking" || err.Error() == "cannot cancel a booking that already started" || err.Error() == "booking cannot be cancelled in its current status" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel booking", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(cancellation))
+}
+
+// SplitBooking godoc
+// @Summary Split booking payment
+// @Description Split the payment of a pending booking among the given number of players (up to the court max players). Each player then pays their share through its own preference; the booking is confirmed when every share is paid before the hold expires
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.SplitBookingRequest true "Number of players"
+// @Success 200 {object} models.APIResponse{data=models.BookingResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/split [put]
+func (h *BookingHandler) SplitBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var req models.SplitBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	booking, err := h.bookingService.SplitBooking(uint(id), userIDUint, &req)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "booking already has payments" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot split booking", err.Error()))
+		} else if err.Error() == "booking is not awaiting payment" || err.Error() == "invalid number of split players" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot split booking", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to split booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(booking))
+}
+
+// GetBookingHistory godoc
+// @Summary Get booking status history
+// @Description Get the status changes of a booking with the actor and reason of each change
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=[]models.BookingStatusHistory}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/history [get]
+func (h *BookingHandler) GetBookingHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	history, err := h.bookingService.GetBookingHistory(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch booking history", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(history))
+}
+
+// GetOwnerBookings godoc
+// @Summary Get owner bookings
+// @Description Get the bookings of all the courts of the authenticated owner
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param court_id query int false "Filter by court ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param status query string false "Filter by status (comma separated)"
+// @Success 200 {object} models.APIResponse{data=[]models.BookingResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/bookings [get]
+func (h *BookingHandler) GetOwnerBookings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var filters models.OwnerBookingsRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	bookings, err := h.bookingService.GetOwnerBookings(ownerID, &filters)
+	if err != nil {
+		if err.Error() == "invalid date format" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid date format", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch bookings", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(bookings))
+}
+
+// GetCourtDaySchedule godoc
+// @Summary Get court daily schedule
+// @Description Get the daily booking grid of a court of the authenticated owner
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Court ID"
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {object} models.APIResponse{data=models.CourtDayScheduleResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/courts/{id}/schedule [get]
+func (h *BookingHandler) GetCourtDaySchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid court ID", err.Error()))
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid date format", err.Error()))
+		return
+	}
+
+	schedule, err := h.bookingService.GetCourtDaySchedule(uint(id), ownerID, date)
+	if err != nil {
+		if err.Error() == "court not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Court not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get schedule", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(schedule))
+}
+
+// OwnerConfirmBooking godoc
+// @Summary Confirm booking (owner)
+// @Description Manually confirm a pending booking of a court of the authenticated owner. A booking whose payment hold expired can only be confirmed while its slot is still free
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.BookingResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/bookings/{id}/confirm [put]
+func (h *BookingHandler) OwnerConfirmBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	booking, err := h.bookingService.OwnerConfirmBooking(uint(id), ownerID)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "booking cannot be confirmed in its current status" || err.Error() == "time slot not available" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot confirm booking", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to confirm booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(booking))
+}
+
+// OwnerCancelBooking godoc
+// @Summary Cancel booking (owner)
+// @Description Cancel a booking of a court of the authenticated owner with a reason, fully refunding its payment
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.OwnerBookingActionRequest true "Cancellation reason"
+// @Success 200 {object} models.APIResponse{data=models.CancellationResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/bookings/{id}/cancel [put]
+func (h *BookingHandler) OwnerCancelBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var req models.OwnerBookingActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	cancellation, err := h.bookingService.OwnerCancelBooking(uint(id), ownerID, req.Reason)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "cancellation reason is required" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cancellation reason is required", err.Error()))
+		} else if err.Error() == "booking cannot be cancelled in its current status" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot cancel booking", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(cancellation))
+}
+
+// MarkNoShow godoc
+// @Summary Mark booking as no-show (owner)
+// @Description Mark a confirmed booking that already started as no-show, refunding the percentage set in the court cancellation policy
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.OwnerBookingActionRequest false "Reason"
+// @Success 200 {object} models.APIResponse{data=models.CancellationResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/bookings/{id}/no-show [put]
+func (h *BookingHandler) MarkNoShow(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	// El motivo es opcional
+	var req models.OwnerBookingActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+			return
+		}
+	}
+
+	result, err := h.bookingService.MarkNoShow(uint(id), ownerID, req.Reason)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "booking has not started yet" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot mark booking as no-show", err.Error()))
+		} else if err.Error() == "booking cannot be marked as no-show in its current status" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot mark booking as no-show", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to mark booking as no-show", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
+
+// OwnerCreateBooking godoc
+// @Summary Create phone or walk-in booking (owner)
+// @Description Create a confirmed booking on a court of the authenticated owner for a registered user or a guest, optionally recording a manual payment
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.OwnerCreateBookingRequest true "Owner booking request"
+// @Success 201 {object} models.APIResponse{data=models.BookingResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/bookings [post]
+func (h *BookingHandler) OwnerCreateBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.OwnerCreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	booking, err := h.bookingService.OwnerCreateBooking(ownerID, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.As(err, &ruleErr) {
+			status := http.StatusBadRequest
+			if ruleErr.Conflict {
+				status = http.StatusConflict
+			}
+			c.JSON(status, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "court not found" || err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+		} else if err.Error() == "time slot not available" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Time slot not available", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(booking))
+}
+
+// OwnerRecordPayment godoc
+// @Summary Record manual payment (owner)
+// @Description Record a cash or transfer payment for a booking of a court of the authenticated owner, confirming it if pending. A pending booking whose payment hold expired can only be paid while its slot is still free
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.ManualPaymentRequest true "Manual payment request"
+// @Success 201 {object} models.APIResponse{data=models.Payment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/bookings/{id}/payments [post]
+func (h *BookingHandler) OwnerRecordPayment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var req models.ManualPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	payment, err := h.bookingService.OwnerRecordPayment(uint(id), ownerID, &req)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "invalid payment method" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid payment method", err.Error()))
+		} else if err.Error() == "booking already paid" || err.Error() == "booking cannot be paid in its current status" || err.Error() == "time slot not available" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot record payment", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to record payment", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(payment))
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBookingSeries godoc
+// @Summary Create recurring booking series
+// @Description Create a weekly or biweekly fixed slot. Availability is checked for every date first; conflicting dates are reported and nothing is created unless skip_conflicts is set. Each occurrence is created pending payment and holds its slot until BOOKING_SERIES_PAYMENT_LEAD hours before it starts
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series body models.CreateBookingSeriesRequest true "Series data"
+// @Success 201 {object} models.APIResponse{data=models.BookingSeriesResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse{data=models.BookingSeriesResponse}
+// @Router /bookings/series [post]
+func (h *BookingHandler) CreateBookingSeries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateBookingSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	series, err := h.bookingService.CreateBookingSeries(userIDUint, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.Is(err, services.ErrSeriesConflict) {
+			// El reporte por fecha acompaña al error para que el usuario sepa qué fechas excluir
+			response := models.NewErrorResponse("Some dates of the series are not available", err.Error())
+			response.Data = series
+			c.JSON(http.StatusConflict, response)
+		} else if errors.As(err, &ruleErr) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "court not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Court not found", err.Error()))
+		} else if err.Error() == "series has no available dates" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("No available dates in series", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create booking series", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(series))
+}
+
+// GetUserSeries godoc
+// @Summary Get user booking series
+// @Description Get the recurring booking series of the authenticated user
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.BookingSeries}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/series [get]
+func (h *BookingHandler) GetUserSeries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	series, err := h.bookingService.GetUserSeries(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch booking series", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(series))
+}
+
+// GetSeriesByID godoc
+// @Summary Get booking series by ID
+// @Description Get a recurring booking series with its occurrences
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Success 200 {object} models.APIResponse{data=models.BookingSeries}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/series/{id} [get]
+func (h *BookingHandler) GetSeriesByID(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid series ID", err.Error()))
+		return
+	}
+
+	series, err := h.bookingService.GetSeriesByID(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking series not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking series not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch booking series", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(series))
+}
+
+// CancelSeriesOccurrence godoc
+// @Summary Cancel a single occurrence
+// @Description Cancel one date of a recurring series applying the court cancellation policy; the rest of the series is kept
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param bookingId path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.CancellationResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/series/{id}/occurrences/{bookingId}/cancel [put]
+func (h *BookingHandler) CancelSeriesOccurrence(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid series ID", err.Error()))
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("bookingId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	cancellation, err := h.bookingService.CancelSeriesOccurrence(uint(id), uint(bookingID), userIDUint)
+	if err != nil {
+		if err.Error() == "booking series not found" || err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "booking already cancelled" || err.Error() == "cannot cancel completed booking" || err.Error() == "cannot cancel a booking that already started" || err.Error() == "booking cannot be cancelled in its current status" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel booking", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel booking", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(cancellation))
+}
+
+// CancelSeriesRemainder godoc
+// @Summary Cancel the remainder of a series
+// @Description Cancel every upcoming occurrence of a recurring series from the given date (today by default)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param request body models.CancelSeriesRequest false "Cancellation start date"
+// @Success 200 {object} models.APIResponse{data=models.CancelSeriesResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/series/{id}/cancel [put]
+func (h *BookingHandler) CancelSeriesRemainder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid series ID", err.Error()))
+		return
+	}
+
+	// El cuerpo es opcional: sin fecha se cancelan todas las ocurrencias futuras
+	var req models.CancelSeriesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+			return
+		}
+	}
+
+	result, err := h.bookingService.CancelSeriesRemainder(uint(id), userIDUint, &req)
+	if err != nil {
+		if err.Error() == "booking series not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking series not found", err.Error()))
+		} else if err.Error() == "booking series already cancelled" || err.Error() == "invalid date format" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel booking series", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel booking series", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
@@ -0,0 +1,861 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClassHandler struct {
+	classService *services.ClassService
+}
+
+func NewClassHandler(classService *services.ClassService) *ClassHandler {
+	return &ClassHandler{classService: classService}
+}
+
+// GetClasses godoc
+// @Summary Get classes
+// @Description Get the active classes with their coach and court, optionally filtered by court, coach and level
+// @Tags classes
+// @Produce json
+// @Param court_id query int false "Court ID"
+// @Param coach_id query int false "Coach ID"
+// @Param level query string false "Level"
+// @Success 200 {object} models.APIResponse{data=[]models.Class}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes [get]
+func (h *ClassHandler) GetClasses(c *gin.Context) {
+	var filters models.GetClassesRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	classes, err := h.classService.GetClasses(&filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch classes", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(classes))
+}
+
+// GetClassSessions godoc
+// @Summary Get class sessions
+// @Description Get the upcoming sessions of a class with the available spots
+// @Tags classes
+// @Produce json
+// @Param id path int true "Class ID"
+// @Success 200 {object} models.APIResponse{data=[]models.ClassSessionResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/{id}/sessions [get]
+func (h *ClassHandler) GetClassSessions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid class ID", err.Error()))
+		return
+	}
+
+	sessions, err := h.classService.GetClassSessions(uint(id))
+	if err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Class not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch class sessions", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(sessions))
+}
+
+// EnrollClass godoc
+// @Summary Enroll in class session
+// @Description Enroll the authenticated user in a class session if there are spots left. A credit of a class pack is used when available; otherwise the class is paid at the club
+// @Tags classes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Param request body models.EnrollClassRequest false "Payment option"
+// @Success 201 {object} models.APIResponse{data=models.ClassEnrollment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/sessions/{id}/enroll [post]
+func (h *ClassHandler) EnrollClass(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID", err.Error()))
+		return
+	}
+
+	// El cuerpo es opcional: sin pay_with se usa un pack si hay créditos
+	var req models.EnrollClassRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+			return
+		}
+	}
+
+	enrollment, err := h.classService.Enroll(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "session not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Class session not found", err.Error()))
+		case "class is full", "already enrolled in this class":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot enroll in class", err.Error()))
+		case "class is not open for enrollment", "class already started", "no class credits available", "invalid payment option":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot enroll in class", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to enroll in class", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(enrollment))
+}
+
+// CancelClassEnrollment godoc
+// @Summary Cancel class enrollment
+// @Description Cancel an enrollment before the class starts. The spot is released and the pack credit is returned
+// @Tags classes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Enrollment ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/enrollments/{id} [delete]
+func (h *ClassHandler) CancelClassEnrollment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid enrollment ID", err.Error()))
+		return
+	}
+
+	if err := h.classService.CancelEnrollment(uint(id), userIDUint); err != nil {
+		if err.Error() == "enrollment not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Enrollment not found", err.Error()))
+		} else if err.Error() == "enrollment cannot be cancelled" || err.Error() == "class already started" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel enrollment", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel enrollment", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Enrollment cancelled successfully"}))
+}
+
+// GetMyClassEnrollments godoc
+// @Summary Get my class enrollments
+// @Description Get the class enrollments of the authenticated user with the session, coach and court
+// @Tags classes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.ClassEnrollment}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/enrollments [get]
+func (h *ClassHandler) GetMyClassEnrollments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	enrollments, err := h.classService.GetUserEnrollments(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch enrollments", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(enrollments))
+}
+
+// GetMyClassPacks godoc
+// @Summary Get my class packs
+// @Description Get the class packs of the authenticated user with their used credits
+// @Tags classes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.ClassPack}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/packs [get]
+func (h *ClassHandler) GetMyClassPacks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	packs, err := h.classService.GetUserPacks(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch class packs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(packs))
+}
+
+// GetCoachSessions godoc
+// @Summary Get coach sessions
+// @Description Get the upcoming sessions of the classes taught by the authenticated user, with the enrolled students
+// @Tags classes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.ClassSession}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/coaching [get]
+func (h *ClassHandler) GetCoachSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	sessions, err := h.classService.GetCoachSessions(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch class sessions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(sessions))
+}
+
+// GetSessionRoster godoc
+// @Summary Get class session roster
+// @Description Get the students of a class session with their payment and attendance. Available to the coach of the class and the club owner
+// @Tags classes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} models.APIResponse{data=models.ClassSession}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/sessions/{id}/roster [get]
+func (h *ClassHandler) GetSessionRoster(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID", err.Error()))
+		return
+	}
+
+	session, err := h.classService.GetSessionRoster(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "session not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Class session not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch roster", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(session))
+}
+
+// MarkClassAttendance godoc
+// @Summary Mark class attendance
+// @Description Record which students attended a class session and mark it as completed. Available to the coach of the class and the club owner
+// @Tags classes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Param request body models.MarkAttendanceRequest true "Attendance"
+// @Success 200 {object} models.APIResponse{data=models.ClassSession}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /classes/sessions/{id}/attendance [put]
+func (h *ClassHandler) MarkClassAttendance(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID", err.Error()))
+		return
+	}
+
+	var req models.MarkAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	session, err := h.classService.MarkAttendance(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "session not found", "enrollment not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+		case "session is cancelled", "class has not started yet":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot record attendance", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to record attendance", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(session))
+}
+
+// CreateCoach godoc
+// @Summary Create coach (owner)
+// @Description Register a coach of the club. Linking a user account lets the coach see the roster of their classes
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCoachRequest true "Coach data"
+// @Success 201 {object} models.APIResponse{data=models.Coach}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/coaches [post]
+func (h *ClassHandler) CreateCoach(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateCoachRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	coach, err := h.classService.CreateCoach(userIDUint, &req)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("User not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create coach", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(coach))
+}
+
+// GetCoaches godoc
+// @Summary Get coaches (owner)
+// @Description Get the coaches of the authenticated owner
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.Coach}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/coaches [get]
+func (h *ClassHandler) GetCoaches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	coaches, err := h.classService.GetCoaches(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch coaches", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(coaches))
+}
+
+// UpdateCoach godoc
+// @Summary Update coach (owner)
+// @Description Update a coach of the club; is_active=false keeps the coach out of new classes
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Coach ID"
+// @Param request body models.UpdateCoachRequest true "Coach data"
+// @Success 200 {object} models.APIResponse{data=models.Coach}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/coaches/{id} [put]
+func (h *ClassHandler) UpdateCoach(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid coach ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateCoachRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	coach, err := h.classService.UpdateCoach(uint(id), userIDUint, &req)
+	if err != nil {
+		if err.Error() == "coach not found" || err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update coach", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(coach))
+}
+
+// CreateClass godoc
+// @Summary Create class (owner)
+// @Description Create a weekly class with a coach on a court of the owner. Each session reserves the court slot; conflicting dates are reported and nothing is created unless skip_conflicts is set
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateClassRequest true "Class data"
+// @Success 201 {object} models.APIResponse{data=models.ClassScheduleResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse{data=models.ClassScheduleResponse}
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes [post]
+func (h *ClassHandler) CreateClass(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	schedule, err := h.classService.CreateClass(userIDUint, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.Is(err, services.ErrSeriesConflict) {
+			// El reporte por fecha acompaña al error para que el club sepa qué fechas excluir
+			response := models.NewErrorResponse("Some dates of the class are not available", err.Error())
+			response.Data = schedule
+			c.JSON(http.StatusConflict, response)
+		} else if errors.As(err, &ruleErr) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "court not found" || err.Error() == "coach not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+		} else if err.Error() == "class has no available dates" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("No available dates for class", err.Error()))
+		} else if err.Error() == "failed to create class" || err.Error() == "failed to create class session" || err.Error() == "failed to create booking" {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create class", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid class data", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(schedule))
+}
+
+// GetOwnerClasses godoc
+// @Summary Get owner classes
+// @Description Get the classes of the authenticated owner, including cancelled and finished ones
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.Class}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes [get]
+func (h *ClassHandler) GetOwnerClasses(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	classes, err := h.classService.GetOwnerClasses(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch classes", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(classes))
+}
+
+// CancelClass godoc
+// @Summary Cancel class (owner)
+// @Description Cancel a class and its upcoming sessions. The court slots are released, students are notified and pack credits are returned
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Class ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes/{id}/cancel [put]
+func (h *ClassHandler) CancelClass(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid class ID", err.Error()))
+		return
+	}
+
+	if err := h.classService.CancelClass(uint(id), userIDUint); err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Class not found", err.Error()))
+		} else if err.Error() == "class already cancelled" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel class", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel class", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Class cancelled successfully"}))
+}
+
+// CancelClassSession godoc
+// @Summary Cancel class session (owner)
+// @Description Cancel a single session of a class. The court slot is released, students are notified and pack credits are returned
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes/sessions/{id}/cancel [put]
+func (h *ClassHandler) CancelClassSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID", err.Error()))
+		return
+	}
+
+	if err := h.classService.CancelSession(uint(id), userIDUint); err != nil {
+		if err.Error() == "session not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Class session not found", err.Error()))
+		} else if err.Error() == "session cannot be cancelled" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel class session", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel class session", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Class session cancelled successfully"}))
+}
+
+// SellClassPack godoc
+// @Summary Sell class pack (owner)
+// @Description Record the sale of a class pack paid at the club. Its credits can be used for any class of the owner
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SellClassPackRequest true "Pack data"
+// @Success 201 {object} models.APIResponse{data=models.ClassPack}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes/packs [post]
+func (h *ClassHandler) SellClassPack(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.SellClassPackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	pack, err := h.classService.SellPack(userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("User not found", err.Error()))
+		case "credits must be at least 1", "invalid payment method":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid class pack", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to sell class pack", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(pack))
+}
+
+// RecordClassPayment godoc
+// @Summary Record class payment (owner)
+// @Description Record the payment at the club (cash or transfer) of a single class enrollment
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Enrollment ID"
+// @Param request body models.ManualPaymentRequest true "Payment method"
+// @Success 200 {object} models.APIResponse{data=models.ClassEnrollment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes/enrollments/{id}/payment [put]
+func (h *ClassHandler) RecordClassPayment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid enrollment ID", err.Error()))
+		return
+	}
+
+	var req models.ManualPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	enrollment, err := h.classService.RecordEnrollmentPayment(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Enrollment not found", err.Error()))
+		case "failed to fetch enrollment", "failed to record payment":
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to record payment", err.Error()))
+		default:
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot record payment", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(enrollment))
+}
+
+// RecordClassRefund godoc
+// @Summary Record class refund (owner)
+// @Description Record that the club refunded the payment of a cancelled single class enrollment. Paid single enrollments are flagged for refund when the student or the club cancels them
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Enrollment ID"
+// @Success 200 {object} models.APIResponse{data=models.ClassEnrollment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/classes/enrollments/{id}/refund [put]
+func (h *ClassHandler) RecordClassRefund(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid enrollment ID", err.Error()))
+		return
+	}
+
+	enrollment, err := h.classService.RecordEnrollmentRefund(uint(id), userIDUint)
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Enrollment not found", err.Error()))
+		case "failed to fetch enrollment", "failed to record refund":
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to record refund", err.Error()))
+		default:
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot record refund", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(enrollment))
+}
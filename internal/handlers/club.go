@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClubHandler struct {
+	clubService *services.ClubService
+}
+
+func NewClubHandler(clubService *services.ClubService) *ClubHandler {
+	return &ClubHandler{clubService: clubService}
+}
+
+// SearchClubs godoc
+// @Summary Search clubs
+// @Description Search active clubs by name, city, amenity and location. Court filters (indoor, max price) match clubs with at least one such court. Each result summarizes the club's courts and price range
+// @Tags clubs
+// @Produce json
+// @Param name query string false "Club name (partial match)"
+// @Param city query string false "City"
+// @Param amenity query string false "Amenity offered by the club"
+// @Param is_indoor query bool false "Has indoor (or outdoor) courts"
+// @Param max_price query number false "Has courts up to this price per hour"
+// @Param latitude query number false "Latitude"
+// @Param longitude query number false "Longitude"
+// @Param radius query number false "Radius in km (default: 20)"
+// @Success 200 {object} models.APIResponse{data=[]models.ClubSearchResult}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /clubs [get]
+func (h *ClubHandler) SearchClubs(c *gin.Context) {
+	var req models.SearchClubsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	clubs, err := h.clubService.SearchClubs(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to search clubs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(clubs))
+}
+
+// GetClub godoc
+// @Summary Get club by ID
+// @Description Get an active club with its weekly schedule and active courts
+// @Tags clubs
+// @Produce json
+// @Param id path int true "Club ID"
+// @Success 200 {object} models.APIResponse{data=models.Club}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /clubs/{id} [get]
+func (h *ClubHandler) GetClub(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid club ID", err.Error()))
+		return
+	}
+
+	club, err := h.clubService.GetClubByID(uint(id))
+	if err != nil {
+		if err.Error() == "club not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Club not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch club", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(club))
+}
+
+// GetClubAvailability godoc
+// @Summary Get club availability
+// @Description Get the free time slots of any court of the club on a specific date. Each slot lists how many courts are free and which ones, with their price. Courts that do not offer the duration are left out
+// @Tags clubs
+// @Produce json
+// @Param id path int true "Club ID"
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Param duration query int false "Slot duration in minutes (default: first duration of the club)"
+// @Success 200 {object} models.APIResponse{data=models.ClubAvailabilityResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /clubs/{id}/availability [get]
+func (h *ClubHandler) GetClubAvailability(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid club ID", err.Error()))
+		return
+	}
+
+	dateStr := c.Query("date")
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid date format", err.Error()))
+		return
+	}
+
+	duration := 0
+	if durationStr := c.Query("duration"); durationStr != "" {
+		duration, err = strconv.Atoi(durationStr)
+		if err != nil || duration <= 0 {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid duration", "BAD_REQUEST"))
+			return
+		}
+	}
+
+	availability, err := h.clubService.GetClubAvailability(uint(id), date, duration)
+	if err != nil {
+		if err.Error() == "club not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Club not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get availability", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(availability))
+}
+
+// CreateClub godoc
+// @Summary Create club (owner)
+// @Description Create a club grouping several courts. Courts of the club without their own weekly schedule, slot durations or cancellation policy use the club's
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateClubRequest true "Club data"
+// @Success 201 {object} models.APIResponse{data=models.Club}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/clubs [post]
+func (h *ClubHandler) CreateClub(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateClubRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	club, err := h.clubService.CreateClub(ownerID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create club", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(club))
+}
+
+// GetOwnerClubs godoc
+// @Summary Get owner's clubs
+// @Description Get the clubs of the authenticated owner with their courts
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.Club}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/clubs [get]
+func (h *ClubHandler) GetOwnerClubs(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	clubs, err := h.clubService.GetOwnerClubs(ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch clubs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(clubs))
+}
+
+// UpdateClub godoc
+// @Summary Update club (owner)
+// @Description Update a club. Changes to slot durations and cancellation policy apply to the courts of the club that do not define their own
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Club ID"
+// @Param request body models.UpdateClubRequest true "Club data"
+// @Success 200 {object} models.APIResponse{data=models.Club}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/clubs/{id} [put]
+func (h *ClubHandler) UpdateClub(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid club ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateClubRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	club, err := h.clubService.UpdateClub(uint(id), ownerID, &req)
+	if err != nil {
+		if err.Error() == "club not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Club not found", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to update club", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(club))
+}
+
+// ReplaceClubBusinessHours godoc
+// @Summary Replace club business hours
+// @Description Replace the weekly schedule of a club (owner only). It applies to the courts of the club without their own weekly schedule
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Club ID"
+// @Param request body models.UpdateBusinessHoursRequest true "Weekly schedule"
+// @Success 200 {object} models.APIResponse{data=[]models.ClubBusinessHour}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/clubs/{id}/business-hours [put]
+func (h *ClubHandler) ReplaceClubBusinessHours(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	ownerID, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid club ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateBusinessHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	businessHours, err := h.clubService.ReplaceClubBusinessHours(uint(id), ownerID, &req)
+	if err != nil {
+		if err.Error() == "club not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Club not found", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to update business hours", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(businessHours))
+}
@@ -0,0 +1,613 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeagueHandler struct {
+	leagueService *services.LeagueService
+}
+
+func NewLeagueHandler(leagueService *services.LeagueService) *LeagueHandler {
+	return &LeagueHandler{leagueService: leagueService}
+}
+
+// GetLeagues godoc
+// @Summary Get leagues
+// @Description Get all leagues with their divisions, latest seasons first
+// @Tags leagues
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=[]models.League}
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues [get]
+func (h *LeagueHandler) GetLeagues(c *gin.Context) {
+	leagues, err := h.leagueService.GetLeagues()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch leagues", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(leagues))
+}
+
+// GetLeagueByID godoc
+// @Summary Get league by ID
+// @Description Get a league with its divisions and the teams registered in each one
+// @Tags leagues
+// @Produce json
+// @Param id path int true "League ID"
+// @Success 200 {object} models.APIResponse{data=models.League}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id} [get]
+func (h *LeagueHandler) GetLeagueByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return
+	}
+
+	league, err := h.leagueService.GetLeagueByID(uint(id))
+	if err != nil {
+		if err.Error() == "league not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("League not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch league", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(league))
+}
+
+// GetLeagueFixtures godoc
+// @Summary Get league fixtures
+// @Description Get the fixtures of a league, optionally filtered by division and round
+// @Tags leagues
+// @Produce json
+// @Param id path int true "League ID"
+// @Param division_id query int false "Division ID"
+// @Param round query int false "Round"
+// @Success 200 {object} models.APIResponse{data=[]models.LeagueFixture}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/fixtures [get]
+func (h *LeagueHandler) GetLeagueFixtures(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return
+	}
+
+	var filters models.GetLeagueFixturesRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	fixtures, err := h.leagueService.GetFixtures(uint(id), &filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch fixtures", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(fixtures))
+}
+
+// GetLeagueStandings godoc
+// @Summary Get league standings
+// @Description Get the standings of every division from confirmed results, with the promotion and relegation positions marked
+// @Tags leagues
+// @Produce json
+// @Param id path int true "League ID"
+// @Success 200 {object} models.APIResponse{data=models.LeagueStandingsResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/standings [get]
+func (h *LeagueHandler) GetLeagueStandings(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return
+	}
+
+	standings, err := h.leagueService.GetStandings(uint(id))
+	if err != nil {
+		if err.Error() == "league not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("League not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch standings", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(standings))
+}
+
+// RegisterLeagueTeam godoc
+// @Summary Register team in league
+// @Description Register the authenticated user and a partner as a team in a division while registration is open
+// @Tags leagues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param request body models.RegisterLeagueTeamRequest true "Registration data"
+// @Success 201 {object} models.APIResponse{data=models.LeagueTeam}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/teams [post]
+func (h *LeagueHandler) RegisterLeagueTeam(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return
+	}
+
+	var req models.RegisterLeagueTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	team, err := h.leagueService.RegisterTeam(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "league not found", "division not found", "partner not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+		case "player already registered":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot register in league", err.Error()))
+		case "league registration is closed", "a partner is required":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot register in league", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to register in league", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(team))
+}
+
+// BookLeagueFixture godoc
+// @Summary Book league fixture
+// @Description Book a court of the league organizer to play a fixture before its round deadline. The booking is made by the authenticated player, split between the four players, who are added as participants
+// @Tags leagues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param fixtureId path int true "Fixture ID"
+// @Param request body models.BookLeagueFixtureRequest true "Booking data"
+// @Success 201 {object} models.APIResponse{data=models.LeagueFixture}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/fixtures/{fixtureId}/booking [post]
+func (h *LeagueHandler) BookLeagueFixture(c *gin.Context) {
+	userIDUint, leagueID, fixtureID, ok := leagueFixtureParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.BookLeagueFixtureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	fixture, err := h.leagueService.BookFixture(leagueID, fixtureID, userIDUint, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.As(err, &ruleErr) {
+			status := http.StatusBadRequest
+			if ruleErr.Conflict {
+				status = http.StatusConflict
+			}
+			c.JSON(status, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "fixture already scheduled" || err.Error() == "time slot not available" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot book fixture", err.Error()))
+		} else {
+			writeLeagueFixtureError(c, err, "Failed to book fixture")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(fixture))
+}
+
+// ReportLeagueResult godoc
+// @Summary Report league result
+// @Description Report the score of a fixture (team1 is the home team). The result counts for the standings once the opponent confirms it
+// @Tags leagues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param fixtureId path int true "Fixture ID"
+// @Param request body models.ReportLeagueResultRequest true "Set scores"
+// @Success 200 {object} models.APIResponse{data=models.LeagueFixture}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/fixtures/{fixtureId}/result [post]
+func (h *LeagueHandler) ReportLeagueResult(c *gin.Context) {
+	userIDUint, leagueID, fixtureID, ok := leagueFixtureParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportLeagueResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	fixture, err := h.leagueService.ReportResult(leagueID, fixtureID, userIDUint, &req)
+	if err != nil {
+		if err.Error() == "result already confirmed" || err.Error() == "result already reported by the opponent" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot report result", err.Error()))
+		} else {
+			writeLeagueFixtureError(c, err, "Failed to report result")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(fixture))
+}
+
+// ConfirmLeagueResult godoc
+// @Summary Confirm league result
+// @Description Confirm the result reported by the opposing team
+// @Tags leagues
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param fixtureId path int true "Fixture ID"
+// @Success 200 {object} models.APIResponse{data=models.LeagueFixture}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/fixtures/{fixtureId}/confirm [put]
+func (h *LeagueHandler) ConfirmLeagueResult(c *gin.Context) {
+	userIDUint, leagueID, fixtureID, ok := leagueFixtureParams(c)
+	if !ok {
+		return
+	}
+
+	fixture, err := h.leagueService.ConfirmResult(leagueID, fixtureID, userIDUint)
+	if err != nil {
+		writeLeagueFixtureError(c, err, "Failed to confirm result")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(fixture))
+}
+
+// DisputeLeagueResult godoc
+// @Summary Dispute league result
+// @Description Reject the result reported by the opposing team. The league organizer is notified and sets the final result
+// @Tags leagues
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param fixtureId path int true "Fixture ID"
+// @Success 200 {object} models.APIResponse{data=models.LeagueFixture}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /leagues/{id}/fixtures/{fixtureId}/dispute [put]
+func (h *LeagueHandler) DisputeLeagueResult(c *gin.Context) {
+	userIDUint, leagueID, fixtureID, ok := leagueFixtureParams(c)
+	if !ok {
+		return
+	}
+
+	fixture, err := h.leagueService.DisputeResult(leagueID, fixtureID, userIDUint)
+	if err != nil {
+		writeLeagueFixtureError(c, err, "Failed to dispute result")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(fixture))
+}
+
+// CreateLeague godoc
+// @Summary Create league (owner)
+// @Description Create a league with its divisions, ordered from the top division down, and the points rules for the standings
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateLeagueRequest true "League data"
+// @Success 201 {object} models.APIResponse{data=models.League}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/leagues [post]
+func (h *LeagueHandler) CreateLeague(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateLeagueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	league, err := h.leagueService.CreateLeague(userIDUint, &req)
+	if err != nil {
+		if err.Error() == "failed to create league" {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create league", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league data", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(league))
+}
+
+// GetOwnerLeagues godoc
+// @Summary Get owner leagues
+// @Description Get the leagues of the authenticated owner with their divisions
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.League}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/leagues [get]
+func (h *LeagueHandler) GetOwnerLeagues(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	leagues, err := h.leagueService.GetOwnerLeagues(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch leagues", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(leagues))
+}
+
+// StartLeague godoc
+// @Summary Start league (owner)
+// @Description Close registration and generate a round-robin fixture list per division. Each round must be played within round_days
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Success 200 {object} models.APIResponse{data=models.League}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/leagues/{id}/start [post]
+func (h *LeagueHandler) StartLeague(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return
+	}
+
+	league, err := h.leagueService.StartLeague(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "league not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("League not found", err.Error()))
+		} else if err.Error() == "league already started" || err.Error() == "each division needs at least 2 teams" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot start league", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to start league", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(league))
+}
+
+// RecordLeagueResult godoc
+// @Summary Record league result (owner)
+// @Description Set or correct the result of a fixture, for example to settle a dispute. The result is confirmed directly
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param fixtureId path int true "Fixture ID"
+// @Param request body models.ReportLeagueResultRequest true "Set scores"
+// @Success 200 {object} models.APIResponse{data=models.LeagueFixture}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/leagues/{id}/fixtures/{fixtureId}/result [put]
+func (h *LeagueHandler) RecordLeagueResult(c *gin.Context) {
+	userIDUint, leagueID, fixtureID, ok := leagueFixtureParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportLeagueResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	fixture, err := h.leagueService.OwnerRecordResult(leagueID, fixtureID, userIDUint, &req)
+	if err != nil {
+		writeLeagueFixtureError(c, err, "Failed to record result")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(fixture))
+}
+
+// StartNextSeason godoc
+// @Summary Start next league season (owner)
+// @Description Finish the current season and create the next one with the same divisions and rules. Top teams of each division are promoted and bottom teams relegated
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "League ID"
+// @Param request body models.NextSeasonRequest true "Next season data"
+// @Success 201 {object} models.APIResponse{data=models.League}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/leagues/{id}/next-season [post]
+func (h *LeagueHandler) StartNextSeason(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return
+	}
+
+	var req models.NextSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	league, err := h.leagueService.StartNextSeason(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "league not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("League not found", err.Error()))
+		case "invalid start date format", "league is not in progress", "next season must start after the current one":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot start next season", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to start next season", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(league))
+}
+
+// leagueFixtureParams lee el usuario autenticado y los IDs de liga y partido de la ruta
+func leagueFixtureParams(c *gin.Context) (uint, uint, uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return 0, 0, 0, false
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return 0, 0, 0, false
+	}
+
+	leagueID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid league ID", err.Error()))
+		return 0, 0, 0, false
+	}
+
+	fixtureID, err := strconv.ParseUint(c.Param("fixtureId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid fixture ID", err.Error()))
+		return 0, 0, 0, false
+	}
+
+	return userIDUint, uint(leagueID), uint(fixtureID), true
+}
+
+// writeLeagueFixtureError traduce los errores comunes de las operaciones sobre un partido de liga
+func writeLeagueFixtureError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "league not found", "fixture not found", "court not found":
+		c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+	case "only players of this fixture can do this", "the result must be confirmed by the opponent":
+		c.JSON(http.StatusForbidden, models.NewErrorResponse("Access denied", err.Error()))
+	case "failed to fetch fixture", "failed to fetch league", "failed to fetch users", "failed to add participants",
+		"failed to report result", "failed to update result", "failed to record result", "failed to load fixture":
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(message, err.Error()))
+	default:
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(message, err.Error()))
+	}
+}
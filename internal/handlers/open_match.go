@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublishOpenMatch godoc
+// @Summary Publish booking as open match
+// @Description Publish a booking of the organizer as an open match looking for players, with a skill level range (1-7) and the number of free spots
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.PublishOpenMatchRequest true "Open match data"
+// @Success 201 {object} models.APIResponse{data=models.OpenMatchResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/open-match [post]
+func (h *BookingHandler) PublishOpenMatch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var req models.PublishOpenMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	match, err := h.bookingService.PublishOpenMatch(uint(id), userIDUint, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.As(err, &ruleErr) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "invalid level range" || err.Error() == "not enough spots in this booking" || err.Error() == "booking cannot be published in its current status" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot publish open match", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to publish open match", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(match))
+}
+
+// CloseOpenMatch godoc
+// @Summary Close open match
+// @Description Stop looking for players; players who already joined stay in the booking
+// @Tags matches
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/open-match [delete]
+func (h *BookingHandler) CloseOpenMatch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	if err := h.bookingService.CloseOpenMatch(uint(id), userIDUint); err != nil {
+		if err.Error() == "open match not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Open match not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to close open match", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Open match closed successfully"}))
+}
+
+// SearchOpenMatches godoc
+// @Summary Search open matches
+// @Description Search upcoming open matches with free spots near a location, by date and skill level. Without a level, the level of the player's rating is used
+// @Tags matches
+// @Produce json
+// @Security BearerAuth
+// @Param lat query number false "Latitude"
+// @Param lng query number false "Longitude"
+// @Param radius query number false "Radius in kilometers (default: 20)"
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Param level query number false "Player level (1-7), defaults to the level of the player's rating"
+// @Success 200 {object} models.APIResponse{data=[]models.OpenMatchResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /matches/open [get]
+func (h *BookingHandler) SearchOpenMatches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.SearchOpenMatchesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	matches, err := h.bookingService.SearchOpenMatches(userIDUint, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to search open matches", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(matches))
+}
+
+// JoinOpenMatch godoc
+// @Summary Join open match
+// @Description Join an open match as a participant of the booking. The player's rating level must be within the match range. The response includes the share of the price each player pays; while the booking has no payments its price is split among the court players so the player can pay that share
+// @Tags matches
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Open match ID"
+// @Success 200 {object} models.APIResponse{data=models.OpenMatchResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /matches/{id}/join [post]
+func (h *BookingHandler) JoinOpenMatch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid open match ID", err.Error()))
+		return
+	}
+
+	match, err := h.bookingService.JoinOpenMatch(uint(id), userIDUint)
+	if err != nil {
+		switch err.Error() {
+		case "open match not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Open match not found", err.Error()))
+		case "match is full", "already in this match":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot join match", err.Error()))
+		case "match is not open", "player level out of range for this match":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot join match", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to join match", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(match))
+}
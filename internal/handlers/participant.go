@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InviteParticipant godoc
+// @Summary Invite a player to a booking
+// @Description Invite a registered user (user_id) or an email to the booking. The organizer plus active invitations cannot exceed the court max players
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.InviteParticipantRequest true "Invitee"
+// @Success 201 {object} models.APIResponse{data=models.BookingParticipant}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/participants [post]
+func (h *BookingHandler) InviteParticipant(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var req models.InviteParticipantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	participant, err := h.bookingService.InviteParticipant(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "booking not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		case "user not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("User not found", err.Error()))
+		case "booking is full", "player already invited":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot invite player", err.Error()))
+		case "user_id or email is required", "cannot invite the organizer", "booking cannot receive participants in its current status":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot invite player", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to invite player", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(participant))
+}
+
+// GetBookingParticipants godoc
+// @Summary Get booking participants
+// @Description Get the invited and accepted players of a booking. Available to the organizer and to the participants
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=[]models.ParticipantInfo}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/participants [get]
+func (h *BookingHandler) GetBookingParticipants(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	participants, err := h.bookingService.GetBookingParticipants(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch participants", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(participants))
+}
+
+// RemoveParticipant godoc
+// @Summary Remove a participant
+// @Description Remove a player from a booking. The organizer can remove anyone; a participant can leave the booking
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param participantId path int true "Participant ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/participants/{participantId} [delete]
+func (h *BookingHandler) RemoveParticipant(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	participantID, err := strconv.ParseUint(c.Param("participantId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid participant ID", err.Error()))
+		return
+	}
+
+	if err := h.bookingService.RemoveParticipant(uint(id), uint(participantID), userIDUint); err != nil {
+		if err.Error() == "participant not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Participant not found", err.Error()))
+		} else if err.Error() == "participant already removed" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot remove participant", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to remove participant", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Participant removed successfully"}))
+}
+
+// GetInvitations godoc
+// @Summary Get my invitations
+// @Description Get the booking invitations of the authenticated user that are waiting for an answer
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.BookingParticipant}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /invitations [get]
+func (h *BookingHandler) GetInvitations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	invitations, err := h.bookingService.GetUserInvitations(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch invitations", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(invitations))
+}
+
+// AcceptInvitation godoc
+// @Summary Accept invitation
+// @Description Accept an invitation to play in a booking
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} models.APIResponse{data=models.BookingParticipant}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /invitations/{id}/accept [put]
+func (h *BookingHandler) AcceptInvitation(c *gin.Context) {
+	h.respondInvitation(c, true)
+}
+
+// DeclineInvitation godoc
+// @Summary Decline invitation
+// @Description Decline an invitation to play in a booking
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} models.APIResponse{data=models.BookingParticipant}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /invitations/{id}/decline [put]
+func (h *BookingHandler) DeclineInvitation(c *gin.Context) {
+	h.respondInvitation(c, false)
+}
+
+func (h *BookingHandler) respondInvitation(c *gin.Context, accept bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid invitation ID", err.Error()))
+		return
+	}
+
+	participant, err := h.bookingService.RespondInvitation(uint(id), userIDUint, accept)
+	if err != nil {
+		if err.Error() == "invitation not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Invitation not found", err.Error()))
+		} else if err.Error() == "invitation already answered" || err.Error() == "booking is no longer active" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot answer invitation", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to answer invitation", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(participant))
+}
@@ -1,177 +1,462 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"backend-padel-go/internal/middleware"
-	"backend-padel-go/internal/models"
-	"backend-padel-go/internal/services"
-
-	"github.com/gin-gonic/gin"
-)
-
-type PaymentHandler struct {
-	paymentService *services.PaymentService
-}
-
-func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
-	return &PaymentHandler{paymentService: paymentService}
-}
-
-// CreatePreference godoc
-// @Summary Create payment preference
-// @Description Create a MercadoPago payment preference
-// @Tags payments
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body models.CreatePreferenceRequest true "Create preference request"
-// @Success 201 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /payments/preference [post]
-func (h *PaymentHandler) CreatePreference(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
-		return
-	}
-
-	var req models.CreatePreferenceRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
-		return
-	}
-
-	preference, err := h.paymentService.CreatePreference(userIDUint, &req)
-	if err != nil {
-		if err.Error() == "booking not found" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
-		} else if err.Error() == "payment already exists for this booking" {
-			c.JSON(http.StatusConflict, models.NewErrorResponse("Payment already exists for this booking", err.Error()))
-		} else {
-			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create preference", err.Error()))
-		}
-		return
-	}
-
-	c.JSON(http.StatusCreated, models.NewSuccessResponse(preference))
-}
-
-// GetPaymentStatus godoc
-// @Summary Get payment status
-// @Description Get the status of a payment
-// @Tags payments
-// @Produce json
-// @Security BearerAuth
-// @Param id path string true "Payment ID"
-// @Success 200 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 404 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /payments/{id}/status [get]
-func (h *PaymentHandler) GetPaymentStatus(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
-		return
-	}
-
-	paymentID := c.Param("id")
-	if paymentID == "" {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Payment ID is required", "BAD_REQUEST"))
-		return
-	}
-
-	status, err := h.paymentService.GetPaymentStatus(userIDUint, paymentID)
-	if err != nil {
-		if err.Error() == "payment not found" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse("Payment not found", err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get payment status", err.Error()))
-		}
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(status))
-}
-
-// HandleWebhook godoc
-// @Summary Handle MercadoPago webhook
-// @Description Handle webhook notifications from MercadoPago
-// @Tags payments
-// @Accept json
-// @Produce json
-// @Param request body models.WebhookRequest true "Webhook request"
-// @Success 200 {object} models.APIResponse
-// @Failure 400 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /payments/webhook [post]
-func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
-	var req models.WebhookRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
-		return
-	}
-
-	// Convertir data a map[string]interface{}
-	data := make(map[string]interface{})
-	data["id"] = req.Data.ID
-
-	err := h.paymentService.HandleWebhook(req.Action, data)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to handle webhook", err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"received": true}))
-}
-
-// GetPaymentStatistics godoc
-// @Summary Get payment statistics
-// @Description Get payment statistics (admin only)
-// @Tags payments
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} models.APIResponse
-// @Failure 401 {object} models.APIResponse
-// @Failure 403 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /payments/statistics [get]
-func (h *PaymentHandler) GetPaymentStatistics(c *gin.Context) {
-	// Verificar que el usuario es admin
-	userRole, exists := c.Get("user_role")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
-		return
-	}
-
-	roleStr, ok := userRole.(string)
-	if !ok || roleStr != "admin" {
-		c.JSON(http.StatusForbidden, models.NewErrorResponse("Admin access required", "FORBIDDEN"))
-		return
-	}
-
-	statistics, err := h.paymentService.GetPaymentStatistics()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get payment statistics", err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, models.NewSuccessResponse(statistics))
-}
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/middleware"
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+}
+
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// CreatePreference godoc
+// @Summary Create payment preference
+// @Description Create a MercadoPago payment preference. For bookings split among players each call creates the preference for one share; pay_remainder covers every unassigned share
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreatePreferenceRequest true "Create preference request"
+// @Success 201 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /payments/preference [post]
+func (h *PaymentHandler) CreatePreference(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreatePreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	preference, err := h.paymentService.CreatePreference(userIDUint, &req)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else if err.Error() == "payment already exists for this booking" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Payment already exists for this booking", err.Error()))
+		} else if err.Error() == "only the organizer can pay this booking" {
+			c.JSON(http.StatusForbidden, models.NewErrorResponse("Cannot pay this booking", err.Error()))
+		} else if err.Error() == "all shares of this booking are already assigned" || err.Error() == "payer already has a pending payment for this booking" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot create payment for this share", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to create preference", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(preference))
+}
+
+// GetBookingPayments godoc
+// @Summary Get booking payments
+// @Description Get the payments of a booking with the paid, pending and unassigned amounts (one payment per share for split bookings)
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.BookingPaymentSummary}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/payments [get]
+func (h *PaymentHandler) GetBookingPayments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	summary, err := h.paymentService.GetBookingPayments(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch booking payments", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(summary))
+}
+
+// GetPaymentStatus godoc
+// @Summary Get payment status
+// @Description Get the status of a payment
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /payments/{id}/status [get]
+func (h *PaymentHandler) GetPaymentStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	paymentID := c.Param("id")
+	if paymentID == "" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Payment ID is required", "BAD_REQUEST"))
+		return
+	}
+
+	status, err := h.paymentService.GetPaymentStatus(userIDUint, paymentID)
+	if err != nil {
+		if err.Error() == "payment not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Payment not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get payment status", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(status))
+}
+
+// HandleWebhook godoc
+// @Summary Handle MercadoPago webhook
+// @Description Handle webhook notifications from MercadoPago. The x-signature header is verified with the webhook secret and repeated notifications are processed only once
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param x-signature header string true "MercadoPago signature (ts=...,v1=...)"
+// @Param x-request-id header string false "MercadoPago request ID"
+// @Param request body models.WebhookRequest true "Webhook request"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	var req models.WebhookRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	// MercadoPago envía el id del recurso también como query param y es el que se usa en la firma
+	dataID := c.Query("data.id")
+	if dataID == "" {
+		dataID = req.Data.ID
+	}
+
+	if err := h.paymentService.VerifyWebhookSignature(c.GetHeader("x-signature"), c.GetHeader("x-request-id"), dataID); err != nil {
+		if err.Error() == "invalid webhook signature" || err.Error() == "expired webhook signature" {
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse("Invalid webhook signature", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to handle webhook", err.Error()))
+		}
+		return
+	}
+
+	eventID := strings.Trim(string(req.ID), `"`)
+	if eventID == "null" {
+		eventID = ""
+	}
+
+	err = h.paymentService.ReceiveWebhook(eventID, req.Action, dataID, string(payload))
+	if err != nil {
+		if err.Error() == "invalid payment ID in webhook data" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid webhook data", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to handle webhook", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"received": true}))
+}
+
+// GetPaymentStatistics godoc
+// @Summary Get payment statistics
+// @Description Get payment statistics (admin only)
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /payments/statistics [get]
+func (h *PaymentHandler) GetPaymentStatistics(c *gin.Context) {
+	// Verificar que el usuario es admin
+	userRole, exists := c.Get("user_role")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	roleStr, ok := userRole.(string)
+	if !ok || roleStr != "admin" {
+		c.JSON(http.StatusForbidden, models.NewErrorResponse("Admin access required", "FORBIDDEN"))
+		return
+	}
+
+	statistics, err := h.paymentService.GetPaymentStatistics()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get payment statistics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(statistics))
+}
+
+// RefundPayment godoc
+// @Summary Refund payment
+// @Description Fully or partially refund an approved payment (court owner or admin). Omitting the amount refunds the remaining balance
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body models.CreateRefundRequest true "Refund request"
+// @Success 201 {object} models.APIResponse{data=models.Refund}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Router /owner/payments/{id}/refunds [post]
+func (h *PaymentHandler) RefundPayment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	role, _ := c.Get("user_role")
+	roleStr, _ := role.(string)
+
+	paymentID := c.Param("id")
+	if paymentID == "" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Payment ID is required", "BAD_REQUEST"))
+		return
+	}
+
+	var req models.CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	refund, err := h.paymentService.RefundPayment(paymentID, userIDUint, roleStr, &req)
+	if err != nil {
+		switch err.Error() {
+		case "payment not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Payment not found", err.Error()))
+		case "payment is not refundable", "invalid refund amount", "refund amount exceeds refundable balance":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot refund payment", err.Error()))
+		case "failed to fetch payment", "failed to create refund", "failed to update refund", "failed to update payment", "failed to update booking status":
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to refund payment", err.Error()))
+		default:
+			// El proveedor rechazó o no respondió la devolución
+			c.JSON(http.StatusBadGateway, models.NewErrorResponse("Failed to refund payment", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(refund))
+}
+
+// GetPaymentRefunds godoc
+// @Summary Get payment refunds
+// @Description Get the refunds of a payment (court owner or admin)
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} models.APIResponse{data=[]models.Refund}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/payments/{id}/refunds [get]
+func (h *PaymentHandler) GetPaymentRefunds(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	role, _ := c.Get("user_role")
+	roleStr, _ := role.(string)
+
+	refunds, err := h.paymentService.GetPaymentRefunds(c.Param("id"), userIDUint, roleStr)
+	if err != nil {
+		if err.Error() == "payment not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Payment not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get refunds", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(refunds))
+}
+
+// SandboxEnabled indica si la simulación de pagos del proveedor sandbox está disponible
+func (h *PaymentHandler) SandboxEnabled() bool {
+	return h.paymentService.SandboxEnabled()
+}
+
+// SimulateSandboxPayment godoc
+// @Summary Simulate sandbox payment
+// @Description Approve or reject a payment preference of the sandbox provider and process it as a webhook notification (only with PAYMENT_PROVIDER=sandbox)
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param preferenceId path string true "Sandbox preference ID"
+// @Param request body models.SandboxPaymentRequest true "Simulated payment status"
+// @Success 200 {object} models.APIResponse{data=models.PaymentStatusResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /payments/sandbox/{preferenceId} [post]
+func (h *PaymentHandler) SimulateSandboxPayment(c *gin.Context) {
+	var req models.SandboxPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	status, err := h.paymentService.SimulateSandboxPayment(c.Param("preferenceId"), &req)
+	if err != nil {
+		switch err.Error() {
+		case "sandbox payments are disabled", "sandbox preference not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Sandbox preference not found", err.Error()))
+		case "invalid sandbox payment status":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid payment status", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to simulate payment", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(status))
+}
+
+// GetReconciliationReport godoc
+// @Summary Get payment reconciliation report
+// @Description List the differences found between local pending payments and the payment provider (admin only)
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param since query string false "Start date (YYYY-MM-DD), defaults to the last 7 days"
+// @Success 200 {object} models.APIResponse{data=[]models.PaymentReconciliation}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/payments/reconciliation [get]
+func (h *PaymentHandler) GetReconciliationReport(c *gin.Context) {
+	since := time.Now().AddDate(0, 0, -7)
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid date format", err.Error()))
+			return
+		}
+		since = parsed
+	}
+
+	mismatches, err := h.paymentService.GetReconciliationReport(since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get reconciliation report", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(mismatches))
+}
+
+// ReconcilePayments godoc
+// @Summary Reconcile pending payments
+// @Description Run the reconciliation of stale pending payments against the payment provider now (admin only). Stale pending refunds already executed by the provider are completed; those with an unknown outcome are reported
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.ReconciliationReport}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /admin/payments/reconcile [post]
+func (h *PaymentHandler) ReconcilePayments(c *gin.Context) {
+	cfg := config.Load()
+
+	report, err := h.paymentService.ReconcilePendingPayments(time.Duration(cfg.Payment.ReconcileAfterMinutes) * time.Minute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to reconcile payments", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(report))
+}
@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlayerHandler struct {
+	playerService *services.PlayerService
+}
+
+func NewPlayerHandler(playerService *services.PlayerService) *PlayerHandler {
+	return &PlayerHandler{playerService: playerService}
+}
+
+// GetMyProfile godoc
+// @Summary Get my player profile
+// @Description Get the padel profile of the authenticated user: declared level, preferred side, dominant hand and rating
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.PlayerProfileResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /players/me [get]
+func (h *PlayerHandler) GetMyProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	profile, err := h.playerService.GetProfile(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch player profile", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(profile))
+}
+
+// UpdateMyProfile godoc
+// @Summary Update my player profile
+// @Description Update the declared level (1-7), preferred side (drive, reves, both) and dominant hand (right, left). Until the first match is recorded, the declared level sets the rating
+// @Tags players
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdatePlayerProfileRequest true "Profile data"
+// @Success 200 {object} models.APIResponse{data=models.PlayerProfileResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /players/me [put]
+func (h *PlayerHandler) UpdateMyProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.UpdatePlayerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	profile, err := h.playerService.UpdateProfile(userIDUint, &req)
+	if err != nil {
+		if err.Error() == "level must be between 1 and 7" || err.Error() == "invalid preferred side" || err.Error() == "invalid dominant hand" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid profile data", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update player profile", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(profile))
+}
+
+// GetMyRatingHistory godoc
+// @Summary Get my rating history
+// @Description Get the latest rating changes of the authenticated user, most recent first
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.RatingHistory}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /players/me/rating-history [get]
+func (h *PlayerHandler) GetMyRatingHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	history, err := h.playerService.GetRatingHistory(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch rating history", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(history))
+}
+
+// GetPlayerProfile godoc
+// @Summary Get player profile
+// @Description Get the padel profile and rating of a player
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.PlayerProfileResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /players/{id} [get]
+func (h *PlayerHandler) GetPlayerProfile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid user ID", err.Error()))
+		return
+	}
+
+	profile, err := h.playerService.GetProfile(uint(id))
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Player not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch player profile", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(profile))
+}
+
+// SuggestPartners godoc
+// @Summary Suggest partners
+// @Description Suggest players with a similar rating (up to one level apart) who play on the complementary side
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Param side query string false "Side of the partner (drive, reves), defaults to the complementary side of the player"
+// @Param limit query int false "Maximum number of suggestions (default: 20)"
+// @Success 200 {object} models.APIResponse{data=[]models.PlayerProfileResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /players/partners [get]
+func (h *PlayerHandler) SuggestPartners(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.SuggestPartnersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	partners, err := h.playerService.SuggestPartners(userIDUint, &req)
+	if err != nil {
+		if err.Error() == "invalid preferred side" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid side", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to suggest partners", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(partners))
+}
+
+// RecordMatchResult godoc
+// @Summary Record match result
+// @Description Report the result of the match played in a completed booking. Both teams must be players of the booking. The rating of the players is updated once a player of the opposing team confirms it
+// @Tags players
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body models.RecordMatchResultRequest true "Teams and set scores"
+// @Success 201 {object} models.APIResponse{data=models.MatchResult}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/result [post]
+func (h *PlayerHandler) RecordMatchResult(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var req models.RecordMatchResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	result, err := h.playerService.RecordMatchResult(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "booking not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Booking not found", err.Error()))
+		case "only players of this booking can record the result":
+			c.JSON(http.StatusForbidden, models.NewErrorResponse("Access denied", err.Error()))
+		case "match result already recorded", "result already reported by the opponent":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Match result already recorded", err.Error()))
+		case "teams must have the same number of players (1 or 2)", "invalid score", "match result can only be recorded for completed bookings",
+			"player is not part of this booking", "player cannot be in both teams", "reporter must be in one of the teams":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid match result", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to record match result", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(result))
+}
+
+// GetMatchResult godoc
+// @Summary Get match result
+// @Description Get the recorded result of the match played in a booking
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.MatchResult}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/result [get]
+func (h *PlayerHandler) GetMatchResult(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	result, err := h.playerService.GetMatchResult(uint(id), userIDUint)
+	if err != nil {
+		if err.Error() == "booking not found" || err.Error() == "match result not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Match result not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch match result", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
+
+// ConfirmMatchResult godoc
+// @Summary Confirm match result
+// @Description Confirm the result reported by the opposing team and update the rating of the players
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.MatchResult}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/result/confirm [put]
+func (h *PlayerHandler) ConfirmMatchResult(c *gin.Context) {
+	h.respondMatchResult(c, true)
+}
+
+// DisputeMatchResult godoc
+// @Summary Dispute match result
+// @Description Reject the result reported by the opposing team. Ratings are not updated; any player can report the result again
+// @Tags players
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.MatchResult}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /bookings/{id}/result/dispute [put]
+func (h *PlayerHandler) DisputeMatchResult(c *gin.Context) {
+	h.respondMatchResult(c, false)
+}
+
+func (h *PlayerHandler) respondMatchResult(c *gin.Context, confirm bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	var result *models.MatchResult
+	if confirm {
+		result, err = h.playerService.ConfirmMatchResult(uint(id), userIDUint)
+	} else {
+		result, err = h.playerService.DisputeMatchResult(uint(id), userIDUint)
+	}
+	if err != nil {
+		switch err.Error() {
+		case "match result not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Match result not found", err.Error()))
+		case "the result must be confirmed by the opponent":
+			c.JSON(http.StatusForbidden, models.NewErrorResponse("Access denied", err.Error()))
+		case "no result to confirm":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid match result", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update match result", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
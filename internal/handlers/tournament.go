@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TournamentHandler struct {
+	tournamentService *services.TournamentService
+}
+
+func NewTournamentHandler(tournamentService *services.TournamentService) *TournamentHandler {
+	return &TournamentHandler{tournamentService: tournamentService}
+}
+
+// GetTournaments godoc
+// @Summary Get tournaments
+// @Description Get tournaments, optionally filtered by status and format. Cancelled tournaments are excluded unless requested
+// @Tags tournaments
+// @Produce json
+// @Param status query string false "Status (registration, scheduled, in_progress, finished, cancelled)"
+// @Param format query string false "Format (elimination, groups, americano, mexicano)"
+// @Success 200 {object} models.APIResponse{data=[]models.Tournament}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /tournaments [get]
+func (h *TournamentHandler) GetTournaments(c *gin.Context) {
+	var filters models.GetTournamentsRequest
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid query parameters", err.Error()))
+		return
+	}
+
+	tournaments, err := h.tournamentService.GetTournaments(&filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch tournaments", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tournaments))
+}
+
+// GetTournamentByID godoc
+// @Summary Get tournament by ID
+// @Description Get a tournament with its courts and registered pairs
+// @Tags tournaments
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.APIResponse{data=models.Tournament}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /tournaments/{id} [get]
+func (h *TournamentHandler) GetTournamentByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	tournament, err := h.tournamentService.GetTournamentByID(uint(id))
+	if err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Tournament not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch tournament", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tournament))
+}
+
+// GetTournamentBracket godoc
+// @Summary Get tournament bracket
+// @Description Get the matches of the tournament grouped by round, with court, time and result
+// @Tags tournaments
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.APIResponse{data=models.TournamentBracketResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /tournaments/{id}/bracket [get]
+func (h *TournamentHandler) GetTournamentBracket(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	bracket, err := h.tournamentService.GetBracket(uint(id))
+	if err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Tournament not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch bracket", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(bracket))
+}
+
+// GetTournamentStandings godoc
+// @Summary Get tournament standings
+// @Description Get the standings of the tournament: matches won per pair (by group in the groups format), or games won per player in americano and mexicano
+// @Tags tournaments
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.APIResponse{data=models.TournamentStandingsResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /tournaments/{id}/standings [get]
+func (h *TournamentHandler) GetTournamentStandings(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	standings, err := h.tournamentService.GetStandings(uint(id))
+	if err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Tournament not found", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch standings", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(standings))
+}
+
+// RegisterTournament godoc
+// @Summary Register in tournament
+// @Description Register the authenticated user with a partner. In americano and mexicano players register individually
+// @Tags tournaments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tournament ID"
+// @Param request body models.RegisterTournamentRequest true "Registration data"
+// @Success 201 {object} models.APIResponse{data=models.TournamentPair}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /tournaments/{id}/register [post]
+func (h *TournamentHandler) RegisterTournament(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	var req models.RegisterTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	pair, err := h.tournamentService.RegisterPair(uint(id), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "tournament not found", "partner not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Not found", err.Error()))
+		case "player already registered", "tournament is full":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Cannot register in tournament", err.Error()))
+		case "tournament registration is closed", "this format registers individual players", "a partner is required":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot register in tournament", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to register in tournament", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(pair))
+}
+
+// WithdrawTournament godoc
+// @Summary Withdraw from tournament
+// @Description Withdraw the registration of the authenticated user (and their partner) while registration is open
+// @Tags tournaments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /tournaments/{id}/register [delete]
+func (h *TournamentHandler) WithdrawTournament(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	if err := h.tournamentService.WithdrawPair(uint(id), userIDUint); err != nil {
+		if err.Error() == "tournament not found" || err.Error() == "registration not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Registration not found", err.Error()))
+		} else if err.Error() == "tournament registration is closed" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot withdraw from tournament", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to withdraw from tournament", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Registration withdrawn successfully"}))
+}
+
+// CreateTournament godoc
+// @Summary Create tournament (owner)
+// @Description Create a tournament on courts of the authenticated owner. Matches are scheduled within the daily time window starting on start_date. match_minutes must be an allowed slot duration of every court, and on start_date every court must be open for at least one match within the window
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateTournamentRequest true "Tournament data"
+// @Success 201 {object} models.APIResponse{data=models.Tournament}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/tournaments [post]
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	tournament, err := h.tournamentService.CreateTournament(userIDUint, &req)
+	if err != nil {
+		if err.Error() == "court not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Court not found", err.Error()))
+		} else if err.Error() == "failed to fetch courts" || err.Error() == "failed to create tournament" {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create tournament", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament data", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(tournament))
+}
+
+// GetOwnerTournaments godoc
+// @Summary Get owner tournaments
+// @Description Get the tournaments of the authenticated owner with their registered pairs
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.Tournament}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/tournaments [get]
+func (h *TournamentHandler) GetOwnerTournaments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	tournaments, err := h.tournamentService.GetOwnerTournaments(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch tournaments", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tournaments))
+}
+
+// GenerateTournament godoc
+// @Summary Generate tournament bracket (owner)
+// @Description Close registration, seed the pairs by rating, generate the bracket or round-robin for the format and reserve a court slot for every match. Nothing is reserved if the courts cannot fit all matches
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.APIResponse{data=models.TournamentBracketResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/tournaments/{id}/generate [post]
+func (h *TournamentHandler) GenerateTournament(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	bracket, err := h.tournamentService.GenerateTournament(uint(id), userIDUint)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.Is(err, services.ErrTournamentUnschedulable) {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Not enough court availability", err.Error()))
+		} else if errors.As(err, &ruleErr) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "tournament not found" || err.Error() == "court not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Tournament not found", err.Error()))
+		} else if err.Error() == "tournament already generated" || err.Error() == "not enough pairs registered" ||
+			err.Error() == "each group needs at least 2 pairs" || err.Error() == "americano and mexicano need a multiple of 4 players" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot generate tournament", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to generate tournament", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(bracket))
+}
+
+// RecordTournamentResult godoc
+// @Summary Record tournament match result (owner)
+// @Description Record the score of a match. In elimination the winner advances to the next match; in mexicano the teams of the next round are formed when the round ends
+// @Tags owner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tournament ID"
+// @Param matchId path int true "Match ID"
+// @Param request body models.RecordTournamentResultRequest true "Set scores"
+// @Success 200 {object} models.APIResponse{data=models.TournamentMatch}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/tournaments/{id}/matches/{matchId}/result [put]
+func (h *TournamentHandler) RecordTournamentResult(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	matchIDStr := c.Param("matchId")
+	matchID, err := strconv.ParseUint(matchIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid match ID", err.Error()))
+		return
+	}
+
+	var req models.RecordTournamentResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	match, err := h.tournamentService.RecordResult(uint(id), uint(matchID), userIDUint, &req)
+	if err != nil {
+		switch err.Error() {
+		case "tournament not found", "match not found":
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Match not found", err.Error()))
+		case "match result already recorded":
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Match result already recorded", err.Error()))
+		case "tournament is not in progress", "match teams are not defined yet", "invalid score":
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot record match result", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to record match result", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(match))
+}
+
+// CancelTournament godoc
+// @Summary Cancel tournament (owner)
+// @Description Cancel a tournament, release the court slots of the matches not played yet and notify the players
+// @Tags owner
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /owner/tournaments/{id}/cancel [put]
+func (h *TournamentHandler) CancelTournament(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid tournament ID", err.Error()))
+		return
+	}
+
+	if err := h.tournamentService.CancelTournament(uint(id), userIDUint); err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Tournament not found", err.Error()))
+		} else if err.Error() == "tournament cannot be cancelled" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot cancel tournament", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to cancel tournament", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Tournament cancelled successfully"}))
+}
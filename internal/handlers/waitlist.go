@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	waitlistService *services.WaitlistService
+}
+
+func NewWaitlistHandler(waitlistService *services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: waitlistService}
+}
+
+// JoinWaitlist godoc
+// @Summary Join the waitlist
+// @Description Subscribe to a time range of a court on a date. When a booking in the range is cancelled or expires, the first users of the list are notified and get an exclusive time-limited claim on the slot
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.JoinWaitlistRequest true "Waitlist request"
+// @Success 201 {object} models.APIResponse{data=models.WaitlistEntry}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /waitlist [post]
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	var req models.JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	entry, err := h.waitlistService.JoinWaitlist(userIDUint, &req)
+	if err != nil {
+		var ruleErr *services.BookingRuleError
+		if errors.As(err, &ruleErr) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(ruleErr.Message, ruleErr.Code))
+		} else if err.Error() == "court not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Court not found", err.Error()))
+		} else if err.Error() == "already on the waitlist" {
+			c.JSON(http.StatusConflict, models.NewErrorResponse("Already on the waitlist", err.Error()))
+		} else {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to join waitlist", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewSuccessResponse(entry))
+}
+
+// GetUserWaitlist godoc
+// @Summary Get my waitlist entries
+// @Description Get the upcoming waitlist entries of the authenticated user, including offered slots and their claim deadline
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.WaitlistEntry}
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /waitlist [get]
+func (h *WaitlistHandler) GetUserWaitlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	entries, err := h.waitlistService.GetUserWaitlist(userIDUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to fetch waitlist", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(entries))
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave the waitlist
+// @Description Remove a waitlist entry of the authenticated user
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Waitlist entry ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /waitlist/{id} [delete]
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("User not authenticated", "UNAUTHORIZED"))
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Invalid user ID", "INTERNAL_ERROR"))
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid waitlist entry ID", err.Error()))
+		return
+	}
+
+	if err := h.waitlistService.LeaveWaitlist(uint(id), userIDUint); err != nil {
+		if err.Error() == "waitlist entry not found" {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse("Waitlist entry not found", err.Error()))
+		} else if err.Error() == "waitlist entry is no longer active" {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse("Cannot leave waitlist", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to leave waitlist", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"message": "Left waitlist successfully"}))
+}
@@ -1,84 +1,190 @@
-package models
-
-import (
-	"time"
-
-	"gorm.io/gorm"
-)
-
-type Booking struct {
-	ID         uint           `json:"id" gorm:"primaryKey"`
-	CourtID    uint           `json:"court_id" gorm:"not null"`
-	UserID     uint           `json:"user_id" gorm:"not null"`
-	Date       time.Time      `json:"date" gorm:"type:date;not null"`
-	StartTime  string         `json:"start_time" gorm:"not null" validate:"required"`
-	EndTime    string         `json:"end_time" gorm:"not null" validate:"required"`
-	Status     string         `json:"status" gorm:"default:pending" validate:"oneof=pending confirmed cancelled completed"`
-	TotalPrice float64        `json:"total_price" gorm:"type:decimal(10,2)" validate:"required,min=0"`
-	Notes      string         `json:"notes"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
-
-	// Relaciones
-	Court Court `json:"court,omitempty" gorm:"foreignKey:CourtID"`
-	User  User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
-}
-
-type CreateBookingRequest struct {
-	CourtID   uint   `json:"court_id" validate:"required"`
-	Date      string `json:"date" validate:"required"` // formato: "2024-03-20"
-	StartTime string `json:"start_time" validate:"required"` // formato: "10:00"
-	EndTime   string `json:"end_time" validate:"required"`   // formato: "11:00"
-	Notes     string `json:"notes"`
-}
-
-type BookingResponse struct {
-	ID         uint      `json:"id"`
-	CourtID    uint      `json:"court_id"`
-	UserID     uint      `json:"user_id"`
-	Date       time.Time `json:"date"`
-	StartTime  string    `json:"start_time"`
-	EndTime    string    `json:"end_time"`
-	Status     string    `json:"status"`
-	TotalPrice float64   `json:"total_price"`
-	Notes      string    `json:"notes"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	
-	// Información adicional
-	Court CourtInfo `json:"court"`
-	User  UserInfo  `json:"user"`
-}
-
-type CourtInfo struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Address     string  `json:"address"`
-	PricePerHour float64 `json:"price_per_hour"`
-	Surface     string  `json:"surface"`
-	HasLighting bool    `json:"has_lighting"`
-	IsIndoor    bool    `json:"is_indoor"`
-}
-
-type UserInfo struct {
-	ID        uint   `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Phone     string `json:"phone"`
-}
-
-type GetBookingsRequest struct {
-	UserID  *uint   `json:"user_id,omitempty"`
-	CourtID *uint   `json:"court_id,omitempty"`
-	Date    *string `json:"date,omitempty"` // formato: "2024-03-20"
-	Status  *string `json:"status,omitempty"`
-}
-
-type TimeSlot struct {
-	StartTime string `json:"start_time"`
-	EndTime   string `json:"end_time"`
-	Available bool   `json:"available"`
-	Price     float64 `json:"price,omitempty"`
-}
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Booking struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	CourtID    uint           `json:"court_id" gorm:"not null"`
+	UserID     *uint          `json:"user_id,omitempty" gorm:"index"` // nil para invitados sin cuenta cargados por el club
+	Date       time.Time      `json:"date" gorm:"type:date;not null"`
+	StartTime  string         `json:"start_time" gorm:"not null" validate:"required"`
+	EndTime    string         `json:"end_time" gorm:"not null" validate:"required"`
+	Status     string         `json:"status" gorm:"default:pending" validate:"oneof=pending confirmed cancelled completed no_show expired"`
+	Source     string         `json:"source" gorm:"default:online"` // online, phone, walk_in, tournament, class
+	GuestName  string         `json:"guest_name,omitempty"`
+	GuestPhone string         `json:"guest_phone,omitempty"`
+	CreatedBy  *uint          `json:"created_by,omitempty"` // propietario que cargó la reserva telefónica o presencial
+	SeriesID   *uint          `json:"series_id,omitempty" gorm:"index"` // turno fijo que generó la reserva
+	SplitPlayers int          `json:"split_players" gorm:"default:0"` // partes en que se divide el pago entre los jugadores; 0 = pago único
+	TotalPrice float64        `json:"total_price" gorm:"type:decimal(10,2)" validate:"required,min=0"`
+	PriceBreakdown *PriceBreakdown `json:"price_breakdown,omitempty" gorm:"type:json;serializer:json"`
+	Notes      string         `json:"notes"`
+	HoldExpiresAt *time.Time  `json:"hold_expires_at,omitempty" gorm:"index"` // vencimiento del bloqueo mientras la reserva está pendiente de pago
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Court        Court                `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	User         User                 `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Participants []BookingParticipant `json:"participants,omitempty" gorm:"foreignKey:BookingID"`
+}
+
+// BookingStatusHistory registra cada cambio de estado de una reserva con quién lo hizo y por qué
+type BookingStatusHistory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BookingID  uint      `json:"booking_id" gorm:"not null;index"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status" gorm:"not null"`
+	ActorID    *uint     `json:"actor_id,omitempty"` // nil cuando el cambio lo hizo el sistema
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateBookingRequest struct {
+	CourtID   uint   `json:"court_id" validate:"required"`
+	Date      string `json:"date" validate:"required"` // formato: "2024-03-20"
+	StartTime string `json:"start_time" validate:"required"` // formato: "10:00"
+	EndTime   string `json:"end_time" validate:"required"`   // formato: "11:00"
+	SplitPlayers int `json:"split_players,omitempty" validate:"omitempty,min=2"` // dividir el pago entre los jugadores
+	Notes     string `json:"notes"`
+}
+
+type SplitBookingRequest struct {
+	Players int `json:"players" validate:"required,min=1"` // 1 = volver al pago único
+}
+
+type BookingResponse struct {
+	ID         uint      `json:"id"`
+	CourtID    uint      `json:"court_id"`
+	UserID     *uint     `json:"user_id,omitempty"`
+	Date       time.Time `json:"date"`
+	StartTime  string    `json:"start_time"`
+	EndTime    string    `json:"end_time"`
+	Status     string    `json:"status"`
+	Source     string    `json:"source"`
+	GuestName  string    `json:"guest_name,omitempty"`
+	GuestPhone string    `json:"guest_phone,omitempty"`
+	SeriesID   *uint     `json:"series_id,omitempty"`
+	SplitPlayers int     `json:"split_players,omitempty"`
+	TotalPrice float64   `json:"total_price"`
+	PriceBreakdown *PriceBreakdown `json:"price_breakdown,omitempty"`
+	Notes      string    `json:"notes"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	
+	// Información adicional
+	Court        CourtInfo         `json:"court"`
+	User         UserInfo          `json:"user"`
+	Participants []ParticipantInfo `json:"participants,omitempty"`
+}
+
+type CourtInfo struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Address     string  `json:"address"`
+	PricePerHour float64 `json:"price_per_hour"`
+	Surface     string  `json:"surface"`
+	HasLighting bool    `json:"has_lighting"`
+	IsIndoor    bool    `json:"is_indoor"`
+}
+
+type UserInfo struct {
+	ID        uint   `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+}
+
+type CancellationResponse struct {
+	BookingID     uint    `json:"booking_id"`
+	Status        string  `json:"status"`
+	RefundPercent float64 `json:"refund_percent"`
+	RefundAmount  float64 `json:"refund_amount"`
+	RefundStatus  string  `json:"refund_status"` // none, refunded, failed
+}
+
+type GetBookingsRequest struct {
+	UserID  *uint   `json:"user_id,omitempty"`
+	CourtID *uint   `json:"court_id,omitempty"`
+	Date    *string `json:"date,omitempty"` // formato: "2024-03-20"
+	Status  *string `json:"status,omitempty"`
+}
+
+// OwnerBookingsRequest filtra las reservas de las canchas de un propietario
+type OwnerBookingsRequest struct {
+	CourtID   *uint   `form:"court_id" json:"court_id,omitempty"`
+	StartDate *string `form:"start_date" json:"start_date,omitempty"` // formato: "2024-03-20"
+	EndDate   *string `form:"end_date" json:"end_date,omitempty"`
+	Status    *string `form:"status" json:"status,omitempty"` // uno o varios estados separados por coma
+}
+
+// OwnerCreateBookingRequest carga una reserva telefónica o presencial a nombre de un usuario registrado o de un invitado
+type OwnerCreateBookingRequest struct {
+	CourtID       uint   `json:"court_id" validate:"required"`
+	Date          string `json:"date" validate:"required"`       // formato: "2024-03-20"
+	StartTime     string `json:"start_time" validate:"required"` // formato: "10:00"
+	EndTime       string `json:"end_time" validate:"required"`   // formato: "11:00"
+	UserID        *uint  `json:"user_id,omitempty"`              // usuario registrado
+	GuestName     string `json:"guest_name,omitempty"`           // invitado sin cuenta
+	GuestPhone    string `json:"guest_phone,omitempty"`
+	Source        string `json:"source" validate:"omitempty,oneof=phone walk_in"` // por defecto: phone
+	PaymentMethod string `json:"payment_method,omitempty" validate:"omitempty,oneof=cash transfer"` // si se informa, la reserva queda pagada
+	Notes         string `json:"notes"`
+}
+
+// OwnerBookingActionRequest acompaña las acciones del propietario sobre una reserva
+type OwnerBookingActionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CourtDayScheduleResponse es la grilla diaria de una cancha para el propietario
+type CourtDayScheduleResponse struct {
+	CourtID     uint               `json:"court_id"`
+	Date        string             `json:"date"`
+	IsClosed    bool               `json:"is_closed"`
+	Reason      string             `json:"reason,omitempty"`
+	Windows     []OpeningWindow    `json:"windows,omitempty"`
+	StepMinutes int                `json:"step_minutes"`
+	Slots       []ScheduleSlot     `json:"slots"`
+	Bookings    []*BookingResponse `json:"bookings"`
+}
+
+// ScheduleSlot es un casillero de la grilla diaria
+type ScheduleSlot struct {
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time"`
+	Available     bool   `json:"available"`
+	BookingID     *uint  `json:"booking_id,omitempty"`
+	BookingStatus string `json:"booking_status,omitempty"`
+	CustomerName  string `json:"customer_name,omitempty"`
+}
+
+type TimeSlot struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	DurationMinutes int `json:"duration_minutes"`
+	Available bool   `json:"available"`
+	Price     float64 `json:"price,omitempty"`
+}
+
+type AvailabilityResponse struct {
+	CourtID   uint        `json:"court_id"`
+	Date      string      `json:"date"`
+	IsClosed  bool        `json:"is_closed"`
+	Reason    string      `json:"reason,omitempty"` // motivo del cierre o del horario especial
+	Windows   []OpeningWindow `json:"windows,omitempty"` // franjas de apertura del día
+	DurationMinutes int   `json:"duration_minutes"`
+	Slots     []*TimeSlot `json:"slots"`
+}
+
+type OpeningWindow struct {
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
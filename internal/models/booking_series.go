@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BookingSeries es un turno fijo semanal o quincenal que genera una reserva por cada fecha
+type BookingSeries struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	CourtID    uint           `json:"court_id" gorm:"not null;index"`
+	UserID     uint           `json:"user_id" gorm:"not null;index"`
+	Frequency  string         `json:"frequency" gorm:"not null" validate:"oneof=weekly biweekly"`
+	DayOfWeek  int            `json:"day_of_week"` // 0 = Domingo, 1 = Lunes, etc.; se toma de la fecha de inicio
+	StartTime  string         `json:"start_time" gorm:"not null"`
+	EndTime    string         `json:"end_time" gorm:"not null"`
+	StartDate  time.Time      `json:"start_date" gorm:"type:date;not null"`
+	EndDate    time.Time      `json:"end_date" gorm:"type:date;not null"`
+	Exceptions []string       `json:"exceptions" gorm:"type:json;serializer:json"` // fechas sin turno (feriados, vacaciones, ocurrencias canceladas)
+	Status     string         `json:"status" gorm:"default:active" validate:"oneof=active cancelled"`
+	Notes      string         `json:"notes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Court    Court     `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Bookings []Booking `json:"bookings,omitempty" gorm:"foreignKey:SeriesID"`
+}
+
+type CreateBookingSeriesRequest struct {
+	CourtID       uint     `json:"court_id" validate:"required"`
+	Frequency     string   `json:"frequency" validate:"required,oneof=weekly biweekly"`
+	StartDate     string   `json:"start_date" validate:"required"` // formato: "2024-03-05", define el día de la semana
+	EndDate       string   `json:"end_date" validate:"required"`
+	StartTime     string   `json:"start_time" validate:"required"` // formato: "20:00"
+	EndTime       string   `json:"end_time" validate:"required"`
+	Exceptions    []string `json:"exceptions,omitempty"`     // fechas a omitir
+	SkipConflicts bool     `json:"skip_conflicts,omitempty"` // crear las fechas libres aunque otras estén ocupadas
+	Notes         string   `json:"notes"`
+}
+
+// SeriesOccurrence informa el resultado de una fecha de la serie
+type SeriesOccurrence struct {
+	Date      string `json:"date"`
+	Status    string `json:"status"` // created, conflict, skipped
+	BookingID *uint  `json:"booking_id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type BookingSeriesResponse struct {
+	Series      *BookingSeries     `json:"series,omitempty"`
+	Occurrences []SeriesOccurrence `json:"occurrences"`
+	Created     int                `json:"created"`
+	Conflicts   int                `json:"conflicts"`
+}
+
+type CancelSeriesRequest struct {
+	FromDate string `json:"from_date,omitempty"` // formato: "2024-04-01"; por defecto se cancelan todas las fechas futuras
+}
+
+// CancelSeriesResponse detalla las ocurrencias canceladas y los reembolsos aplicados
+type CancelSeriesResponse struct {
+	SeriesID      uint                   `json:"series_id"`
+	Status        string                 `json:"status"`
+	Cancellations []CancellationResponse `json:"cancellations"`
+}
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Coach es un profesor del club. Si tiene cuenta (UserID), puede ver los alumnos de sus clases y tomar asistencia.
+type Coach struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	OwnerID   uint           `json:"owner_id" gorm:"not null;index"`
+	UserID    *uint          `json:"user_id,omitempty" gorm:"index"`
+	Name      string         `json:"name" gorm:"not null" validate:"required"`
+	Email     string         `json:"email"`
+	Phone     string         `json:"phone"`
+	Bio       string         `json:"bio" gorm:"type:text"`
+	IsActive  bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Class es una clase semanal con un profesor en una cancha. Cada fecha genera una ClassSession que
+// reserva el turno de la cancha, por lo que las clases bloquean la disponibilidad como cualquier reserva.
+type Class struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	OwnerID     uint           `json:"owner_id" gorm:"not null;index"`
+	CoachID     uint           `json:"coach_id" gorm:"not null;index"`
+	CourtID     uint           `json:"court_id" gorm:"not null;index"`
+	Name        string         `json:"name" gorm:"not null" validate:"required"`
+	Description string         `json:"description" gorm:"type:text"`
+	Level       string         `json:"level"` // ej: "iniciación", "intermedio"
+	Capacity    int            `json:"capacity" gorm:"not null"`
+	Price       float64        `json:"price" gorm:"type:decimal(10,2)"` // precio de una clase suelta
+	DayOfWeek   int            `json:"day_of_week"`                     // 0 = Domingo, 1 = Lunes, etc.; se toma de la fecha de inicio
+	StartTime   string         `json:"start_time" gorm:"not null"`
+	EndTime     string         `json:"end_time" gorm:"not null"`
+	StartDate   time.Time      `json:"start_date" gorm:"type:date;not null"`
+	EndDate     time.Time      `json:"end_date" gorm:"type:date;not null"`
+	Status      string         `json:"status" gorm:"default:active" validate:"oneof=active cancelled"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Coach    Coach          `json:"coach,omitempty" gorm:"foreignKey:CoachID"`
+	Court    Court          `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Sessions []ClassSession `json:"sessions,omitempty" gorm:"foreignKey:ClassID"`
+}
+
+// ClassSession es una fecha de la clase con su turno reservado
+type ClassSession struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ClassID   uint      `json:"class_id" gorm:"not null;index"`
+	BookingID uint      `json:"booking_id" gorm:"not null;index"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;index"`
+	StartTime string    `json:"start_time" gorm:"not null"`
+	EndTime   string    `json:"end_time" gorm:"not null"`
+	Enrolled  int       `json:"enrolled" gorm:"default:0"` // alumnos inscriptos, para controlar el cupo
+	Status    string    `json:"status" gorm:"default:scheduled" validate:"oneof=scheduled completed cancelled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relaciones
+	Class       Class             `json:"class,omitempty" gorm:"foreignKey:ClassID"`
+	Enrollments []ClassEnrollment `json:"enrollments,omitempty" gorm:"foreignKey:SessionID"`
+}
+
+// ClassEnrollment es la inscripción de un alumno a una fecha. Se paga como clase suelta (en el club) o
+// descontando un crédito de un pack; al cancelar a tiempo el crédito se devuelve y la clase suelta ya pagada
+// queda pendiente de devolución.
+type ClassEnrollment struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	SessionID     uint       `json:"session_id" gorm:"not null;index"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	PaymentType   string     `json:"payment_type" gorm:"not null" validate:"oneof=single pack"`
+	PackID        *uint      `json:"pack_id,omitempty" gorm:"index"`
+	Amount        float64    `json:"amount" gorm:"type:decimal(10,2)"` // precio de la clase suelta; 0 con pack
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	PaymentMethod string     `json:"payment_method,omitempty"`             // cash, transfer
+	RefundStatus  string     `json:"refund_status,omitempty" gorm:"index"` // pending: clase suelta pagada y cancelada que el club debe devolver; refunded
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+	Status        string     `json:"status" gorm:"default:enrolled;index" validate:"oneof=enrolled cancelled attended absent"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relaciones
+	User    User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Session ClassSession `json:"session,omitempty" gorm:"foreignKey:SessionID"`
+}
+
+// ClassPack es un pack de clases vendido por el club a un alumno; sus créditos sirven para cualquier clase del club
+type ClassPack struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	OwnerID       uint       `json:"owner_id" gorm:"not null;index"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	Credits       int        `json:"credits" gorm:"not null"`
+	UsedCredits   int        `json:"used_credits" gorm:"default:0"`
+	Price         float64    `json:"price" gorm:"type:decimal(10,2)"`
+	PaymentMethod string     `json:"payment_method"` // cash, transfer
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relaciones
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+type CreateCoachRequest struct {
+	Name   string `json:"name" validate:"required"`
+	Email  string `json:"email" validate:"omitempty,email"`
+	Phone  string `json:"phone"`
+	Bio    string `json:"bio"`
+	UserID *uint  `json:"user_id,omitempty"` // cuenta del profesor para ver sus clases
+}
+
+type UpdateCoachRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    *string `json:"phone,omitempty"`
+	Bio      *string `json:"bio,omitempty"`
+	UserID   *uint   `json:"user_id,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+type CreateClassRequest struct {
+	CoachID       uint     `json:"coach_id" validate:"required"`
+	CourtID       uint     `json:"court_id" validate:"required"`
+	Name          string   `json:"name" validate:"required"`
+	Description   string   `json:"description"`
+	Level         string   `json:"level"`
+	Capacity      int      `json:"capacity" validate:"required,min=1"`
+	Price         float64  `json:"price" validate:"min=0"`
+	StartDate     string   `json:"start_date" validate:"required"` // formato: "2024-03-05", define el día de la semana
+	EndDate       string   `json:"end_date" validate:"required"`
+	StartTime     string   `json:"start_time" validate:"required"` // formato: "18:00"
+	EndTime       string   `json:"end_time" validate:"required"`
+	Exceptions    []string `json:"exceptions,omitempty"`     // fechas sin clase
+	SkipConflicts bool     `json:"skip_conflicts,omitempty"` // crear las fechas libres aunque otras estén ocupadas
+}
+
+type GetClassesRequest struct {
+	CourtID *uint  `form:"court_id"`
+	CoachID *uint  `form:"coach_id"`
+	Level   string `form:"level"`
+}
+
+// ClassScheduleResponse informa la clase creada y el resultado de cada fecha
+type ClassScheduleResponse struct {
+	Class       *Class             `json:"class,omitempty"`
+	Occurrences []SeriesOccurrence `json:"occurrences"`
+	Created     int                `json:"created"`
+	Conflicts   int                `json:"conflicts"`
+}
+
+type EnrollClassRequest struct {
+	PayWith string `json:"pay_with,omitempty" validate:"omitempty,oneof=single pack"` // por defecto usa un pack si tiene créditos
+}
+
+type SellClassPackRequest struct {
+	UserID        uint    `json:"user_id" validate:"required"`
+	Credits       int     `json:"credits" validate:"required,min=1"`
+	Price         float64 `json:"price" validate:"min=0"`
+	PaymentMethod string  `json:"payment_method" validate:"required,oneof=cash transfer"`
+	ValidDays     int     `json:"valid_days,omitempty"` // vacío = sin vencimiento
+}
+
+type AttendanceEntry struct {
+	EnrollmentID uint `json:"enrollment_id" validate:"required"`
+	Attended     bool `json:"attended"`
+}
+
+type MarkAttendanceRequest struct {
+	Attendance []AttendanceEntry `json:"attendance" validate:"required,min=1"`
+}
+
+// ClassSessionResponse es una fecha de la clase con los cupos libres
+type ClassSessionResponse struct {
+	ID        uint   `json:"id"`
+	ClassID   uint   `json:"class_id"`
+	Date      string `json:"date"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Capacity  int    `json:"capacity"`
+	Enrolled  int    `json:"enrolled"`
+	Available int    `json:"available"`
+	Status    string `json:"status"`
+}
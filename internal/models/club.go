@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Club es un complejo con varias canchas que comparten dirección, horarios, servicios y reglas. Las canchas
+// del club usan su configuración salvo que definan la propia (horarios, turnos y política de cancelación).
+type Club struct {
+	ID                 uint                `json:"id" gorm:"primaryKey"`
+	OwnerID            uint                `json:"owner_id" gorm:"not null;index"`
+	Name               string              `json:"name" gorm:"not null" validate:"required"`
+	Address            string              `json:"address" gorm:"not null" validate:"required"`
+	City               string              `json:"city" gorm:"index"`
+	Latitude           float64             `json:"latitude" gorm:"type:decimal(10,7)" validate:"required"`
+	Longitude          float64             `json:"longitude" gorm:"type:decimal(10,7)" validate:"required"`
+	Description        string              `json:"description" gorm:"type:text"`
+	ImageURL           string              `json:"image_url"`
+	Phone              string              `json:"phone"`
+	Email              string              `json:"email"`
+	Amenities          []string            `json:"amenities" gorm:"type:json;serializer:json"` // ej: vestuarios, estacionamiento, bar
+	Rules              []string            `json:"rules" gorm:"type:json;serializer:json"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy" gorm:"serializer:cancellation_policy"`
+	SlotDurations      []int               `json:"slot_durations" gorm:"type:json;serializer:json"`
+	SlotStepMinutes    int                 `json:"slot_step_minutes"`
+	BufferMinutes      int                 `json:"buffer_minutes"`
+	IsActive           bool                `json:"is_active" gorm:"default:true"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt      `json:"-" gorm:"index"`
+
+	// Relaciones
+	Courts        []Court            `json:"courts,omitempty" gorm:"foreignKey:ClubID"`
+	BusinessHours []ClubBusinessHour `json:"business_hours,omitempty" gorm:"foreignKey:ClubID"`
+}
+
+// ClubBusinessHour es una franja del horario semanal del club, usado por las canchas sin horario propio
+type ClubBusinessHour struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ClubID    uint      `json:"club_id" gorm:"not null;index"`
+	DayOfWeek int       `json:"day_of_week" gorm:"not null" validate:"min=0,max=6"` // 0=Sunday, 1=Monday, etc.
+	OpenTime  string    `json:"open_time" gorm:"not null" validate:"required"`
+	CloseTime string    `json:"close_time" gorm:"not null" validate:"required"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateClubRequest struct {
+	Name               string                `json:"name" validate:"required"`
+	Address            string                `json:"address" validate:"required"`
+	City               string                `json:"city"`
+	Latitude           float64               `json:"latitude" validate:"required"`
+	Longitude          float64               `json:"longitude" validate:"required"`
+	Description        string                `json:"description"`
+	ImageURL           string                `json:"image_url"`
+	Phone              string                `json:"phone"`
+	Email              string                `json:"email" validate:"omitempty,email"`
+	Amenities          []string              `json:"amenities"`
+	Rules              []string              `json:"rules"`
+	CancellationPolicy *CancellationPolicy   `json:"cancellation_policy" validate:"omitempty"`
+	SlotDurations      []int                 `json:"slot_durations" validate:"omitempty,dive,oneof=60 90 120"`
+	SlotStepMinutes    int                   `json:"slot_step_minutes" validate:"omitempty,min=5,max=120"`
+	BufferMinutes      int                   `json:"buffer_minutes" validate:"omitempty,min=0,max=60"`
+	BusinessHours      []BusinessHourRequest `json:"business_hours" validate:"required,min=1"`
+}
+
+type UpdateClubRequest struct {
+	Name               *string             `json:"name,omitempty"`
+	Address            *string             `json:"address,omitempty"`
+	City               *string             `json:"city,omitempty"`
+	Latitude           *float64            `json:"latitude,omitempty"`
+	Longitude          *float64            `json:"longitude,omitempty"`
+	Description        *string             `json:"description,omitempty"`
+	ImageURL           *string             `json:"image_url,omitempty"`
+	Phone              *string             `json:"phone,omitempty"`
+	Email              *string             `json:"email,omitempty" validate:"omitempty,email"`
+	Amenities          []string            `json:"amenities,omitempty"`
+	Rules              []string            `json:"rules,omitempty"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty"`
+	SlotDurations      []int               `json:"slot_durations,omitempty" validate:"omitempty,dive,oneof=60 90 120"`
+	SlotStepMinutes    *int                `json:"slot_step_minutes,omitempty" validate:"omitempty,min=5,max=120"`
+	BufferMinutes      *int                `json:"buffer_minutes,omitempty" validate:"omitempty,min=0,max=60"`
+	IsActive           *bool               `json:"is_active,omitempty"`
+}
+
+type SearchClubsRequest struct {
+	Name      string   `form:"name"`
+	City      string   `form:"city"`
+	Amenity   string   `form:"amenity"`
+	IsIndoor  *bool    `form:"is_indoor"` // clubes con al menos una cancha techada (o descubierta)
+	MaxPrice  *float64 `form:"max_price"` // clubes con al menos una cancha hasta este precio por hora
+	Latitude  *float64 `form:"latitude"`
+	Longitude *float64 `form:"longitude"`
+	Radius    *float64 `form:"radius"` // km, por defecto 20
+}
+
+// ClubSearchResult resume un club con sus canchas activas
+type ClubSearchResult struct {
+	Club         *Club   `json:"club"`
+	CourtCount   int     `json:"court_count"`
+	IndoorCourts int     `json:"indoor_courts"`
+	MinPrice     float64 `json:"min_price"` // precio por hora más bajo entre sus canchas
+	MaxPrice     float64 `json:"max_price"`
+}
+
+// ClubSlotCourt es una cancha libre para un horario de la disponibilidad del club
+type ClubSlotCourt struct {
+	CourtID   uint    `json:"court_id"`
+	CourtName string  `json:"court_name"`
+	IsIndoor  bool    `json:"is_indoor"`
+	Price     float64 `json:"price"`
+}
+
+// ClubSlot es un horario en el que al menos una cancha del club está libre
+type ClubSlot struct {
+	StartTime       string          `json:"start_time"`
+	EndTime         string          `json:"end_time"`
+	DurationMinutes int             `json:"duration_minutes"`
+	AvailableCourts int             `json:"available_courts"`
+	MinPrice        float64         `json:"min_price"`
+	Courts          []ClubSlotCourt `json:"courts"`
+}
+
+// ClubAvailabilityResponse agrupa los horarios libres de todas las canchas del club ("cualquier cancha")
+type ClubAvailabilityResponse struct {
+	ClubID          uint       `json:"club_id"`
+	Date            string     `json:"date"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Slots           []ClubSlot `json:"slots"`
+}
@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+func TestCancellationPolicyColumn(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	court := testutil.CreateCourt(t, db, owner.ID)
+
+	// Sin política se lee nil para usar la del club o la predeterminada
+	var stored models.Court
+	if err := db.First(&stored, court.ID).Error; err != nil {
+		t.Fatalf("fetch court: %v", err)
+	}
+	if stored.CancellationPolicy != nil {
+		t.Fatalf("expected no cancellation policy, got %+v", stored.CancellationPolicy)
+	}
+
+	// Las canchas existentes guardaban la política como texto libre en la misma columna
+	if err := db.Exec("UPDATE courts SET cancellation_policy = ? WHERE id = ?", "Cancelación gratuita hasta 24 hs antes", court.ID).Error; err != nil {
+		t.Fatalf("store legacy policy: %v", err)
+	}
+	stored = models.Court{}
+	if err := db.First(&stored, court.ID).Error; err != nil {
+		t.Fatalf("fetch court with legacy policy: %v", err)
+	}
+	if stored.CancellationPolicy == nil || !stored.CancellationPolicy.FreeText || stored.CancellationPolicy.Description != "Cancelación gratuita hasta 24 hs antes" {
+		t.Fatalf("expected the legacy text as the policy description, got %+v", stored.CancellationPolicy)
+	}
+
+	policy := &models.CancellationPolicy{FreeCancellationHours: 24, RefundTiers: []models.RefundTier{{MinHoursBefore: 6, RefundPercent: 50}}}
+	encoded, _ := json.Marshal(policy)
+	if err := db.Model(&stored).Update("cancellation_policy", string(encoded)).Error; err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+	stored = models.Court{}
+	if err := db.First(&stored, court.ID).Error; err != nil {
+		t.Fatalf("fetch court with policy: %v", err)
+	}
+	if stored.CancellationPolicy == nil || stored.CancellationPolicy.FreeCancellationHours != 24 || len(stored.CancellationPolicy.RefundTiers) != 1 {
+		t.Fatalf("expected the stored policy, got %+v", stored.CancellationPolicy)
+	}
+}
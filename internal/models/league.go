@@ -0,0 +1,149 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// League es una liga de varias semanas organizada por el dueño de las canchas. Cada temporada es una liga
+// nueva que conserva las parejas de la anterior con los ascensos y descensos entre divisiones.
+type League struct {
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	OwnerID          uint           `json:"owner_id" gorm:"not null;index"`
+	Name             string         `json:"name" gorm:"not null" validate:"required"`
+	Description      string         `json:"description" gorm:"type:text"`
+	Season           int            `json:"season" gorm:"default:1"`
+	PreviousLeagueID *uint          `json:"previous_league_id,omitempty" gorm:"index"` // temporada anterior
+	Status           string         `json:"status" gorm:"default:registration;index" validate:"oneof=registration in_progress finished"`
+	StartDate        time.Time      `json:"start_date" gorm:"type:date;not null"`
+	RoundDays        int            `json:"round_days" gorm:"default:7"` // días que tienen las parejas para jugar cada fecha
+	PointsWin        int            `json:"points_win" gorm:"default:3"`
+	PointsLoss       int            `json:"points_loss" gorm:"default:1"`    // por jugar y perder
+	PointsPerSet     int            `json:"points_per_set" gorm:"default:0"` // bonus por cada set ganado
+	PromotionSpots   int            `json:"promotion_spots" gorm:"default:2"`
+	RelegationSpots  int            `json:"relegation_spots" gorm:"default:2"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Divisions []LeagueDivision `json:"divisions,omitempty" gorm:"foreignKey:LeagueID"`
+}
+
+// LeagueDivision es una categoría de la liga; Level 1 es la división más alta
+type LeagueDivision struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	LeagueID uint   `json:"league_id" gorm:"not null;index"`
+	Name     string `json:"name" gorm:"not null"`
+	Level    int    `json:"level" gorm:"not null"`
+
+	// Relaciones
+	Teams []LeagueTeam `json:"teams,omitempty" gorm:"foreignKey:DivisionID"`
+}
+
+// LeagueTeam es una pareja inscripta en una división de la liga
+type LeagueTeam struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	LeagueID   uint      `json:"league_id" gorm:"not null;index"`
+	DivisionID uint      `json:"division_id" gorm:"not null;index"`
+	Player1ID  uint      `json:"player1_id" gorm:"not null;index"`
+	Player2ID  uint      `json:"player2_id" gorm:"not null;index"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LeagueFixture es un partido de una fecha de la liga. Las parejas lo programan reservando una cancha y
+// el resultado que carga una pareja queda firme cuando lo confirma la rival.
+type LeagueFixture struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	LeagueID       uint       `json:"league_id" gorm:"not null;index"`
+	DivisionID     uint       `json:"division_id" gorm:"not null;index"`
+	Round          int        `json:"round" gorm:"not null"`
+	HomeTeamID     uint       `json:"home_team_id" gorm:"not null;index"`
+	AwayTeamID     uint       `json:"away_team_id" gorm:"not null;index"`
+	DueDate        time.Time  `json:"due_date" gorm:"type:date"` // último día para jugar la fecha
+	BookingID      *uint      `json:"booking_id,omitempty" gorm:"index"`
+	Sets           []SetScore `json:"sets,omitempty" gorm:"type:text;serializer:json"`
+	HomeSets       int        `json:"home_sets"`
+	AwaySets       int        `json:"away_sets"`
+	WinnerTeamID   *uint      `json:"winner_team_id,omitempty"`
+	Status         string     `json:"status" gorm:"default:pending;index" validate:"oneof=pending scheduled reported confirmed disputed"`
+	ReportedTeamID *uint      `json:"reported_team_id,omitempty"`
+	ReportedBy     *uint      `json:"reported_by,omitempty"`
+	ConfirmedBy    *uint      `json:"confirmed_by,omitempty"`
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relaciones
+	HomeTeam LeagueTeam `json:"home_team,omitempty" gorm:"foreignKey:HomeTeamID"`
+	AwayTeam LeagueTeam `json:"away_team,omitempty" gorm:"foreignKey:AwayTeamID"`
+	Booking  *Booking   `json:"booking,omitempty" gorm:"foreignKey:BookingID"`
+}
+
+type CreateLeagueRequest struct {
+	Name            string   `json:"name" validate:"required"`
+	Description     string   `json:"description"`
+	StartDate       string   `json:"start_date" validate:"required"`      // formato: "2024-03-04"
+	Divisions       []string `json:"divisions" validate:"required,min=1"` // nombres de la división más alta a la más baja
+	RoundDays       int      `json:"round_days,omitempty"`                // por defecto 7
+	PointsWin       *int     `json:"points_win,omitempty"`                // por defecto 3
+	PointsLoss      *int     `json:"points_loss,omitempty"`               // por defecto 1
+	PointsPerSet    *int     `json:"points_per_set,omitempty"`            // por defecto 0
+	PromotionSpots  *int     `json:"promotion_spots,omitempty"`           // por defecto 2
+	RelegationSpots *int     `json:"relegation_spots,omitempty"`          // por defecto 2
+}
+
+type RegisterLeagueTeamRequest struct {
+	DivisionID uint `json:"division_id" validate:"required"`
+	PartnerID  uint `json:"partner_id" validate:"required"`
+}
+
+type GetLeagueFixturesRequest struct {
+	DivisionID *uint `form:"division_id"`
+	Round      *int  `form:"round"`
+}
+
+// BookLeagueFixtureRequest reserva una cancha del club para jugar el partido de la fecha
+type BookLeagueFixtureRequest struct {
+	CourtID   uint   `json:"court_id" validate:"required"`
+	Date      string `json:"date" validate:"required"`       // formato: "2024-03-20"
+	StartTime string `json:"start_time" validate:"required"` // formato: "20:00"
+	EndTime   string `json:"end_time" validate:"required"`
+}
+
+type ReportLeagueResultRequest struct {
+	Sets []SetScore `json:"sets" validate:"required,min=1,max=5"` // Team1 es la pareja local y Team2 la visitante
+}
+
+type NextSeasonRequest struct {
+	StartDate string `json:"start_date" validate:"required"` // formato: "2024-06-03"
+}
+
+type LeagueStanding struct {
+	TeamID       uint   `json:"team_id"`
+	Name         string `json:"name"`
+	Played       int    `json:"played"`
+	Won          int    `json:"won"`
+	Lost         int    `json:"lost"`
+	SetsFor      int    `json:"sets_for"`
+	SetsAgainst  int    `json:"sets_against"`
+	GamesFor     int    `json:"games_for"`
+	GamesAgainst int    `json:"games_against"`
+	Points       int    `json:"points"`
+	Movement     string `json:"movement,omitempty"` // promotion o relegation según la posición actual
+}
+
+type DivisionStandings struct {
+	DivisionID uint             `json:"division_id"`
+	Name       string           `json:"name"`
+	Level      int              `json:"level"`
+	Standings  []LeagueStanding `json:"standings"`
+}
+
+type LeagueStandingsResponse struct {
+	LeagueID  uint                `json:"league_id"`
+	Season    int                 `json:"season"`
+	Divisions []DivisionStandings `json:"divisions"`
+}
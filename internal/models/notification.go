@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification es un aviso dirigido a un usuario que la app consulta desde su bandeja
+type Notification struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	Type          string     `json:"type" gorm:"not null"` // waitlist_slot_available
+	Title         string     `json:"title" gorm:"not null"`
+	Message       string     `json:"message" gorm:"type:text"`
+	ReferenceType string     `json:"reference_type,omitempty"` // entidad relacionada (waitlist, booking)
+	ReferenceID   *uint      `json:"reference_id,omitempty"`
+	ReadAt        *time.Time `json:"read_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type GetNotificationsRequest struct {
+	UnreadOnly bool `form:"unread_only"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// OpenMatch publica una reserva que busca jugadores para completar el partido
+type OpenMatch struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BookingID   uint      `json:"booking_id" gorm:"not null;uniqueIndex"`
+	OrganizerID uint      `json:"organizer_id" gorm:"not null;index"`
+	MinLevel    float64   `json:"min_level" gorm:"type:decimal(3,1)" validate:"min=1,max=7"` // escala de nivel de padel 1 a 7
+	MaxLevel    float64   `json:"max_level" gorm:"type:decimal(3,1)" validate:"min=1,max=7"`
+	FreeSpots   int       `json:"free_spots" gorm:"not null"`
+	Status      string    `json:"status" gorm:"default:open;index" validate:"oneof=open full closed"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relaciones
+	Booking Booking `json:"booking,omitempty" gorm:"foreignKey:BookingID"`
+}
+
+type PublishOpenMatchRequest struct {
+	MinLevel    float64 `json:"min_level" validate:"required,min=1,max=7"`
+	MaxLevel    float64 `json:"max_level" validate:"required,min=1,max=7"`
+	FreeSpots   int     `json:"free_spots" validate:"required,min=1"`
+	Description string  `json:"description"`
+}
+
+type SearchOpenMatchesRequest struct {
+	Latitude  *float64 `form:"lat"`
+	Longitude *float64 `form:"lng"`
+	Radius    *float64 `form:"radius"` // km, por defecto 20
+	Date      *string  `form:"date"`   // formato: "2024-03-20"
+	Level     *float64 `form:"level"`  // nivel del jugador que busca partido
+}
+
+type OpenMatchResponse struct {
+	ID          uint              `json:"id"`
+	BookingID   uint              `json:"booking_id"`
+	Date        time.Time         `json:"date"`
+	StartTime   string            `json:"start_time"`
+	EndTime     string            `json:"end_time"`
+	MinLevel    float64           `json:"min_level"`
+	MaxLevel    float64           `json:"max_level"`
+	FreeSpots   int               `json:"free_spots"`
+	ShareAmount float64           `json:"share_amount"` // parte del precio que paga cada jugador
+	Status      string            `json:"status"`
+	Description string            `json:"description"`
+	Court       CourtInfo         `json:"court"`
+	Organizer   UserInfo          `json:"organizer"`
+	Players     []ParticipantInfo `json:"players,omitempty"`
+}
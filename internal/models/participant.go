@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// BookingParticipant es un jugador invitado a una reserva por el organizador.
+// La invitación puede hacerse a un usuario registrado o a un email; al registrarse con ese email queda vinculada.
+type BookingParticipant struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	BookingID   uint       `json:"booking_id" gorm:"not null;index"`
+	UserID      *uint      `json:"user_id,omitempty" gorm:"index"`
+	Email       string     `json:"email" gorm:"not null;index"`
+	Status      string     `json:"status" gorm:"default:invited" validate:"oneof=invited accepted declined removed"`
+	InvitedBy   uint       `json:"invited_by" gorm:"not null"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relaciones
+	User    *User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Booking Booking `json:"booking,omitempty" gorm:"foreignKey:BookingID"`
+}
+
+type InviteParticipantRequest struct {
+	UserID *uint  `json:"user_id,omitempty"`                          // usuario registrado
+	Email  string `json:"email,omitempty" validate:"omitempty,email"` // o email de un jugador sin cuenta
+}
+
+type ParticipantInfo struct {
+	ID        uint   `json:"id"`
+	UserID    *uint  `json:"user_id,omitempty"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Status    string `json:"status"`
+}
@@ -1,64 +1,151 @@
-package models
-
-import (
-	"time"
-
-	"gorm.io/gorm"
-)
-
-type Payment struct {
-	ID            string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
-	BookingID     uint           `json:"booking_id" gorm:"not null"`
-	UserID        uint           `json:"user_id" gorm:"not null"`
-	Amount        float64        `json:"amount" gorm:"type:decimal(10,2)" validate:"required,min=0"`
-	Currency      string         `json:"currency" gorm:"default:ARS"`
-	Status        string         `json:"status" gorm:"default:pending" validate:"oneof=pending approved rejected cancelled"`
-	PreferenceID  string         `json:"preference_id" gorm:"uniqueIndex"`
-	MercadoPagoID string         `json:"mercado_pago_id"`
-	PaymentMethod string         `json:"payment_method"`
-	PayerEmail    string         `json:"payer_email"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
-
-	// Relaciones
-	Booking Booking `json:"booking,omitempty" gorm:"foreignKey:BookingID"`
-	User    User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-}
-
-type CreatePreferenceRequest struct {
-	BookingID  uint   `json:"booking_id" validate:"required"`
-	PayerEmail string `json:"payer_email" validate:"required,email"`
-}
-
-type PreferenceResponse struct {
-	ID        string `json:"id"`
-	InitPoint string `json:"init_point"`
-}
-
-type PaymentStatusResponse struct {
-	ID            string  `json:"id"`
-	Status        string  `json:"status"`
-	Amount        float64 `json:"amount"`
-	Currency      string  `json:"currency"`
-	PaymentMethod string  `json:"payment_method"`
-	PayerEmail    string  `json:"payer_email"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-}
-
-type WebhookRequest struct {
-	Action string `json:"action"`
-	Data   struct {
-		ID string `json:"id"`
-	} `json:"data"`
-}
-
-type PaymentStatistics struct {
-	TotalPayments    int     `json:"total_payments"`
-	ApprovedPayments int     `json:"approved_payments"`
-	PendingPayments  int     `json:"pending_payments"`
-	RejectedPayments int     `json:"rejected_payments"`
-	TotalRevenue     float64 `json:"total_revenue"`
-	AverageAmount    float64 `json:"average_amount"`
-}
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Payment struct {
+	ID            string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	BookingID     uint           `json:"booking_id" gorm:"not null"`
+	UserID        uint           `json:"user_id" gorm:"not null"`
+	Amount        float64        `json:"amount" gorm:"type:decimal(10,2)" validate:"required,min=0"`
+	Currency      string         `json:"currency" gorm:"default:ARS"`
+	Provider      string         `json:"provider" gorm:"default:mercadopago"` // mercadopago, sandbox o manual (efectivo/transferencia cobrado por el club)
+	Status        string         `json:"status" gorm:"default:pending" validate:"oneof=pending approved rejected cancelled refunded"`
+	PreferenceID  string         `json:"preference_id" gorm:"uniqueIndex"`
+	MercadoPagoID string         `json:"mercado_pago_id"`
+	PaymentMethod string         `json:"payment_method"`
+	PayerEmail    string         `json:"payer_email"`
+	RefundedAmount float64       `json:"refunded_amount" gorm:"type:decimal(10,2);default:0"` // total reembolsado; el pago pasa a refunded al devolverse completo
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Booking Booking `json:"booking,omitempty" gorm:"foreignKey:BookingID"`
+	User    User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Refunds []Refund `json:"refunds,omitempty" gorm:"foreignKey:PaymentID"`
+}
+
+// Refund registra cada devolución (total o parcial) de un pago
+type Refund struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	PaymentID        string    `json:"payment_id" gorm:"type:varchar(36);not null;index"`
+	BookingID        uint      `json:"booking_id" gorm:"not null;index"`
+	Amount           float64   `json:"amount" gorm:"type:decimal(10,2)"`
+	Status           string    `json:"status" gorm:"default:pending" validate:"oneof=pending approved rejected"`
+	Reason           string    `json:"reason"`
+	ProviderRefundID string    `json:"provider_refund_id"`
+	RequestedBy      *uint     `json:"requested_by,omitempty"` // nil cuando el reembolso lo dispara el sistema (ej: cancelación)
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type CreateRefundRequest struct {
+	Amount *float64 `json:"amount,omitempty" validate:"omitempty,gt=0"` // vacío = reembolsar el saldo completo
+	Reason string   `json:"reason" validate:"required"`
+}
+
+type CreatePreferenceRequest struct {
+	BookingID    uint   `json:"booking_id" validate:"required"`
+	PayerEmail   string `json:"payer_email" validate:"required,email"`
+	PayRemainder bool   `json:"pay_remainder,omitempty"` // en reservas divididas, pagar todo el saldo que nadie tomó
+}
+
+type PreferenceResponse struct {
+	ID        string `json:"id"`
+	InitPoint string `json:"init_point"`
+}
+
+type PaymentStatusResponse struct {
+	ID            string  `json:"id"`
+	Status        string  `json:"status"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	PaymentMethod string  `json:"payment_method"`
+	PayerEmail    string  `json:"payer_email"`
+	RefundedAmount float64 `json:"refunded_amount"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// BookingPaymentSummary resume los pagos de una reserva, en particular de las divididas entre jugadores
+type BookingPaymentSummary struct {
+	BookingID        uint                    `json:"booking_id"`
+	Status           string                  `json:"status"`
+	TotalPrice       float64                 `json:"total_price"`
+	SplitPlayers     int                     `json:"split_players"`
+	ShareAmount      float64                 `json:"share_amount"`
+	PaidAmount       float64                 `json:"paid_amount"`
+	PendingAmount    float64                 `json:"pending_amount"`    // preferencias creadas aún sin pagar
+	UnassignedAmount float64                 `json:"unassigned_amount"` // saldo sin preferencia
+	HoldExpiresAt    *time.Time              `json:"hold_expires_at,omitempty"`
+	Payments         []PaymentStatusResponse `json:"payments"`
+}
+
+type WebhookRequest struct {
+	ID     json.RawMessage `json:"id"` // id de la notificación (numérico en MercadoPago)
+	Type   string          `json:"type"`
+	Action string `json:"action"`
+	Data   struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// WebhookEvent registra cada notificación recibida del proveedor de pagos. La clave única
+// (proveedor, id de evento) permite descartar reenvíos y reprocesar los eventos fallidos.
+type WebhookEvent struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Provider    string     `json:"provider" gorm:"type:varchar(32);not null;uniqueIndex:idx_webhook_events_provider_event"`
+	EventID     string     `json:"event_id" gorm:"type:varchar(128);not null;uniqueIndex:idx_webhook_events_provider_event"`
+	Action      string     `json:"action"`
+	ResourceID  string     `json:"resource_id" gorm:"index"` // id del pago en el proveedor
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Status      string     `json:"status" gorm:"default:received"` // received, processing, processed, ignored, failed
+	Error       string     `json:"error,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ManualPaymentRequest registra un cobro realizado por el club fuera de la pasarela de pagos
+type ManualPaymentRequest struct {
+	PaymentMethod string `json:"payment_method" validate:"required,oneof=cash transfer"`
+}
+
+// SandboxPaymentRequest simula la resolución de un pago en el proveedor sandbox
+type SandboxPaymentRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected pending cancelled"`
+}
+
+// PaymentReconciliation registra una diferencia detectada entre el estado local de un pago y el del proveedor
+type PaymentReconciliation struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	PaymentID        string    `json:"payment_id" gorm:"type:varchar(36);not null;index"`
+	BookingID        uint      `json:"booking_id"`
+	LocalStatus      string    `json:"local_status"` // estado local del pago, o refund_pending para un reembolso sin completar
+	GatewayStatus    string    `json:"gateway_status"`
+	GatewayPaymentID string    `json:"gateway_payment_id"`
+	Resolution       string    `json:"resolution"` // updated: se aplicó el estado del proveedor, ignored: el proveedor informa un estado anterior, failed: no se pudo consultar o actualizar
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at" gorm:"index"`
+}
+
+// ReconciliationReport resume una ejecución de la conciliación de pagos pendientes
+type ReconciliationReport struct {
+	CheckedAt  time.Time               `json:"checked_at"`
+	Checked    int                     `json:"checked"`
+	Mismatches []PaymentReconciliation `json:"mismatches"`
+}
+
+type PaymentStatistics struct {
+	TotalPayments    int     `json:"total_payments"`
+	ApprovedPayments int     `json:"approved_payments"`
+	PendingPayments  int     `json:"pending_payments"`
+	RejectedPayments int     `json:"rejected_payments"`
+	TotalRevenue     float64 `json:"total_revenue"`
+	AverageAmount    float64 `json:"average_amount"`
+}
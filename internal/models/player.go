@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+// PlayerProfile es el perfil de padel de un usuario: nivel declarado, lado y mano hábil, y su rating calculado
+// a partir de los resultados de los partidos jugados
+type PlayerProfile struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	DeclaredLevel float64   `json:"declared_level" gorm:"type:decimal(3,1)" validate:"min=1,max=7"` // escala de nivel de padel 1 a 7
+	PreferredSide string    `json:"preferred_side" gorm:"default:both" validate:"oneof=drive reves both"`
+	DominantHand  string    `json:"dominant_hand" gorm:"default:right" validate:"oneof=right left"`
+	Rating        float64   `json:"rating" gorm:"type:decimal(7,2);index"`
+	MatchesPlayed int       `json:"matches_played" gorm:"default:0"`
+	Wins          int       `json:"wins" gorm:"default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relaciones
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type UpdatePlayerProfileRequest struct {
+	DeclaredLevel *float64 `json:"declared_level,omitempty" validate:"omitempty,min=1,max=7"`
+	PreferredSide *string  `json:"preferred_side,omitempty" validate:"omitempty,oneof=drive reves both"`
+	DominantHand  *string  `json:"dominant_hand,omitempty" validate:"omitempty,oneof=right left"`
+}
+
+type PlayerProfileResponse struct {
+	UserID        uint    `json:"user_id"`
+	FirstName     string  `json:"first_name"`
+	LastName      string  `json:"last_name"`
+	DeclaredLevel float64 `json:"declared_level"`
+	Level         float64 `json:"level"` // nivel equivalente al rating actual
+	PreferredSide string  `json:"preferred_side"`
+	DominantHand  string  `json:"dominant_hand"`
+	Rating        float64 `json:"rating"`
+	MatchesPlayed int     `json:"matches_played"`
+	Wins          int     `json:"wins"`
+}
+
+// MatchResult es el resultado de un partido jugado en una reserva completada. Lo informa un jugador y
+// actualiza el rating recién cuando lo confirma un jugador del equipo rival.
+type MatchResult struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	BookingID    uint       `json:"booking_id" gorm:"not null;uniqueIndex"`
+	RecordedBy   uint       `json:"recorded_by" gorm:"not null"`
+	ReportedTeam int        `json:"reported_team"` // equipo de quien informó el resultado
+	Sets         []SetScore `json:"sets" gorm:"type:text;serializer:json"`
+	WinnerTeam   int        `json:"winner_team" validate:"oneof=1 2"`
+	Status       string     `json:"status" gorm:"default:reported;index" validate:"oneof=reported confirmed disputed"`
+	ConfirmedBy  *uint      `json:"confirmed_by,omitempty"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relaciones
+	Players []MatchResultPlayer `json:"players" gorm:"foreignKey:MatchResultID"`
+}
+
+// MatchResultPlayer es un jugador de uno de los dos equipos del partido
+type MatchResultPlayer struct {
+	ID            uint `json:"id" gorm:"primaryKey"`
+	MatchResultID uint `json:"match_result_id" gorm:"not null;index"`
+	UserID        uint `json:"user_id" gorm:"not null;index"`
+	Team          int  `json:"team" validate:"oneof=1 2"`
+}
+
+type SetScore struct {
+	Team1 int `json:"team1"`
+	Team2 int `json:"team2"`
+}
+
+type RecordMatchResultRequest struct {
+	Team1 []uint     `json:"team1" validate:"required,min=1,max=2"` // ids de los jugadores de cada equipo
+	Team2 []uint     `json:"team2" validate:"required,min=1,max=2"`
+	Sets  []SetScore `json:"sets" validate:"required,min=1,max=5"`
+}
+
+// RatingHistory registra cada cambio de rating de un jugador
+type RatingHistory struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"not null;index"`
+	MatchResultID *uint     `json:"match_result_id,omitempty" gorm:"index"` // nil cuando el cambio viene del nivel declarado
+	BookingID     *uint     `json:"booking_id,omitempty"`
+	OldRating     float64   `json:"old_rating" gorm:"type:decimal(7,2)"`
+	NewRating     float64   `json:"new_rating" gorm:"type:decimal(7,2)"`
+	Change        float64   `json:"change" gorm:"type:decimal(7,2)"`
+	Reason        string    `json:"reason"` // match_won, match_lost, declared_level
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+type SuggestPartnersRequest struct {
+	Side  *string `form:"side"`  // drive o reves; por defecto el lado complementario al propio
+	Limit int     `form:"limit"` // por defecto 20
+}
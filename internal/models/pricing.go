@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PricingRule ajusta el precio por hora de una cancha según el horario, el día de la semana o un rango de fechas
+type PricingRule struct {
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	CourtID          uint           `json:"court_id" gorm:"not null;index"`
+	Name             string         `json:"name" gorm:"not null" validate:"required"`
+	Adjustment       string         `json:"adjustment" gorm:"not null" validate:"oneof=fixed percent amount"` // fixed: reemplaza el precio por hora, percent: porcentaje sobre el precio, amount: monto fijo por hora
+	Value            float64        `json:"value" gorm:"type:decimal(10,2)"`
+	DaysOfWeek       []int          `json:"days_of_week" gorm:"type:json;serializer:json"` // vacío = todos los días
+	StartTime        *string        `json:"start_time,omitempty"`                          // franja horaria, un fin anterior al inicio cruza la medianoche
+	EndTime          *string        `json:"end_time,omitempty"`
+	StartDate        *time.Time     `json:"start_date,omitempty" gorm:"type:date"` // vigencia para promociones
+	EndDate          *time.Time     `json:"end_date,omitempty" gorm:"type:date"`
+	RequiresLighting bool           `json:"requires_lighting" gorm:"default:false"` // solo aplica si la cancha tiene iluminación (recargo nocturno)
+	Priority         int            `json:"priority" gorm:"default:0"`
+	IsActive         bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relación
+	Court Court `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+}
+
+type CreatePricingRuleRequest struct {
+	Name             string  `json:"name" validate:"required"`
+	Adjustment       string  `json:"adjustment" validate:"required,oneof=fixed percent amount"`
+	Value            float64 `json:"value"`
+	DaysOfWeek       []int   `json:"days_of_week" validate:"omitempty,dive,min=0,max=6"`
+	StartTime        *string `json:"start_time,omitempty"` // formato: "18:00"
+	EndTime          *string `json:"end_time,omitempty"`   // formato: "23:00"
+	StartDate        *string `json:"start_date,omitempty"` // formato: "2024-03-20"
+	EndDate          *string `json:"end_date,omitempty"`
+	RequiresLighting bool    `json:"requires_lighting"`
+	Priority         int     `json:"priority"`
+}
+
+// PriceBreakdown detalla cómo se calculó el precio de un turno
+type PriceBreakdown struct {
+	BasePricePerHour float64        `json:"base_price_per_hour"`
+	Segments         []PriceSegment `json:"segments"`
+	Total            float64        `json:"total"`
+}
+
+// PriceSegment es un tramo del turno con el mismo precio por hora
+type PriceSegment struct {
+	StartTime    string   `json:"start_time"`
+	EndTime      string   `json:"end_time"`
+	PricePerHour float64  `json:"price_per_hour"`
+	Amount       float64  `json:"amount"`
+	Rules        []string `json:"rules,omitempty"`
+}
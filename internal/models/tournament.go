@@ -0,0 +1,142 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tournament es un torneo organizado por el dueño de las canchas. Al generar el cuadro, cada partido se
+// programa en una de las canchas del torneo reservando el turno como cualquier otra reserva del club.
+type Tournament struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	OwnerID      uint           `json:"owner_id" gorm:"not null;index"`
+	Name         string         `json:"name" gorm:"not null" validate:"required"`
+	Description  string         `json:"description" gorm:"type:text"`
+	Format       string         `json:"format" gorm:"not null" validate:"oneof=elimination groups americano mexicano"`
+	Status       string         `json:"status" gorm:"default:registration;index" validate:"oneof=registration scheduled in_progress finished cancelled"`
+	StartDate    time.Time      `json:"start_date" gorm:"type:date;not null"`
+	DayStartTime string         `json:"day_start_time" gorm:"not null"` // franja diaria en la que se programan los partidos
+	DayEndTime   string         `json:"day_end_time" gorm:"not null"`
+	MatchMinutes int            `json:"match_minutes" gorm:"default:90"`
+	MaxEntries   int            `json:"max_entries"`                  // parejas, o jugadores en americano y mexicano
+	GroupCount   int            `json:"group_count" gorm:"default:1"` // cantidad de grupos del formato groups
+	Rounds       int            `json:"rounds"`                       // rondas de americano y mexicano
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Courts  []Court           `json:"courts,omitempty" gorm:"many2many:tournament_courts"`
+	Pairs   []TournamentPair  `json:"pairs,omitempty" gorm:"foreignKey:TournamentID"`
+	Matches []TournamentMatch `json:"matches,omitempty" gorm:"foreignKey:TournamentID"`
+}
+
+// TournamentPair es una pareja inscripta al torneo. En americano y mexicano los compañeros rotan en cada
+// ronda, por lo que cada inscripción es un jugador individual y Player2ID queda vacío.
+type TournamentPair struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	TournamentID uint      `json:"tournament_id" gorm:"not null;index"`
+	Player1ID    uint      `json:"player1_id" gorm:"not null;index"`
+	Player2ID    *uint     `json:"player2_id,omitempty" gorm:"index"`
+	Name         string    `json:"name"`
+	Seed         int       `json:"seed"`         // orden según el rating de los jugadores al generar el cuadro
+	GroupNumber  int       `json:"group_number"` // grupo asignado en el formato groups
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TournamentMatch es un partido del cuadro. En eliminación los partidos de las rondas siguientes se crean
+// con la cancha reservada y las parejas se completan a medida que avanzan los ganadores.
+type TournamentMatch struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	TournamentID uint       `json:"tournament_id" gorm:"not null;index"`
+	Round        int        `json:"round" gorm:"not null"`
+	Position     int        `json:"position"`     // orden dentro de la ronda
+	GroupNumber  int        `json:"group_number"` // 0 fuera del formato groups
+	Pair1ID      *uint      `json:"pair1_id,omitempty"`
+	Pair2ID      *uint      `json:"pair2_id,omitempty"`
+	Partner1ID   *uint      `json:"partner1_id,omitempty"` // americano y mexicano: compañero de turno de Pair1
+	Partner2ID   *uint      `json:"partner2_id,omitempty"` // y de Pair2
+	NextMatchID  *uint      `json:"next_match_id,omitempty"`
+	NextSlot     int        `json:"next_slot,omitempty"` // 1 o 2: lugar que ocupa el ganador en el partido siguiente
+	CourtID      *uint      `json:"court_id,omitempty"`
+	BookingID    *uint      `json:"booking_id,omitempty" gorm:"index"`
+	Date         *time.Time `json:"date,omitempty" gorm:"type:date"`
+	StartTime    string     `json:"start_time,omitempty"`
+	EndTime      string     `json:"end_time,omitempty"`
+	Sets         []SetScore `json:"sets,omitempty" gorm:"type:text;serializer:json"`
+	Score1       int        `json:"score1"` // sets ganados, o games en americano y mexicano
+	Score2       int        `json:"score2"`
+	WinnerPairID *uint      `json:"winner_pair_id,omitempty"`
+	Status       string     `json:"status" gorm:"default:scheduled" validate:"oneof=scheduled completed bye cancelled"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relaciones
+	Pair1    *TournamentPair `json:"pair1,omitempty" gorm:"foreignKey:Pair1ID"`
+	Pair2    *TournamentPair `json:"pair2,omitempty" gorm:"foreignKey:Pair2ID"`
+	Partner1 *TournamentPair `json:"partner1,omitempty" gorm:"foreignKey:Partner1ID"`
+	Partner2 *TournamentPair `json:"partner2,omitempty" gorm:"foreignKey:Partner2ID"`
+}
+
+type CreateTournamentRequest struct {
+	Name         string `json:"name" validate:"required"`
+	Description  string `json:"description"`
+	Format       string `json:"format" validate:"required,oneof=elimination groups americano mexicano"`
+	CourtIDs     []uint `json:"court_ids" validate:"required,min=1"`
+	StartDate    string `json:"start_date" validate:"required"`     // formato: "2024-03-20"
+	DayStartTime string `json:"day_start_time" validate:"required"` // formato: "09:00"
+	DayEndTime   string `json:"day_end_time" validate:"required"`   // formato: "22:00"
+	MatchMinutes int    `json:"match_minutes,omitempty"`            // por defecto 90
+	MaxEntries   int    `json:"max_entries" validate:"required,min=2"`
+	GroupCount   int    `json:"group_count,omitempty"` // formato groups, por defecto 1
+	Rounds       int    `json:"rounds,omitempty"`      // americano y mexicano, por defecto una ronda por compañero posible
+}
+
+type RegisterTournamentRequest struct {
+	PartnerID *uint `json:"partner_id,omitempty"` // requerido salvo en americano y mexicano
+}
+
+type GetTournamentsRequest struct {
+	Status *string `form:"status"`
+	Format *string `form:"format"`
+}
+
+// RecordTournamentResultRequest carga el marcador de un partido; en americano y mexicano se informa un solo
+// set con los games de cada equipo
+type RecordTournamentResultRequest struct {
+	Sets []SetScore `json:"sets" validate:"required,min=1,max=5"`
+}
+
+type TournamentRound struct {
+	Round   int               `json:"round"`
+	Matches []TournamentMatch `json:"matches"`
+}
+
+type TournamentBracketResponse struct {
+	TournamentID uint              `json:"tournament_id"`
+	Format       string            `json:"format"`
+	Status       string            `json:"status"`
+	Rounds       []TournamentRound `json:"rounds"`
+}
+
+// TournamentStanding es la posición de una pareja (o de un jugador en americano y mexicano)
+type TournamentStanding struct {
+	PairID       uint   `json:"pair_id"`
+	Name         string `json:"name"`
+	GroupNumber  int    `json:"group_number,omitempty"`
+	Played       int    `json:"played"`
+	Won          int    `json:"won"`
+	Lost         int    `json:"lost"`
+	SetsFor      int    `json:"sets_for"`
+	SetsAgainst  int    `json:"sets_against"`
+	GamesFor     int    `json:"games_for"`
+	GamesAgainst int    `json:"games_against"`
+	Points       int    `json:"points"` // partidos ganados, o games ganados en americano y mexicano
+}
+
+type TournamentStandingsResponse struct {
+	TournamentID uint                 `json:"tournament_id"`
+	Format       string               `json:"format"`
+	Standings    []TournamentStanding `json:"standings"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistEntry registra el interés de un usuario en un rango horario de una cancha que está ocupado.
+// Cuando una reserva del rango se cancela o vence, los primeros de la lista reciben un plazo exclusivo para tomar el turno.
+type WaitlistEntry struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	UserID            uint           `json:"user_id" gorm:"not null;index"`
+	CourtID           uint           `json:"court_id" gorm:"not null;index:idx_waitlist_court_date"`
+	Date              time.Time      `json:"date" gorm:"type:date;not null;index:idx_waitlist_court_date"`
+	StartTime         string         `json:"start_time" gorm:"not null"` // inicio del rango aceptable
+	EndTime           string         `json:"end_time" gorm:"not null"`   // fin del rango aceptable
+	Status            string         `json:"status" gorm:"default:waiting" validate:"oneof=waiting notified claimed expired cancelled"`
+	ReleasedBookingID *uint          `json:"released_booking_id,omitempty" gorm:"index"` // reserva liberada cuyo turno se ofreció
+	ClaimStartTime    string         `json:"claim_start_time,omitempty"`
+	ClaimEndTime      string         `json:"claim_end_time,omitempty"`
+	ClaimExpiresAt    *time.Time     `json:"claim_expires_at,omitempty"` // hasta cuándo el turno está reservado para los notificados
+	NotifiedAt        *time.Time     `json:"notified_at,omitempty"`
+	BookingID         *uint          `json:"booking_id,omitempty"` // reserva creada al tomar el turno
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	Court Court `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+}
+
+type JoinWaitlistRequest struct {
+	CourtID   uint   `json:"court_id" validate:"required"`
+	Date      string `json:"date" validate:"required"`       // formato: "2024-01-15"
+	StartTime string `json:"start_time" validate:"required"` // formato: "18:00"
+	EndTime   string `json:"end_time" validate:"required"`   // formato: "22:00"
+}
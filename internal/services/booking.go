@@ -1,249 +1,475 @@
-package services
-
-import (
-	"errors"
-	"time"
-
-	"backend-padel-go/internal/models"
-
-	"gorm.io/gorm"
-)
-
-type BookingService struct {
-	db *gorm.DB
-}
-
-func NewBookingService(db *gorm.DB) *BookingService {
-	return &BookingService{db: db}
-}
-
-func (s *BookingService) CreateBooking(userID uint, req *models.CreateBookingRequest) (*models.BookingResponse, error) {
-	// Verificar que la cancha existe
-	var court models.Court
-	if err := s.db.Where("id = ? AND is_active = ?", req.CourtID, true).First(&court).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("court not found")
-		}
-		return nil, errors.New("failed to fetch court")
-	}
-
-	// Parsear fecha
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		return nil, errors.New("invalid date format")
-	}
-
-	// Verificar disponibilidad
-	available, err := s.checkAvailability(req.CourtID, date, req.StartTime, req.EndTime)
-	if err != nil {
-		return nil, err
-	}
-
-	if !available {
-		return nil, errors.New("time slot not available")
-	}
-
-	// Calcular precio total
-	hours := s.calculateHours(req.StartTime, req.EndTime)
-	totalPrice := hours * court.PricePerHour
-
-	// Crear reserva
-	booking := models.Booking{
-		CourtID:    req.CourtID,
-		UserID:     userID,
-		Date:       date,
-		StartTime:  req.StartTime,
-		EndTime:    req.EndTime,
-		Status:     "pending",
-		TotalPrice: totalPrice,
-		Notes:      req.Notes,
-	}
-
-	if err := s.db.Create(&booking).Error; err != nil {
-		return nil, errors.New("failed to create booking")
-	}
-
-	// Cargar relaciones
-	if err := s.db.Preload("Court").Preload("User").First(&booking, booking.ID).Error; err != nil {
-		return nil, errors.New("failed to load booking with relations")
-	}
-
-	// Crear respuesta
-	response := &models.BookingResponse{
-		ID:         booking.ID,
-		CourtID:    booking.CourtID,
-		UserID:     booking.UserID,
-		Date:       booking.Date,
-		StartTime:  booking.StartTime,
-		EndTime:    booking.EndTime,
-		Status:     booking.Status,
-		TotalPrice: booking.TotalPrice,
-		Notes:      booking.Notes,
-		CreatedAt:  booking.CreatedAt,
-		UpdatedAt:  booking.UpdatedAt,
-		Court: models.CourtInfo{
-			ID:           booking.Court.ID,
-			Name:         booking.Court.Name,
-			Address:      booking.Court.Address,
-			PricePerHour: booking.Court.PricePerHour,
-			Surface:      booking.Court.Surface,
-			HasLighting:  booking.Court.HasLighting,
-			IsIndoor:     booking.Court.IsIndoor,
-		},
-		User: models.UserInfo{
-			ID:        booking.User.ID,
-			FirstName: booking.User.FirstName,
-			LastName:  booking.User.LastName,
-			Email:     booking.User.Email,
-			Phone:     booking.User.Phone,
-		},
-	}
-
-	return response, nil
-}
-
-func (s *BookingService) GetUserBookings(userID uint, filters *models.GetBookingsRequest) ([]*models.BookingResponse, error) {
-	query := s.db.Model(&models.Booking{}).Where("user_id = ?", userID)
-
-	// Aplicar filtros
-	if filters.CourtID != nil {
-		query = query.Where("court_id = ?", *filters.CourtID)
-	}
-	if filters.Date != nil {
-		query = query.Where("date = ?", *filters.Date)
-	}
-	if filters.Status != nil {
-		query = query.Where("status = ?", *filters.Status)
-	}
-
-	var bookings []models.Booking
-	if err := query.Preload("Court").Preload("User").Find(&bookings).Error; err != nil {
-		return nil, errors.New("failed to fetch bookings")
-	}
-
-	// Convertir a respuesta
-	var responses []*models.BookingResponse
-	for _, booking := range bookings {
-		responses = append(responses, &models.BookingResponse{
-			ID:         booking.ID,
-			CourtID:    booking.CourtID,
-			UserID:     booking.UserID,
-			Date:       booking.Date,
-			StartTime:  booking.StartTime,
-			EndTime:    booking.EndTime,
-			Status:     booking.Status,
-			TotalPrice: booking.TotalPrice,
-			Notes:      booking.Notes,
-			CreatedAt:  booking.CreatedAt,
-			UpdatedAt:  booking.UpdatedAt,
-			Court: models.CourtInfo{
-				ID:           booking.Court.ID,
-				Name:         booking.Court.Name,
-				Address:      booking.Court.Address,
-				PricePerHour: booking.Court.PricePerHour,
-				Surface:      booking.Court.Surface,
-				HasLighting:  booking.Court.HasLighting,
-				IsIndoor:     booking.Court.IsIndoor,
-			},
-			User: models.UserInfo{
-				ID:        booking.User.ID,
-				FirstName: booking.User.FirstName,
-				LastName:  booking.User.LastName,
-				Email:     booking.User.Email,
-				Phone:     booking.User.Phone,
-			},
-		})
-	}
-
-	return responses, nil
-}
-
-func (s *BookingService) GetBookingByID(id uint, userID uint) (*models.BookingResponse, error) {
-	var booking models.Booking
-	if err := s.db.Where("id = ? AND user_id = ?", id, userID).Preload("Court").Preload("User").First(&booking).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("booking not found")
-		}
-		return nil, errors.New("failed to fetch booking")
-	}
-
-	response := &models.BookingResponse{
-		ID:         booking.ID,
-		CourtID:    booking.CourtID,
-		UserID:     booking.UserID,
-		Date:       booking.Date,
-		StartTime:  booking.StartTime,
-		EndTime:    booking.EndTime,
-		Status:     booking.Status,
-		TotalPrice: booking.TotalPrice,
-		Notes:      booking.Notes,
-		CreatedAt:  booking.CreatedAt,
-		UpdatedAt:  booking.UpdatedAt,
-		Court: models.CourtInfo{
-			ID:           booking.Court.ID,
-			Name:         booking.Court.Name,
-			Address:      booking.Court.Address,
-			PricePerHour: booking.Court.PricePerHour,
-			Surface:      booking.Court.Surface,
-			HasLighting:  booking.Court.HasLighting,
-			IsIndoor:     booking.Court.IsIndoor,
-		},
-		User: models.UserInfo{
-			ID:        booking.User.ID,
-			FirstName: booking.User.FirstName,
-			LastName:  booking.User.LastName,
-			Email:     booking.User.Email,
-			Phone:     booking.User.Phone,
-		},
-	}
-
-	return response, nil
-}
-
-func (s *BookingService) CancelBooking(id uint, userID uint) error {
-	var booking models.Booking
-	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&booking).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("booking not found")
-		}
-		return errors.New("failed to fetch booking")
-	}
-
-	// Verificar que la reserva se puede cancelar
-	if booking.Status == "cancelled" {
-		return errors.New("booking already cancelled")
-	}
-
-	if booking.Status == "completed" {
-		return errors.New("cannot cancel completed booking")
-	}
-
-	// Actualizar estado
-	if err := s.db.Model(&booking).Update("status", "cancelled").Error; err != nil {
-		return errors.New("failed to cancel booking")
-	}
-
-	return nil
-}
-
-func (s *BookingService) checkAvailability(courtID uint, date time.Time, startTime, endTime string) (bool, error) {
-	// Verificar si hay reservas existentes en el mismo horario
-	var count int64
-	err := s.db.Model(&models.Booking{}).
-		Where("court_id = ? AND date = ? AND status = ?", courtID, date.Format("2006-01-02"), "confirmed").
-		Where("(start_time < ? AND end_time > ?) OR (start_time < ? AND end_time > ?) OR (start_time >= ? AND end_time <= ?)",
-			endTime, startTime, endTime, startTime, startTime, endTime).
-		Count(&count).Error
-
-	if err != nil {
-		return false, errors.New("failed to check availability")
-	}
-
-	return count == 0, nil
-}
-
-func (s *BookingService) calculateHours(startTime, endTime string) float64 {
-	start, _ := time.Parse("15:04", startTime)
-	end, _ := time.Parse("15:04", endTime)
-	return end.Sub(start).Hours()
-}
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookingService struct {
+	db             *gorm.DB
+	paymentService *PaymentService
+}
+
+func NewBookingService(db *gorm.DB, paymentService *PaymentService) *BookingService {
+	return &BookingService{db: db, paymentService: paymentService}
+}
+
+func (s *BookingService) CreateBooking(userID uint, req *models.CreateBookingRequest) (*models.BookingResponse, error) {
+	// Parsear fecha
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format")
+	}
+
+	var booking *models.Booking
+
+	// Verificar disponibilidad y crear la reserva en una única transacción para evitar reservas duplicadas
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		booking, err = s.createOnlineBooking(tx, userID, date, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Cargar relaciones
+	if err := s.db.Preload("Court.Club").Preload("User").First(booking, booking.ID).Error; err != nil {
+		return nil, errors.New("failed to load booking with relations")
+	}
+
+	return s.toBookingResponse(booking), nil
+}
+
+// createOnlineBooking crea la reserva pendiente de pago de un usuario dentro de la transacción recibida
+func (s *BookingService) createOnlineBooking(tx *gorm.DB, userID uint, date time.Time, req *models.CreateBookingRequest) (*models.Booking, error) {
+	cfg := config.Load()
+
+	court, priceBreakdown, err := s.reserveSlot(tx, req.CourtID, date, req.StartTime, req.EndTime, true, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSplitPlayers(court, req.SplitPlayers); err != nil {
+		return nil, err
+	}
+
+	// La reserva pendiente bloquea el turno hasta que se confirme el pago o venza el plazo
+	holdExpiresAt := time.Now().Add(time.Duration(cfg.Booking.PendingHoldMinutes) * time.Minute)
+
+	// Crear reserva
+	booking := models.Booking{
+		CourtID:        req.CourtID,
+		UserID:         &userID,
+		Date:           date,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Status:         "pending",
+		Source:         "online",
+		SplitPlayers:   req.SplitPlayers,
+		TotalPrice:     priceBreakdown.Total,
+		PriceBreakdown: priceBreakdown,
+		Notes:          req.Notes,
+		HoldExpiresAt:  &holdExpiresAt,
+	}
+
+	if err := tx.Create(&booking).Error; err != nil {
+		return nil, errors.New("failed to create booking")
+	}
+
+	// Si el turno le había sido ofrecido desde la lista de espera, queda tomado
+	if err := claimWaitlistSlot(tx, userID, &booking); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// reserveSlot bloquea la cancha, valida el turno contra sus reglas, verifica que esté libre y calcula el precio.
+// Debe ejecutarse dentro de la transacción que crea la reserva para evitar reservas duplicadas.
+// enforceLeadTime exige la anticipación mínima; las reservas cargadas por el club en el momento la omiten.
+// claimantID es el usuario que reserva online, para respetar los turnos ofrecidos a la lista de espera.
+func (s *BookingService) reserveSlot(tx *gorm.DB, courtID uint, date time.Time, startTime, endTime string, enforceLeadTime bool, claimantID *uint) (*models.Court, *models.PriceBreakdown, error) {
+	// Bloquear la fila de la cancha para serializar las reservas concurrentes sobre la misma cancha
+	var court models.Court
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND is_active = ?", courtID, true).Preload("Club").First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("court not found")
+		}
+		return nil, nil, errors.New("failed to fetch court")
+	}
+
+	// Validar el turno contra las reglas de la cancha (horario, duración, granularidad, anticipación)
+	schedule, err := s.validateBookingRules(tx, &court, date, startTime, endTime, enforceLeadTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Verificar disponibilidad
+	available, err := checkAvailability(tx, &court, date, schedule, startTime, endTime, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !available {
+		return nil, nil, errors.New("time slot not available")
+	}
+
+	// Un turno recién liberado queda reservado para los notificados de la lista de espera durante su plazo
+	claimed, err := waitlistClaimBlocks(tx, &court, date, schedule, startTime, endTime, claimantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claimed {
+		return nil, nil, errors.New("time slot not available")
+	}
+
+	// Calcular precio total aplicando las reglas de precio de la cancha
+	start, end, err := schedule.toOperational(startTime, endTime)
+	if err != nil {
+		return nil, nil, ErrInvalidBookingTime
+	}
+	rules, err := loadPricingRules(tx, court.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	priceBreakdown := calculatePrice(&court, rules, date, start, end)
+	if priceBreakdown.Total <= 0 {
+		return nil, nil, errors.New("invalid booking price")
+	}
+
+	return &court, priceBreakdown, nil
+}
+
+func (s *BookingService) GetUserBookings(userID uint, filters *models.GetBookingsRequest) ([]*models.BookingResponse, error) {
+	// Incluye las reservas a las que el usuario fue invitado como participante
+	query := s.db.Model(&models.Booking{}).Scopes(visibleBookings(userID))
+
+	// Aplicar filtros
+	if filters.CourtID != nil {
+		query = query.Where("court_id = ?", *filters.CourtID)
+	}
+	if filters.Date != nil {
+		query = query.Where("date = ?", *filters.Date)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+
+	var bookings []models.Booking
+	if err := query.Preload("Court.Club").Preload("User").Preload("Participants", activeParticipants).Preload("Participants.User").Find(&bookings).Error; err != nil {
+		return nil, errors.New("failed to fetch bookings")
+	}
+
+	// Convertir a respuesta
+	var responses []*models.BookingResponse
+	for i := range bookings {
+		responses = append(responses, s.toBookingResponse(&bookings[i]))
+	}
+
+	return responses, nil
+}
+
+func (s *BookingService) GetBookingByID(id uint, userID uint) (*models.BookingResponse, error) {
+	var booking models.Booking
+	err := s.db.Where("id = ?", id).Scopes(visibleBookings(userID)).
+		Preload("Court.Club").Preload("User").
+		Preload("Participants", activeParticipants).Preload("Participants.User").
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	return s.toBookingResponse(&booking), nil
+}
+
+// CancelBooking cancela una reserva aplicando la política de cancelación de la cancha.
+// Si la reserva tiene un pago aprobado se reembolsa el porcentaje que corresponde según la anticipación.
+func (s *BookingService) CancelBooking(id uint, userID uint) (*models.CancellationResponse, error) {
+	var booking models.Booking
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).Preload("Court.Club").First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	// Verificar que la reserva se puede cancelar
+	if booking.Status == "cancelled" {
+		return nil, errors.New("booking already cancelled")
+	}
+
+	if booking.Status == "completed" {
+		return nil, errors.New("cannot cancel completed booking")
+	}
+
+	if !canTransitionBooking(booking.Status, "cancelled") {
+		return nil, errors.New("booking cannot be cancelled in its current status")
+	}
+
+	// El horario del día permite ubicar correctamente los turnos que comienzan después de la medianoche
+	schedule, err := resolveDaySchedule(s.db, booking.CourtID, booking.Date)
+	if err != nil {
+		return nil, err
+	}
+	startsAt, err := bookingStartsAt(schedule, &booking)
+	if err != nil {
+		return nil, err
+	}
+
+	percent, err := refundPercent(courtCancellationPolicy(&booking.Court), startsAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Actualizar estado
+	if err := transitionBooking(s.db, &booking, "cancelled", &userID, "cancelled by user"); err != nil {
+		if errors.Is(err, ErrInvalidBookingTransition) {
+			return nil, errors.New("booking cannot be cancelled in its current status")
+		}
+		return nil, errors.New("failed to cancel booking")
+	}
+
+	return s.refundCancelledBooking(&booking, percent, "booking cancelled by user"), nil
+}
+
+// SplitBooking divide el pago de una reserva pendiente entre la cantidad de jugadores indicada.
+// Solo puede cambiarse mientras ningún jugador haya iniciado un pago.
+func (s *BookingService) SplitBooking(id uint, userID uint, req *models.SplitBookingRequest) (*models.BookingResponse, error) {
+	var booking models.Booking
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).Preload("Court.Club").Preload("User").First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	if booking.Status != "pending" {
+		return nil, errors.New("booking is not awaiting payment")
+	}
+
+	players := req.Players
+	if players <= 1 {
+		players = 0
+	}
+	if err := validateSplitPlayers(&booking.Court, players); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	s.db.Model(&models.Payment{}).Where("booking_id = ? AND status IN ?", booking.ID, []string{"pending", "approved"}).Count(&count)
+	if count > 0 {
+		return nil, errors.New("booking already has payments")
+	}
+
+	if err := s.db.Model(&booking).Update("split_players", players).Error; err != nil {
+		return nil, errors.New("failed to update booking")
+	}
+	booking.SplitPlayers = players
+
+	return s.toBookingResponse(&booking), nil
+}
+
+// validateSplitPlayers verifica que el pago no se divida entre más jugadores de los que admite la cancha
+func validateSplitPlayers(court *models.Court, players int) error {
+	if players == 0 {
+		return nil
+	}
+	if players < 2 || players > court.MaxPlayers {
+		return errors.New("invalid number of split players")
+	}
+	return nil
+}
+
+// refundCancelledBooking reembolsa el porcentaje indicado de los pagos aprobados de una reserva cancelada.
+// Las reservas sin pago aprobado simplemente liberan el turno.
+func (s *BookingService) refundCancelledBooking(booking *models.Booking, percent float64, reason string) *models.CancellationResponse {
+	response := &models.CancellationResponse{
+		BookingID:     booking.ID,
+		Status:        booking.Status,
+		RefundPercent: percent,
+		RefundStatus:  "none",
+	}
+
+	if percent <= 0 || s.paymentService == nil {
+		return response
+	}
+
+	amount, err := s.paymentService.RefundBookingPayments(booking.ID, percent, reason)
+	if err != nil {
+		// El cambio de estado ya se registró; el reembolso fallido queda informado para reintentarlo
+		log.Printf("failed to refund booking %d: %v", booking.ID, err)
+		response.RefundAmount = amount
+		response.RefundStatus = "failed"
+		return response
+	}
+	if amount > 0 {
+		response.RefundAmount = amount
+		response.RefundStatus = "refunded"
+	}
+
+	return response
+}
+
+// ExpirePendingBookings marca como expired las reservas pendientes cuyo bloqueo venció, liberando el turno
+func (s *BookingService) ExpirePendingBookings() (int64, error) {
+	var bookings []models.Booking
+	if err := s.db.Where("status = ? AND hold_expires_at IS NOT NULL AND hold_expires_at <= ?", "pending", time.Now()).Find(&bookings).Error; err != nil {
+		return 0, errors.New("failed to expire pending bookings")
+	}
+
+	var expired int64
+	for i := range bookings {
+		if err := transitionBooking(s.db, &bookings[i], "expired", nil, "payment hold expired"); err != nil {
+			// Una reserva confirmada mientras tanto ya no está pendiente y se omite
+			if !errors.Is(err, ErrInvalidBookingTransition) {
+				log.Printf("failed to expire booking %d: %v", bookings[i].ID, err)
+			}
+			continue
+		}
+		expired++
+
+		// En una reserva dividida que no se completó a tiempo se devuelven las partes ya pagadas
+		if bookings[i].SplitPlayers > 1 {
+			s.refundCancelledBooking(&bookings[i], 100, "booking expired before all shares were paid")
+		}
+	}
+	return expired, nil
+}
+
+// StartHoldExpirer ejecuta ExpirePendingBookings y ExpireWaitlistClaims periódicamente en segundo plano
+func (s *BookingService) StartHoldExpirer(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := s.ExpirePendingBookings()
+			if err != nil {
+				log.Printf("Hold expirer: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Hold expirer: %d pending bookings expired", expired)
+			}
+
+			claims, err := s.ExpireWaitlistClaims()
+			if err != nil {
+				log.Printf("Hold expirer: %v", err)
+				continue
+			}
+			if claims > 0 {
+				log.Printf("Hold expirer: %d waitlist claims expired", claims)
+			}
+		}
+	}()
+}
+
+// checkAvailability verifica que el turno no se superponga con las reservas que ocupan la cancha.
+// excludeBookingID omite una reserva propia (0 no omite ninguna), para revalidar el turno de una reserva existente.
+func checkAvailability(tx *gorm.DB, court *models.Court, date time.Time, schedule *daySchedule, startTime, endTime string, excludeBookingID uint) (bool, error) {
+	// Obtener las reservas que ocupan la cancha ese día
+	var bookings []models.Booking
+	err := tx.Scopes(blockingBookings).
+		Where("court_id = ? AND date = ? AND id <> ?", court.ID, date.Format("2006-01-02"), excludeBookingID).
+		Find(&bookings).Error
+
+	if err != nil {
+		return false, errors.New("failed to check availability")
+	}
+
+	// Verificar superposición respetando el margen configurado entre reservas
+	bufferMinutes := courtSlotConfig(court).BufferMinutes
+	for _, booking := range bookings {
+		if overlapsWithBuffer(schedule, startTime, endTime, booking.StartTime, booking.EndTime, bufferMinutes) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *BookingService) toBookingResponse(booking *models.Booking) *models.BookingResponse {
+	return &models.BookingResponse{
+		ID:             booking.ID,
+		CourtID:        booking.CourtID,
+		UserID:         booking.UserID,
+		Date:           booking.Date,
+		StartTime:      booking.StartTime,
+		EndTime:        booking.EndTime,
+		Status:         booking.Status,
+		Source:         booking.Source,
+		GuestName:      booking.GuestName,
+		GuestPhone:     booking.GuestPhone,
+		SeriesID:       booking.SeriesID,
+		SplitPlayers:   booking.SplitPlayers,
+		TotalPrice:     booking.TotalPrice,
+		PriceBreakdown: booking.PriceBreakdown,
+		Notes:          booking.Notes,
+		HoldExpiresAt:  booking.HoldExpiresAt,
+		CreatedAt:      booking.CreatedAt,
+		UpdatedAt:      booking.UpdatedAt,
+		Court: models.CourtInfo{
+			ID:           booking.Court.ID,
+			Name:         booking.Court.Name,
+			Address:      courtAddress(&booking.Court),
+			PricePerHour: booking.Court.PricePerHour,
+			Surface:      booking.Court.Surface,
+			HasLighting:  booking.Court.HasLighting,
+			IsIndoor:     booking.Court.IsIndoor,
+		},
+		User: models.UserInfo{
+			ID:        booking.User.ID,
+			FirstName: booking.User.FirstName,
+			LastName:  booking.User.LastName,
+			Email:     booking.User.Email,
+			Phone:     booking.User.Phone,
+		},
+		Participants: toParticipantInfos(booking.Participants),
+	}
+}
+
+// lockSlotForConfirmation bloquea la cancha de una reserva pendiente y verifica que su turno siga siendo suyo:
+// el bloqueo de pago está vigente o, si venció, ninguna otra reserva ni reclamo de la lista de espera lo tomó.
+// Debe llamarse dentro de la transacción que confirma la reserva; la recarga con el estado actual.
+func lockSlotForConfirmation(tx *gorm.DB, booking *models.Booking) (bool, error) {
+	var court models.Court
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Club").First(&court, booking.CourtID).Error; err != nil {
+		return false, errors.New("failed to fetch court")
+	}
+	if err := tx.First(booking, booking.ID).Error; err != nil {
+		return false, errors.New("failed to fetch booking")
+	}
+	if booking.Status != "pending" {
+		return false, nil
+	}
+	if booking.HoldExpiresAt != nil && booking.HoldExpiresAt.After(time.Now()) {
+		return true, nil
+	}
+
+	schedule, err := resolveDaySchedule(tx, court.ID, booking.Date)
+	if err != nil {
+		return false, err
+	}
+	available, err := checkAvailability(tx, &court, booking.Date, schedule, booking.StartTime, booking.EndTime, booking.ID)
+	if err != nil || !available {
+		return false, err
+	}
+	claimed, err := waitlistClaimBlocks(tx, &court, booking.Date, schedule, booking.StartTime, booking.EndTime, booking.UserID)
+	if err != nil {
+		return false, err
+	}
+	return !claimed, nil
+}
+
+// blockingBookings filtra las reservas que ocupan un turno: las confirmadas y las pendientes con bloqueo vigente
+func blockingBookings(db *gorm.DB) *gorm.DB {
+	return db.Where("(status = ? OR (status = ? AND hold_expires_at > ?))", "confirmed", "pending", time.Now())
+}
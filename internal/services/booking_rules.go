@@ -0,0 +1,101 @@
+package services
+
+import (
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BookingRuleError indica que una solicitud de reserva no cumple las reglas de la cancha.
+// Conflict distingue los turnos bien formados que no pueden reservarse (cancha cerrada, fuera de horario)
+// de las solicitudes inválidas.
+type BookingRuleError struct {
+	Code     string
+	Message  string
+	Conflict bool
+}
+
+func (e *BookingRuleError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrInvalidBookingTime   = &BookingRuleError{Code: "INVALID_TIME", Message: "invalid time format"}
+	ErrInvalidBookingRange  = &BookingRuleError{Code: "INVALID_TIME_RANGE", Message: "end time must be after start time"}
+	ErrBookingInPast        = &BookingRuleError{Code: "BOOKING_IN_PAST", Message: "booking date is in the past"}
+	ErrBookingLeadTime      = &BookingRuleError{Code: "LEAD_TIME_NOT_MET", Message: "booking must be made further in advance"}
+	ErrBookingTooShort      = &BookingRuleError{Code: "DURATION_TOO_SHORT", Message: "booking duration is below the minimum"}
+	ErrBookingTooLong       = &BookingRuleError{Code: "DURATION_TOO_LONG", Message: "booking duration exceeds the maximum"}
+	ErrBookingGranularity   = &BookingRuleError{Code: "INVALID_START_TIME", Message: "start time is not aligned to the allowed slot granularity"}
+	ErrDurationNotAllowed   = &BookingRuleError{Code: "DURATION_NOT_ALLOWED", Message: "booking duration not allowed for this court"}
+	ErrCourtClosed          = &BookingRuleError{Code: "COURT_CLOSED", Message: "court closed on this date", Conflict: true}
+	ErrOutsideBusinessHours = &BookingRuleError{Code: "OUTSIDE_BUSINESS_HOURS", Message: "time slot outside business hours", Conflict: true}
+)
+
+// validateBookingRules verifica que el turno solicitado respete el horario de la cancha, las duraciones
+// e intervalos de inicio configurados en la cancha y la anticipación mínima (si enforceLeadTime). Devuelve
+// el horario efectivo del día para reutilizarlo al comprobar la disponibilidad.
+func (s *BookingService) validateBookingRules(tx *gorm.DB, court *models.Court, date time.Time, startTime, endTime string, enforceLeadTime bool) (*daySchedule, error) {
+	cfg := config.Load()
+
+	if _, err := parseClock(startTime); err != nil {
+		return nil, ErrInvalidBookingTime
+	}
+	if _, err := parseClock(endTime); err != nil {
+		return nil, ErrInvalidBookingTime
+	}
+	if startTime == endTime {
+		return nil, ErrInvalidBookingRange
+	}
+
+	// Horario de atención del día, incluyendo cierres y horarios especiales
+	schedule, err := resolveDaySchedule(tx, court.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.IsClosed {
+		return nil, ErrCourtClosed
+	}
+
+	// Minutos del día operativo: los turnos nocturnos pueden terminar (o comenzar) después de la medianoche
+	start, end, err := schedule.toOperational(startTime, endTime)
+	if err != nil {
+		return nil, ErrInvalidBookingTime
+	}
+
+	// Anticipación: el turno no puede haber comenzado ni empezar antes del tiempo mínimo configurado
+	startsAt := time.Date(date.Year(), date.Month(), date.Day(), 0, start, 0, 0, time.Local)
+	now := time.Now()
+	if startsAt.Before(now) {
+		return nil, ErrBookingInPast
+	}
+	if enforceLeadTime && startsAt.Before(now.Add(time.Duration(cfg.Booking.MinLeadTimeMinutes)*time.Minute)) {
+		return nil, ErrBookingLeadTime
+	}
+
+	// Duración
+	duration := end - start
+	if duration < cfg.Booking.MinDurationMinutes {
+		return nil, ErrBookingTooShort
+	}
+	if cfg.Booking.MaxDurationMinutes > 0 && duration > cfg.Booking.MaxDurationMinutes {
+		return nil, ErrBookingTooLong
+	}
+
+	// Duraciones e intervalo de inicio configurados en la cancha (por ejemplo, turnos de 90 minutos cada 30)
+	slotCfg := courtSlotConfig(court)
+	if !slotCfg.allowsDuration(duration) {
+		return nil, ErrDurationNotAllowed
+	}
+	if !schedule.contains(start, end) {
+		return nil, ErrOutsideBusinessHours
+	}
+	if !schedule.alignedStart(start, slotCfg.StepMinutes) {
+		return nil, ErrBookingGranularity
+	}
+
+	return schedule, nil
+}
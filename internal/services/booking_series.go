@@ -0,0 +1,342 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// maxSeriesOccurrences limita la cantidad de fechas que puede generar una serie (un año de turnos semanales)
+const maxSeriesOccurrences = 52
+
+// ErrSeriesConflict indica que algunas fechas de la serie no están disponibles; el reporte detalla cuáles
+var ErrSeriesConflict = errors.New("series has conflicting dates")
+
+// CreateBookingSeries crea un turno fijo y genera una reserva por cada fecha de la serie.
+// La disponibilidad de todas las fechas se verifica antes de crear nada: si hay conflictos se devuelve el
+// reporte por fecha junto con ErrSeriesConflict, salvo que SkipConflicts pida crear solo las fechas libres.
+// Las reservas se crean pendientes de pago y cada una bloquea su turno hasta poco antes de su fecha.
+func (s *BookingService) CreateBookingSeries(userID uint, req *models.CreateBookingSeriesRequest) (*models.BookingSeriesResponse, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end date format")
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	step := 7
+	switch req.Frequency {
+	case "weekly":
+	case "biweekly":
+		step = 14
+	default:
+		return nil, errors.New("invalid frequency")
+	}
+
+	exceptions := make(map[string]bool, len(req.Exceptions))
+	for _, exception := range req.Exceptions {
+		if _, err := time.Parse("2006-01-02", exception); err != nil {
+			return nil, errors.New("invalid exception date format")
+		}
+		exceptions[exception] = true
+	}
+
+	var dates []time.Time
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, step) {
+		dates = append(dates, date)
+	}
+	if len(dates) > maxSeriesOccurrences {
+		return nil, errors.New("series exceeds the maximum number of occurrences")
+	}
+
+	series := models.BookingSeries{
+		CourtID:    req.CourtID,
+		UserID:     userID,
+		Frequency:  req.Frequency,
+		DayOfWeek:  int(startDate.Weekday()),
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Exceptions: req.Exceptions,
+		Status:     "active",
+		Notes:      req.Notes,
+	}
+	response := &models.BookingSeriesResponse{Occurrences: make([]models.SeriesOccurrence, 0, len(dates))}
+	now := time.Now()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&series).Error; err != nil {
+			return errors.New("failed to create booking series")
+		}
+
+		for _, date := range dates {
+			occurrence := models.SeriesOccurrence{Date: date.Format("2006-01-02")}
+			if exceptions[occurrence.Date] {
+				occurrence.Status = "skipped"
+				occurrence.Reason = "exception date"
+				response.Occurrences = append(response.Occurrences, occurrence)
+				continue
+			}
+
+			_, priceBreakdown, err := s.reserveSlot(tx, req.CourtID, date, req.StartTime, req.EndTime, true, &userID)
+			if err != nil {
+				if !isSeriesConflict(err) {
+					return err
+				}
+				occurrence.Status = "conflict"
+				occurrence.Reason = err.Error()
+				response.Occurrences = append(response.Occurrences, occurrence)
+				response.Conflicts++
+				continue
+			}
+
+			// Cada fecha queda pendiente de pago: se abona por MercadoPago o el club registra el cobro, y si el
+			// bloqueo de esa fecha vence sin pago solo se libera ese turno
+			holdExpiresAt, err := seriesHoldExpiresAt(tx, req.CourtID, date, req.StartTime, req.EndTime, now)
+			if err != nil {
+				return err
+			}
+			seriesID := series.ID
+			booking := models.Booking{
+				CourtID:        req.CourtID,
+				UserID:         &userID,
+				Date:           date,
+				StartTime:      req.StartTime,
+				EndTime:        req.EndTime,
+				Status:         "pending",
+				Source:         "online",
+				SeriesID:       &seriesID,
+				TotalPrice:     priceBreakdown.Total,
+				PriceBreakdown: priceBreakdown,
+				Notes:          req.Notes,
+				HoldExpiresAt:  &holdExpiresAt,
+			}
+			if err := tx.Create(&booking).Error; err != nil {
+				return errors.New("failed to create booking")
+			}
+			if err := claimWaitlistSlot(tx, userID, &booking); err != nil {
+				return err
+			}
+
+			occurrence.Status = "created"
+			occurrence.BookingID = &booking.ID
+			response.Occurrences = append(response.Occurrences, occurrence)
+			response.Created++
+		}
+
+		// Si hay conflictos y no se pidió omitirlos se revierte toda la serie para que el usuario ajuste las fechas
+		if response.Conflicts > 0 && !req.SkipConflicts {
+			return ErrSeriesConflict
+		}
+		if response.Created == 0 {
+			return errors.New("series has no available dates")
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrSeriesConflict) {
+			// Las reservas se revirtieron; el reporte solo informa qué fechas están ocupadas
+			for i := range response.Occurrences {
+				if response.Occurrences[i].Status == "created" {
+					response.Occurrences[i].Status = "available"
+					response.Occurrences[i].BookingID = nil
+				}
+			}
+			response.Created = 0
+			return response, err
+		}
+		return nil, err
+	}
+
+	// Las fechas en conflicto omitidas quedan registradas como excepciones de la serie
+	if response.Conflicts > 0 {
+		for _, occurrence := range response.Occurrences {
+			if occurrence.Status == "conflict" {
+				series.Exceptions = append(series.Exceptions, occurrence.Date)
+			}
+		}
+		if err := s.db.Model(&series).Update("exceptions", marshalExceptions(series.Exceptions)).Error; err != nil {
+			log.Printf("failed to record exceptions for series %d: %v", series.ID, err)
+		}
+	}
+
+	response.Series = &series
+	return response, nil
+}
+
+// seriesHoldExpiresAt devuelve el vencimiento del bloqueo de una fecha de la serie: la fecha debe pagarse
+// BOOKING_SERIES_PAYMENT_LEAD horas antes de su inicio, y nunca vence antes que el bloqueo de una reserva online
+func seriesHoldExpiresAt(tx *gorm.DB, courtID uint, date time.Time, startTime, endTime string, now time.Time) (time.Time, error) {
+	cfg := config.Load()
+	minimum := now.Add(time.Duration(cfg.Booking.PendingHoldMinutes) * time.Minute)
+
+	schedule, err := resolveDaySchedule(tx, courtID, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	startsAt, err := bookingStartsAt(schedule, &models.Booking{Date: date, StartTime: startTime, EndTime: endTime})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	due := startsAt.Add(-time.Duration(cfg.Booking.SeriesPaymentLeadHours) * time.Hour)
+	if due.Before(minimum) {
+		return minimum, nil
+	}
+	return due, nil
+}
+
+// isSeriesConflict distingue las fechas que no pueden reservarse de los errores que invalidan toda la serie
+func isSeriesConflict(err error) bool {
+	if err.Error() == "time slot not available" {
+		return true
+	}
+	var ruleErr *BookingRuleError
+	if errors.As(err, &ruleErr) {
+		return ruleErr.Conflict || ruleErr == ErrBookingInPast || ruleErr == ErrBookingLeadTime
+	}
+	return false
+}
+
+func (s *BookingService) GetUserSeries(userID uint) ([]models.BookingSeries, error) {
+	var series []models.BookingSeries
+	if err := s.db.Where("user_id = ?", userID).Preload("Court").Order("start_date DESC").Find(&series).Error; err != nil {
+		return nil, errors.New("failed to fetch booking series")
+	}
+	return series, nil
+}
+
+func (s *BookingService) GetSeriesByID(id uint, userID uint) (*models.BookingSeries, error) {
+	var series models.BookingSeries
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).
+		Preload("Court").
+		Preload("Bookings", func(db *gorm.DB) *gorm.DB { return db.Order("date ASC") }).
+		First(&series).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking series not found")
+		}
+		return nil, errors.New("failed to fetch booking series")
+	}
+	return &series, nil
+}
+
+// CancelSeriesOccurrence cancela una única fecha de la serie con la política de cancelación habitual
+// y la registra como excepción
+func (s *BookingService) CancelSeriesOccurrence(seriesID, bookingID, userID uint) (*models.CancellationResponse, error) {
+	series, err := s.findUserSeries(seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking models.Booking
+	if err := s.db.Where("id = ? AND series_id = ?", bookingID, series.ID).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	response, err := s.CancelBooking(booking.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions := append(series.Exceptions, booking.Date.Format("2006-01-02"))
+	if err := s.db.Model(series).Update("exceptions", marshalExceptions(exceptions)).Error; err != nil {
+		log.Printf("failed to record exception for series %d: %v", series.ID, err)
+	}
+
+	return response, nil
+}
+
+// CancelSeriesRemainder cancela las fechas de la serie a partir de la fecha indicada (por defecto, hoy).
+// Las fechas que ya comenzaron se conservan; si no quedan fechas futuras la serie pasa a cancelled.
+func (s *BookingService) CancelSeriesRemainder(seriesID, userID uint, req *models.CancelSeriesRequest) (*models.CancelSeriesResponse, error) {
+	series, err := s.findUserSeries(seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == "cancelled" {
+		return nil, errors.New("booking series already cancelled")
+	}
+
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	fromDate := today
+	if req.FromDate != "" {
+		fromDate, err = time.Parse("2006-01-02", req.FromDate)
+		if err != nil {
+			return nil, errors.New("invalid date format")
+		}
+		if fromDate.Before(today) {
+			fromDate = today
+		}
+	}
+
+	var bookings []models.Booking
+	err = s.db.Where("series_id = ? AND date >= ? AND status IN ?", series.ID, fromDate.Format("2006-01-02"), []string{"pending", "confirmed"}).
+		Order("date ASC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch bookings")
+	}
+
+	response := &models.CancelSeriesResponse{SeriesID: series.ID, Cancellations: []models.CancellationResponse{}}
+	for _, booking := range bookings {
+		cancellation, err := s.CancelBooking(booking.ID, userID)
+		if err != nil {
+			// Un turno en curso no puede cancelarse; el resto de la serie se cancela igualmente
+			log.Printf("skipping booking %d of series %d: %v", booking.ID, series.ID, err)
+			continue
+		}
+		response.Cancellations = append(response.Cancellations, *cancellation)
+	}
+
+	updates := map[string]interface{}{}
+	if fromDate.After(series.StartDate) {
+		updates["end_date"] = fromDate.AddDate(0, 0, -1)
+	}
+	if !fromDate.After(today) || !fromDate.After(series.StartDate) {
+		updates["status"] = "cancelled"
+	}
+	if len(updates) > 0 {
+		if err := s.db.Model(series).Updates(updates).Error; err != nil {
+			return nil, errors.New("failed to update booking series")
+		}
+		if _, ok := updates["status"]; ok {
+			series.Status = "cancelled"
+		}
+	}
+
+	response.Status = series.Status
+	return response, nil
+}
+
+func (s *BookingService) findUserSeries(id, userID uint) (*models.BookingSeries, error) {
+	var series models.BookingSeries
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking series not found")
+		}
+		return nil, errors.New("failed to fetch booking series")
+	}
+	return &series, nil
+}
+
+// marshalExceptions serializa las excepciones para Updates, que no aplica el serializer JSON del modelo
+func marshalExceptions(exceptions []string) string {
+	data, _ := json.Marshal(exceptions)
+	return string(data)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+func TestSeriesOccurrencesWaitForPayment(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	service := NewBookingService(db, NewPaymentService(db, &fakeGateway{}))
+
+	start := testutil.Day(3)
+	req := &models.CreateBookingSeriesRequest{
+		CourtID:   court.ID,
+		Frequency: "weekly",
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   start.AddDate(0, 0, 14).Format("2006-01-02"),
+		StartTime: "18:00",
+		EndTime:   "19:30",
+	}
+	response, err := service.CreateBookingSeries(player.ID, req)
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	if response.Created != 3 {
+		t.Fatalf("expected 3 occurrences, got %d", response.Created)
+	}
+
+	var bookings []models.Booking
+	db.Where("series_id = ?", response.Series.ID).Order("date ASC").Find(&bookings)
+	for _, booking := range bookings {
+		// Cada fecha bloquea su turno hasta 24 horas antes de su inicio
+		startsAt := time.Date(booking.Date.Year(), booking.Date.Month(), booking.Date.Day(), 18, 0, 0, 0, time.Local)
+		if booking.Status != "pending" || booking.HoldExpiresAt == nil || !booking.HoldExpiresAt.Equal(startsAt.Add(-24*time.Hour)) {
+			t.Fatalf("expected every occurrence pending until a day before it starts, got %s with hold %v", booking.Status, booking.HoldExpiresAt)
+		}
+	}
+	var payments int64
+	db.Model(&models.Payment{}).Count(&payments)
+	if payments != 0 {
+		t.Fatalf("expected no payments recorded, got %d", payments)
+	}
+
+	// El expirador no libera la serie recién creada
+	if expired, err := service.ExpirePendingBookings(); err != nil || expired != 0 {
+		t.Fatalf("expected no occurrence to expire, got %d (%v)", expired, err)
+	}
+
+	// Al llegar el vencimiento de la primera fecha impaga solo se libera ese turno
+	db.Model(&models.Booking{}).Where("id = ?", bookings[0].ID).Update("hold_expires_at", time.Now().Add(-time.Minute))
+	if expired, err := service.ExpirePendingBookings(); err != nil || expired != 1 {
+		t.Fatalf("expected only the first occurrence to expire, got %d (%v)", expired, err)
+	}
+	var pending int64
+	db.Model(&models.Booking{}).Where("series_id = ? AND status = ?", response.Series.ID, "pending").Count(&pending)
+	if pending != 2 {
+		t.Fatalf("expected the other occurrences to stay pending, got %d", pending)
+	}
+	other := testutil.CreateUser(t, db, "other@test.com", "user")
+	if _, err := service.CreateBooking(other.ID, &models.CreateBookingRequest{CourtID: court.ID, Date: req.StartDate, StartTime: "18:00", EndTime: "19:30"}); err != nil {
+		t.Fatalf("expected the unpaid occurrence to release the slot: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// bookingTransitions define los cambios de estado permitidos para una reserva:
+//
+//	pending   → confirmed (pago aprobado), cancelled, expired (venció el bloqueo sin pago)
+//	confirmed → completed (terminó el turno), cancelled, no_show (no se presentó)
+//
+// cancelled, completed, no_show y expired son estados finales.
+var bookingTransitions = map[string][]string{
+	"pending":   {"confirmed", "cancelled", "expired"},
+	"confirmed": {"completed", "cancelled", "no_show"},
+}
+
+var ErrInvalidBookingTransition = errors.New("invalid booking status transition")
+
+// canTransitionBooking indica si una reserva puede pasar del estado from al estado to
+func canTransitionBooking(from, to string) bool {
+	for _, allowed := range bookingTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionBooking cambia el estado de una reserva validando la transición y registra el cambio en el historial.
+// actorID es nil cuando el cambio lo realiza el sistema (vencimientos, pagos, finalización automática).
+// La actualización se condiciona al estado leído para no pisar un cambio concurrente.
+func transitionBooking(tx *gorm.DB, booking *models.Booking, to string, actorID *uint, reason string) error {
+	from := booking.Status
+	if !canTransitionBooking(from, to) {
+		return ErrInvalidBookingTransition
+	}
+
+	updates := map[string]interface{}{"status": to}
+	if from == "pending" {
+		// Al salir de pending la reserva deja de depender del bloqueo temporal
+		updates["hold_expires_at"] = nil
+	}
+
+	result := tx.Model(&models.Booking{}).Where("id = ? AND status = ?", booking.ID, from).Updates(updates)
+	if result.Error != nil {
+		return errors.New("failed to update booking status")
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidBookingTransition
+	}
+
+	history := models.BookingStatusHistory{
+		BookingID:  booking.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Reason:     reason,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return errors.New("failed to record booking status history")
+	}
+
+	booking.Status = to
+	if from == "pending" {
+		booking.HoldExpiresAt = nil
+	}
+
+	// El turno liberado se ofrece primero a la lista de espera. La oferta corre en un savepoint: si falla se
+	// revierten sus propios avisos, pero no el cambio de estado
+	if to == "cancelled" || to == "expired" {
+		if err := tx.Transaction(func(offer *gorm.DB) error { return offerReleasedSlot(offer, booking) }); err != nil {
+			log.Printf("failed to offer booking %d slot to waitlist: %v", booking.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetBookingHistory devuelve los cambios de estado de una reserva del usuario
+func (s *BookingService) GetBookingHistory(id uint, userID uint) ([]models.BookingStatusHistory, error) {
+	var booking models.Booking
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	var history []models.BookingStatusHistory
+	if err := s.db.Where("booking_id = ?", booking.ID).Order("created_at, id").Find(&history).Error; err != nil {
+		return nil, errors.New("failed to fetch booking history")
+	}
+	return history, nil
+}
+
+// CompletePastBookings marca como completed las reservas confirmadas cuyo turno ya terminó
+func (s *BookingService) CompletePastBookings() (int64, error) {
+	now := time.Now()
+
+	// Los turnos nocturnos de ayer pueden terminar hoy después de la medianoche
+	var bookings []models.Booking
+	if err := s.db.Where("status = ? AND date <= ?", "confirmed", now.Format("2006-01-02")).Find(&bookings).Error; err != nil {
+		return 0, errors.New("failed to fetch confirmed bookings")
+	}
+
+	schedules := make(map[string]*daySchedule)
+	var completed int64
+	for i := range bookings {
+		booking := &bookings[i]
+
+		key := fmt.Sprintf("%d|%s", booking.CourtID, booking.Date.Format("2006-01-02"))
+		schedule, ok := schedules[key]
+		if !ok {
+			var err error
+			schedule, err = resolveDaySchedule(s.db, booking.CourtID, booking.Date)
+			if err != nil {
+				// Sin horario se interpretan los horarios tal cual fueron reservados
+				schedule = nil
+			}
+			schedules[key] = schedule
+		}
+
+		endsAt, err := bookingEndsAt(schedule, booking)
+		if err != nil || endsAt.After(now) {
+			continue
+		}
+
+		if err := transitionBooking(s.db, booking, "completed", nil, "booking time finished"); err != nil {
+			if !errors.Is(err, ErrInvalidBookingTransition) {
+				log.Printf("failed to complete booking %d: %v", booking.ID, err)
+			}
+			continue
+		}
+		completed++
+	}
+
+	return completed, nil
+}
+
+// StartCompletionScheduler ejecuta CompletePastBookings periódicamente en segundo plano
+func (s *BookingService) StartCompletionScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			completed, err := s.CompletePastBookings()
+			if err != nil {
+				log.Printf("Completion scheduler: %v", err)
+				continue
+			}
+			if completed > 0 {
+				log.Printf("Completion scheduler: %d bookings completed", completed)
+			}
+		}
+	}()
+}
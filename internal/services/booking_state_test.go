@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestBookingTransitions(t *testing.T) {
+	statuses := []string{"pending", "confirmed", "cancelled", "completed", "no_show", "expired"}
+	allowed := map[string]bool{
+		"pending>confirmed":   true,
+		"pending>cancelled":   true,
+		"pending>expired":     true,
+		"confirmed>completed": true,
+		"confirmed>cancelled": true,
+		"confirmed>no_show":   true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got := canTransitionBooking(from, to); got != allowed[from+">"+to] {
+				t.Errorf("%s -> %s: expected allowed=%v, got %v", from, to, allowed[from+">"+to], got)
+			}
+		}
+	}
+}
+
+func TestTransitionBookingRecordsHistoryAndRejectsStaleStatus(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, testutil.Day(3), "18:00", "19:30", "pending", nil)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"pending to confirmed", "pending", "confirmed", false},
+		{"stale pending read", "pending", "cancelled", true},
+		{"final state", "expired", "confirmed", true},
+		{"confirmed to completed", "confirmed", "completed", false},
+		{"completed is final", "completed", "cancelled", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// La reserva se transiciona según el estado leído, aunque la base ya lo haya cambiado
+			read := booking
+			read.Status = tt.from
+			err := transitionBooking(db, &read, tt.to, &owner.ID, tt.name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidBookingTransition) {
+					t.Fatalf("expected ErrInvalidBookingTransition, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transition: %v", err)
+			}
+			var stored models.Booking
+			db.First(&stored, booking.ID)
+			if stored.Status != tt.to {
+				t.Fatalf("expected status %s, got %s", tt.to, stored.Status)
+			}
+		})
+	}
+
+	var history []models.BookingStatusHistory
+	db.Where("booking_id = ?", booking.ID).Order("id").Find(&history)
+	if len(history) != 2 || history[0].ToStatus != "confirmed" || history[1].ToStatus != "completed" {
+		t.Fatalf("expected only the two applied transitions in the history, got %+v", history)
+	}
+}
+
+func TestFailedWaitlistOfferKeepsTheTransition(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	date := testutil.Day(3)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, date, "18:00", "19:30", "confirmed", nil)
+
+	var entries []models.WaitlistEntry
+	for _, email := range []string{"first@test.com", "second@test.com"} {
+		waiting := testutil.CreateUser(t, db, email, "user")
+		entry := models.WaitlistEntry{UserID: waiting.ID, CourtID: court.ID, Date: date, StartTime: "17:00", EndTime: "21:00", Status: "waiting"}
+		if err := db.Omit("Court").Create(&entry).Error; err != nil {
+			t.Fatalf("create waitlist entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	// Sin tabla de notificaciones el aviso falla después de marcar la primera entrada como notificada
+	if err := db.Migrator().DropTable(&models.Notification{}); err != nil {
+		t.Fatalf("drop notifications: %v", err)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return transitionBooking(tx, &booking, "cancelled", &player.ID, "cancelled by user")
+	})
+	if err != nil {
+		t.Fatalf("cancel booking: %v", err)
+	}
+
+	db.First(&booking, booking.ID)
+	if booking.Status != "cancelled" {
+		t.Fatalf("expected the cancellation to be kept, got %s", booking.Status)
+	}
+	for _, entry := range entries {
+		db.First(&entry, entry.ID)
+		if entry.Status != "waiting" || entry.ReleasedBookingID != nil {
+			t.Fatalf("expected the partial offer to be rolled back, got entry %d %s", entry.ID, entry.Status)
+		}
+	}
+}
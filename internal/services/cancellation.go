@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"backend-padel-go/internal/models"
+)
+
+// defaultCancellationPolicy se aplica a las canchas que no definieron una política propia
+var defaultCancellationPolicy = models.CancellationPolicy{
+	FreeCancellationHours: 24,
+	RefundTiers: []models.RefundTier{
+		{MinHoursBefore: 12, RefundPercent: 50},
+	},
+	NoShowRefundPercent: 0,
+}
+
+// courtCancellationPolicy devuelve la política de cancelación de la cancha, la de su club o la política por defecto
+func courtCancellationPolicy(court *models.Court) models.CancellationPolicy {
+	if court.CancellationPolicy != nil {
+		return withDefaultRules(*court.CancellationPolicy)
+	}
+	// Sin política propia, la cancha de un club usa la del club
+	if court.Club != nil && court.Club.CancellationPolicy != nil {
+		return withDefaultRules(*court.Club.CancellationPolicy)
+	}
+	return defaultCancellationPolicy
+}
+
+// withDefaultRules completa con las reglas por defecto una política anterior guardada como texto libre
+func withDefaultRules(policy models.CancellationPolicy) models.CancellationPolicy {
+	if !policy.FreeText {
+		return policy
+	}
+	rules := defaultCancellationPolicy
+	rules.Description = policy.Description
+	return rules
+}
+
+func validateCancellationPolicy(policy *models.CancellationPolicy) error {
+	if policy.FreeCancellationHours < 0 {
+		return errors.New("free_cancellation_hours must be positive")
+	}
+	if policy.NoShowRefundPercent < 0 || policy.NoShowRefundPercent > 100 {
+		return errors.New("no_show_refund_percent must be between 0 and 100")
+	}
+	for _, tier := range policy.RefundTiers {
+		if tier.MinHoursBefore < 0 {
+			return errors.New("refund tier min_hours_before must be positive")
+		}
+		if tier.RefundPercent < 0 || tier.RefundPercent > 100 {
+			return errors.New("refund tier refund_percent must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// refundPercent calcula el porcentaje a reembolsar al cancelar con la anticipación indicada.
+// Con más horas que la ventana gratuita se reembolsa todo; si no, se usa el tramo de mayor
+// anticipación mínima alcanzada. Un turno ya comenzado no puede cancelarse.
+func refundPercent(policy models.CancellationPolicy, startsAt, now time.Time) (float64, error) {
+	if !now.Before(startsAt) {
+		return 0, errors.New("cannot cancel a booking that already started")
+	}
+
+	hoursBefore := startsAt.Sub(now).Hours()
+	if hoursBefore >= float64(policy.FreeCancellationHours) {
+		return 100, nil
+	}
+
+	tiers := append([]models.RefundTier(nil), policy.RefundTiers...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinHoursBefore > tiers[j].MinHoursBefore
+	})
+	for _, tier := range tiers {
+		if hoursBefore >= float64(tier.MinHoursBefore) {
+			return tier.RefundPercent, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// bookingStartsAt devuelve el momento de inicio de una reserva, considerando los turnos posteriores a la medianoche
+func bookingStartsAt(schedule *daySchedule, booking *models.Booking) (time.Time, error) {
+	start, _, err := schedule.toOperational(booking.StartTime, booking.EndTime)
+	if err != nil {
+		return time.Time{}, errors.New("invalid booking time")
+	}
+	return time.Date(booking.Date.Year(), booking.Date.Month(), booking.Date.Day(), 0, start, 0, 0, time.Local), nil
+}
+
+// bookingEndsAt devuelve el momento de finalización de una reserva, considerando los turnos que terminan después de la medianoche
+func bookingEndsAt(schedule *daySchedule, booking *models.Booking) (time.Time, error) {
+	_, end, err := schedule.toOperational(booking.StartTime, booking.EndTime)
+	if err != nil {
+		return time.Time{}, errors.New("invalid booking time")
+	}
+	return time.Date(booking.Date.Year(), booking.Date.Month(), booking.Date.Day(), 0, end, 0, 0, time.Local), nil
+}
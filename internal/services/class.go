@@ -0,0 +1,757 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ClassService struct {
+	db             *gorm.DB
+	bookingService *BookingService
+}
+
+func NewClassService(db *gorm.DB, bookingService *BookingService) *ClassService {
+	return &ClassService{db: db, bookingService: bookingService}
+}
+
+// CreateCoach da de alta un profesor del club
+func (s *ClassService) CreateCoach(ownerID uint, req *models.CreateCoachRequest) (*models.Coach, error) {
+	if req.UserID != nil {
+		var user models.User
+		if err := s.db.First(&user, *req.UserID).Error; err != nil {
+			return nil, errors.New("user not found")
+		}
+	}
+
+	coach := models.Coach{
+		OwnerID:  ownerID,
+		UserID:   req.UserID,
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Bio:      req.Bio,
+		IsActive: true,
+	}
+	if err := s.db.Create(&coach).Error; err != nil {
+		return nil, errors.New("failed to create coach")
+	}
+	return &coach, nil
+}
+
+// UpdateCoach actualiza los datos de un profesor del club
+func (s *ClassService) UpdateCoach(coachID uint, ownerID uint, req *models.UpdateCoachRequest) (*models.Coach, error) {
+	var coach models.Coach
+	if err := s.db.Where("id = ? AND owner_id = ?", coachID, ownerID).First(&coach).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("coach not found")
+		}
+		return nil, errors.New("failed to fetch coach")
+	}
+
+	if req.Name != nil {
+		coach.Name = *req.Name
+	}
+	if req.Email != nil {
+		coach.Email = *req.Email
+	}
+	if req.Phone != nil {
+		coach.Phone = *req.Phone
+	}
+	if req.Bio != nil {
+		coach.Bio = *req.Bio
+	}
+	if req.UserID != nil {
+		var user models.User
+		if err := s.db.First(&user, *req.UserID).Error; err != nil {
+			return nil, errors.New("user not found")
+		}
+		coach.UserID = req.UserID
+	}
+	if req.IsActive != nil {
+		coach.IsActive = *req.IsActive
+	}
+
+	if err := s.db.Save(&coach).Error; err != nil {
+		return nil, errors.New("failed to update coach")
+	}
+	return &coach, nil
+}
+
+// GetCoaches lista los profesores del club
+func (s *ClassService) GetCoaches(ownerID uint) ([]models.Coach, error) {
+	var coaches []models.Coach
+	if err := s.db.Where("owner_id = ?", ownerID).Order("name").Find(&coaches).Error; err != nil {
+		return nil, errors.New("failed to fetch coaches")
+	}
+	return coaches, nil
+}
+
+// CreateClass crea una clase semanal y reserva el turno de la cancha para cada fecha. Igual que los turnos
+// fijos, si alguna fecha está ocupada se devuelve el reporte con ErrSeriesConflict salvo que SkipConflicts
+// pida crear solo las fechas libres.
+func (s *ClassService) CreateClass(ownerID uint, req *models.CreateClassRequest) (*models.ClassScheduleResponse, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end date format")
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+	if req.Capacity < 1 {
+		return nil, errors.New("capacity must be at least 1")
+	}
+	if req.Price < 0 {
+		return nil, errors.New("invalid price")
+	}
+
+	exceptions := make(map[string]bool, len(req.Exceptions))
+	for _, exception := range req.Exceptions {
+		if _, err := time.Parse("2006-01-02", exception); err != nil {
+			return nil, errors.New("invalid exception date format")
+		}
+		exceptions[exception] = true
+	}
+
+	var dates []time.Time
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 7) {
+		dates = append(dates, date)
+	}
+	if len(dates) > maxSeriesOccurrences {
+		return nil, errors.New("class exceeds the maximum number of sessions")
+	}
+
+	var coach models.Coach
+	if err := s.db.Where("id = ? AND owner_id = ? AND is_active = ?", req.CoachID, ownerID, true).First(&coach).Error; err != nil {
+		return nil, errors.New("coach not found")
+	}
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", req.CourtID, ownerID).First(&court).Error; err != nil {
+		return nil, errors.New("court not found")
+	}
+
+	class := models.Class{
+		OwnerID:     ownerID,
+		CoachID:     coach.ID,
+		CourtID:     court.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Level:       req.Level,
+		Capacity:    req.Capacity,
+		Price:       roundPrice(req.Price),
+		DayOfWeek:   int(startDate.Weekday()),
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Status:      "active",
+	}
+	response := &models.ClassScheduleResponse{Occurrences: make([]models.SeriesOccurrence, 0, len(dates))}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&class).Error; err != nil {
+			return errors.New("failed to create class")
+		}
+
+		notes := fmt.Sprintf("Class %s with %s", class.Name, coach.Name)
+		for _, date := range dates {
+			occurrence := models.SeriesOccurrence{Date: date.Format("2006-01-02")}
+			if exceptions[occurrence.Date] {
+				occurrence.Status = "skipped"
+				occurrence.Reason = "exception date"
+				response.Occurrences = append(response.Occurrences, occurrence)
+				continue
+			}
+
+			booking, err := s.bookingService.reserveEventSlot(tx, ownerID, court.ID, date, req.StartTime, req.EndTime, "class", notes)
+			if err != nil {
+				if !isSeriesConflict(err) {
+					return err
+				}
+				occurrence.Status = "conflict"
+				occurrence.Reason = err.Error()
+				response.Occurrences = append(response.Occurrences, occurrence)
+				response.Conflicts++
+				continue
+			}
+
+			session := models.ClassSession{
+				ClassID:   class.ID,
+				BookingID: booking.ID,
+				Date:      date,
+				StartTime: req.StartTime,
+				EndTime:   req.EndTime,
+				Status:    "scheduled",
+			}
+			if err := tx.Create(&session).Error; err != nil {
+				return errors.New("failed to create class session")
+			}
+
+			occurrence.Status = "created"
+			occurrence.BookingID = &booking.ID
+			response.Occurrences = append(response.Occurrences, occurrence)
+			response.Created++
+		}
+
+		if response.Conflicts > 0 && !req.SkipConflicts {
+			return ErrSeriesConflict
+		}
+		if response.Created == 0 {
+			return errors.New("class has no available dates")
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrSeriesConflict) {
+			// Los turnos se revirtieron; el reporte solo informa qué fechas están ocupadas
+			for i := range response.Occurrences {
+				if response.Occurrences[i].Status == "created" {
+					response.Occurrences[i].Status = "available"
+					response.Occurrences[i].BookingID = nil
+				}
+			}
+			response.Created = 0
+			return response, err
+		}
+		return nil, err
+	}
+
+	class.Coach = coach
+	class.Court = court
+	response.Class = &class
+	return response, nil
+}
+
+// GetClasses lista las clases activas con su profesor y cancha
+func (s *ClassService) GetClasses(filters *models.GetClassesRequest) ([]models.Class, error) {
+	query := s.db.Where("status = ? AND end_date >= ?", "active", time.Now().Format("2006-01-02")).
+		Preload("Coach").Preload("Court")
+	if filters.CourtID != nil {
+		query = query.Where("court_id = ?", *filters.CourtID)
+	}
+	if filters.CoachID != nil {
+		query = query.Where("coach_id = ?", *filters.CoachID)
+	}
+	if filters.Level != "" {
+		query = query.Where("level = ?", filters.Level)
+	}
+
+	var classes []models.Class
+	if err := query.Order("day_of_week, start_time").Find(&classes).Error; err != nil {
+		return nil, errors.New("failed to fetch classes")
+	}
+	return classes, nil
+}
+
+// GetOwnerClasses lista las clases del club, incluidas las canceladas y finalizadas
+func (s *ClassService) GetOwnerClasses(ownerID uint) ([]models.Class, error) {
+	var classes []models.Class
+	err := s.db.Where("owner_id = ?", ownerID).Preload("Coach").Preload("Court").
+		Order("start_date DESC").
+		Find(&classes).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch classes")
+	}
+	return classes, nil
+}
+
+// GetClassSessions devuelve las próximas fechas de una clase con los cupos libres
+func (s *ClassService) GetClassSessions(classID uint) ([]models.ClassSessionResponse, error) {
+	var class models.Class
+	if err := s.db.First(&class, classID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, errors.New("failed to fetch class")
+	}
+
+	var sessions []models.ClassSession
+	err := s.db.Where("class_id = ? AND date >= ?", class.ID, time.Now().Format("2006-01-02")).
+		Order("date").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch class sessions")
+	}
+
+	response := make([]models.ClassSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		available := class.Capacity - session.Enrolled
+		if available < 0 || session.Status != "scheduled" {
+			available = 0
+		}
+		response = append(response, models.ClassSessionResponse{
+			ID:        session.ID,
+			ClassID:   class.ID,
+			Date:      session.Date.Format("2006-01-02"),
+			StartTime: session.StartTime,
+			EndTime:   session.EndTime,
+			Capacity:  class.Capacity,
+			Enrolled:  session.Enrolled,
+			Available: available,
+			Status:    session.Status,
+		})
+	}
+	return response, nil
+}
+
+// CancelClass cancela la clase y sus fechas futuras: libera los turnos, da de baja a los alumnos y devuelve los créditos de pack
+func (s *ClassService) CancelClass(classID uint, ownerID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var class models.Class
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", classID, ownerID).First(&class).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("class not found")
+			}
+			return errors.New("failed to fetch class")
+		}
+		if class.Status == "cancelled" {
+			return errors.New("class already cancelled")
+		}
+
+		var sessions []models.ClassSession
+		if err := tx.Where("class_id = ? AND status = ? AND date >= ?", class.ID, "scheduled", time.Now().Format("2006-01-02")).
+			Find(&sessions).Error; err != nil {
+			return errors.New("failed to fetch class sessions")
+		}
+		for i := range sessions {
+			if err := cancelClassSession(tx, &class, &sessions[i], ownerID, "class cancelled"); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&class).Update("status", "cancelled").Error; err != nil {
+			return errors.New("failed to cancel class")
+		}
+		return nil
+	})
+}
+
+// CancelSession cancela una fecha de la clase (ej: feriado o ausencia del profesor)
+func (s *ClassService) CancelSession(sessionID uint, ownerID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var session models.ClassSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Class").First(&session, sessionID).Error; err != nil || session.Class.OwnerID != ownerID {
+			return errors.New("session not found")
+		}
+		if session.Status != "scheduled" {
+			return errors.New("session cannot be cancelled")
+		}
+		return cancelClassSession(tx, &session.Class, &session, ownerID, "class session cancelled")
+	})
+}
+
+// cancelClassSession libera el turno de una fecha, da de baja a sus alumnos devolviendo los créditos de pack y les avisa
+func cancelClassSession(tx *gorm.DB, class *models.Class, session *models.ClassSession, actorID uint, reason string) error {
+	var booking models.Booking
+	if err := tx.First(&booking, session.BookingID).Error; err == nil && booking.Status == "confirmed" {
+		if err := transitionBooking(tx, &booking, "cancelled", &actorID, reason); err != nil {
+			return err
+		}
+	}
+
+	var enrollments []models.ClassEnrollment
+	if err := tx.Where("session_id = ? AND status = ?", session.ID, "enrolled").Find(&enrollments).Error; err != nil {
+		return errors.New("failed to fetch enrollments")
+	}
+	message := fmt.Sprintf("The class %s of %s at %s was cancelled.", class.Name, session.Date.Format("2006-01-02"), session.StartTime)
+	for i := range enrollments {
+		if err := releaseEnrollment(tx, &enrollments[i]); err != nil {
+			return err
+		}
+		userMessage := message
+		if enrollments[i].RefundStatus == "pending" {
+			userMessage += " The club will refund your payment."
+		}
+		if err := notifyUser(tx, enrollments[i].UserID, "class_cancelled", "Class cancelled", userMessage, "class_session", &session.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(session).Updates(map[string]interface{}{"status": "cancelled", "enrolled": 0}).Error
+}
+
+// releaseEnrollment cancela una inscripción y devuelve el crédito si se pagó con un pack. Una clase suelta ya
+// cobrada por el club queda marcada para que el club devuelva el pago.
+func releaseEnrollment(tx *gorm.DB, enrollment *models.ClassEnrollment) error {
+	updates := map[string]interface{}{"status": "cancelled"}
+	if enrollment.PaymentType == "single" && enrollment.PaidAt != nil {
+		updates["refund_status"] = "pending"
+	}
+	if err := tx.Model(enrollment).Updates(updates).Error; err != nil {
+		return errors.New("failed to cancel enrollment")
+	}
+	enrollment.Status = "cancelled"
+	if refundStatus, ok := updates["refund_status"]; ok {
+		enrollment.RefundStatus = refundStatus.(string)
+	}
+	if enrollment.PackID != nil {
+		if err := tx.Model(&models.ClassPack{}).Where("id = ? AND used_credits > 0", *enrollment.PackID).
+			Update("used_credits", gorm.Expr("used_credits - 1")).Error; err != nil {
+			return errors.New("failed to return class credit")
+		}
+	}
+	return nil
+}
+
+// Enroll inscribe al usuario en una fecha de la clase si hay cupo. Por defecto usa un crédito del pack que vence
+// primero; sin créditos (o con pay_with=single) la inscripción queda como clase suelta a pagar en el club.
+func (s *ClassService) Enroll(sessionID uint, userID uint, req *models.EnrollClassRequest) (*models.ClassEnrollment, error) {
+	if req.PayWith != "" && req.PayWith != "single" && req.PayWith != "pack" {
+		return nil, errors.New("invalid payment option")
+	}
+
+	var enrollment models.ClassEnrollment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var session models.ClassSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Class").First(&session, sessionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("session not found")
+			}
+			return errors.New("failed to fetch session")
+		}
+		if session.Status != "scheduled" || session.Class.Status != "active" {
+			return errors.New("class is not open for enrollment")
+		}
+		startsAt, err := classSessionStartsAt(&session)
+		if err != nil {
+			return err
+		}
+		if !startsAt.After(time.Now()) {
+			return errors.New("class already started")
+		}
+
+		var existing int64
+		tx.Model(&models.ClassEnrollment{}).Where("session_id = ? AND user_id = ? AND status = ?", session.ID, userID, "enrolled").Count(&existing)
+		if existing > 0 {
+			return errors.New("already enrolled in this class")
+		}
+		if session.Enrolled >= session.Class.Capacity {
+			return errors.New("class is full")
+		}
+
+		enrollment = models.ClassEnrollment{
+			SessionID:   session.ID,
+			UserID:      userID,
+			PaymentType: "single",
+			Amount:      session.Class.Price,
+			Status:      "enrolled",
+		}
+
+		if req.PayWith != "single" {
+			var pack models.ClassPack
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("owner_id = ? AND user_id = ? AND used_credits < credits", session.Class.OwnerID, userID).
+				Where("expires_at IS NULL OR expires_at >= ?", session.Date).
+				Order("expires_at IS NULL, expires_at, id").
+				First(&pack).Error
+			if err == nil {
+				if err := tx.Model(&pack).Update("used_credits", pack.UsedCredits+1).Error; err != nil {
+					return errors.New("failed to use class credit")
+				}
+				enrollment.PaymentType = "pack"
+				enrollment.PackID = &pack.ID
+				enrollment.Amount = 0
+			} else if req.PayWith == "pack" {
+				return errors.New("no class credits available")
+			}
+		}
+
+		if err := tx.Create(&enrollment).Error; err != nil {
+			return errors.New("failed to enroll in class")
+		}
+		if err := tx.Model(&session).Update("enrolled", session.Enrolled+1).Error; err != nil {
+			return errors.New("failed to enroll in class")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Session.Class.Coach").Preload("Session.Class.Court").First(&enrollment, enrollment.ID).Error; err != nil {
+		return nil, errors.New("failed to load enrollment")
+	}
+	return &enrollment, nil
+}
+
+// CancelEnrollment da de baja al usuario de una fecha antes de que empiece; libera el cupo y devuelve el crédito del pack.
+// Si la clase suelta ya estaba pagada se avisa al club para que devuelva el pago.
+func (s *ClassService) CancelEnrollment(enrollmentID uint, userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var enrollment models.ClassEnrollment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND user_id = ?", enrollmentID, userID).
+			Preload("Session.Class").
+			First(&enrollment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("enrollment not found")
+			}
+			return errors.New("failed to fetch enrollment")
+		}
+		if enrollment.Status != "enrolled" {
+			return errors.New("enrollment cannot be cancelled")
+		}
+		startsAt, err := classSessionStartsAt(&enrollment.Session)
+		if err != nil {
+			return err
+		}
+		if !startsAt.After(time.Now()) {
+			return errors.New("class already started")
+		}
+
+		if err := releaseEnrollment(tx, &enrollment); err != nil {
+			return err
+		}
+		if enrollment.RefundStatus == "pending" {
+			session := enrollment.Session
+			message := fmt.Sprintf("An enrollment paid for the class %s of %s at %s was cancelled and must be refunded.",
+				session.Class.Name, session.Date.Format("2006-01-02"), session.StartTime)
+			if err := notifyUser(tx, session.Class.OwnerID, "class_refund_pending", "Class refund pending", message, "class_enrollment", &enrollment.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.ClassSession{}).Where("id = ? AND enrolled > 0", enrollment.SessionID).
+			Update("enrolled", gorm.Expr("enrolled - 1")).Error
+	})
+}
+
+// GetUserEnrollments lista las inscripciones del usuario, de la más reciente a la más antigua
+func (s *ClassService) GetUserEnrollments(userID uint) ([]models.ClassEnrollment, error) {
+	var enrollments []models.ClassEnrollment
+	err := s.db.Where("user_id = ?", userID).
+		Preload("Session.Class.Coach").Preload("Session.Class.Court").
+		Order("created_at DESC").
+		Find(&enrollments).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch enrollments")
+	}
+	return enrollments, nil
+}
+
+// GetUserPacks lista los packs de clases del usuario
+func (s *ClassService) GetUserPacks(userID uint) ([]models.ClassPack, error) {
+	var packs []models.ClassPack
+	if err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&packs).Error; err != nil {
+		return nil, errors.New("failed to fetch class packs")
+	}
+	return packs, nil
+}
+
+// SellPack registra la venta de un pack de clases cobrado por el club
+func (s *ClassService) SellPack(ownerID uint, req *models.SellClassPackRequest) (*models.ClassPack, error) {
+	if req.Credits < 1 {
+		return nil, errors.New("credits must be at least 1")
+	}
+	if req.PaymentMethod != "cash" && req.PaymentMethod != "transfer" {
+		return nil, errors.New("invalid payment method")
+	}
+
+	var user models.User
+	if err := s.db.Where("id = ? AND is_active = ?", req.UserID, true).First(&user).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	pack := models.ClassPack{
+		OwnerID:       ownerID,
+		UserID:        user.ID,
+		Credits:       req.Credits,
+		Price:         roundPrice(req.Price),
+		PaymentMethod: req.PaymentMethod,
+	}
+	if req.ValidDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ValidDays)
+		pack.ExpiresAt = &expiresAt
+	}
+	if err := s.db.Create(&pack).Error; err != nil {
+		return nil, errors.New("failed to create class pack")
+	}
+
+	message := fmt.Sprintf("You have %d class credits available.", pack.Credits)
+	if err := notifyUser(s.db, user.ID, "class_pack", "Class pack added", message, "class_pack", &pack.ID); err != nil {
+		log.Printf("failed to notify class pack %d: %v", pack.ID, err)
+	}
+	return &pack, nil
+}
+
+// RecordEnrollmentPayment registra el cobro en el club de una clase suelta
+func (s *ClassService) RecordEnrollmentPayment(enrollmentID uint, ownerID uint, req *models.ManualPaymentRequest) (*models.ClassEnrollment, error) {
+	if req.PaymentMethod != "cash" && req.PaymentMethod != "transfer" {
+		return nil, errors.New("invalid payment method")
+	}
+
+	var enrollment models.ClassEnrollment
+	err := s.db.Joins("JOIN class_sessions ON class_sessions.id = class_enrollments.session_id").
+		Joins("JOIN classes ON classes.id = class_sessions.class_id").
+		Where("class_enrollments.id = ? AND classes.owner_id = ?", enrollmentID, ownerID).
+		First(&enrollment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, errors.New("failed to fetch enrollment")
+	}
+	if enrollment.PaymentType == "pack" {
+		return nil, errors.New("enrollment is paid with a class pack")
+	}
+	if enrollment.PaidAt != nil {
+		return nil, errors.New("enrollment is already paid")
+	}
+	if enrollment.Status == "cancelled" {
+		return nil, errors.New("enrollment is cancelled")
+	}
+
+	now := time.Now()
+	enrollment.PaidAt = &now
+	enrollment.PaymentMethod = req.PaymentMethod
+	if err := s.db.Model(&enrollment).Updates(map[string]interface{}{"paid_at": now, "payment_method": req.PaymentMethod}).Error; err != nil {
+		return nil, errors.New("failed to record payment")
+	}
+	return &enrollment, nil
+}
+
+// RecordEnrollmentRefund registra que el club devolvió el pago de una clase suelta cancelada
+func (s *ClassService) RecordEnrollmentRefund(enrollmentID uint, ownerID uint) (*models.ClassEnrollment, error) {
+	var enrollment models.ClassEnrollment
+	err := s.db.Joins("JOIN class_sessions ON class_sessions.id = class_enrollments.session_id").
+		Joins("JOIN classes ON classes.id = class_sessions.class_id").
+		Where("class_enrollments.id = ? AND classes.owner_id = ?", enrollmentID, ownerID).
+		First(&enrollment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, errors.New("failed to fetch enrollment")
+	}
+
+	now := time.Now()
+	result := s.db.Model(&models.ClassEnrollment{}).Where("id = ? AND refund_status = ?", enrollment.ID, "pending").
+		Updates(map[string]interface{}{"refund_status": "refunded", "refunded_at": now})
+	if result.Error != nil {
+		return nil, errors.New("failed to record refund")
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("enrollment has no pending refund")
+	}
+	enrollment.RefundStatus = "refunded"
+	enrollment.RefundedAt = &now
+	return &enrollment, nil
+}
+
+// GetCoachSessions devuelve las próximas fechas de las clases que dicta el usuario, con los alumnos inscriptos
+func (s *ClassService) GetCoachSessions(userID uint) ([]models.ClassSession, error) {
+	coachClasses := s.db.Model(&models.Class{}).Select("classes.id").
+		Joins("JOIN coaches ON coaches.id = classes.coach_id").
+		Where("coaches.user_id = ?", userID)
+
+	var sessions []models.ClassSession
+	err := s.db.Where("class_id IN (?) AND date >= ?", coachClasses, time.Now().Format("2006-01-02")).
+		Preload("Class.Court").
+		Preload("Enrollments", "status <> ?", "cancelled").Preload("Enrollments.User").
+		Order("date, start_time").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch class sessions")
+	}
+	return sessions, nil
+}
+
+// GetSessionRoster devuelve los alumnos de una fecha, para el profesor de la clase o el club
+func (s *ClassService) GetSessionRoster(sessionID uint, userID uint) (*models.ClassSession, error) {
+	session, err := findManagedSession(s.db, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Where("session_id = ? AND status <> ?", session.ID, "cancelled").
+		Preload("User").
+		Find(&session.Enrollments).Error; err != nil {
+		return nil, errors.New("failed to fetch enrollments")
+	}
+	return session, nil
+}
+
+// MarkAttendance registra quién asistió a la clase y la da por dictada, completando la reserva de su turno
+func (s *ClassService) MarkAttendance(sessionID uint, userID uint, req *models.MarkAttendanceRequest) (*models.ClassSession, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		session, err := findManagedSession(tx, sessionID, userID)
+		if err != nil {
+			return err
+		}
+		if session.Status == "cancelled" {
+			return errors.New("session is cancelled")
+		}
+		startsAt, err := classSessionStartsAt(session)
+		if err != nil {
+			return err
+		}
+		if startsAt.After(time.Now()) {
+			return errors.New("class has not started yet")
+		}
+
+		for _, entry := range req.Attendance {
+			status := "absent"
+			if entry.Attended {
+				status = "attended"
+			}
+			result := tx.Model(&models.ClassEnrollment{}).
+				Where("id = ? AND session_id = ? AND status <> ?", entry.EnrollmentID, session.ID, "cancelled").
+				Update("status", status)
+			if result.Error != nil {
+				return errors.New("failed to record attendance")
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("enrollment not found")
+			}
+		}
+
+		// El turno de la cancha se da por jugado junto con la clase
+		var booking models.Booking
+		if err := tx.First(&booking, session.BookingID).Error; err == nil && booking.Status == "confirmed" {
+			if err := transitionBooking(tx, &booking, "completed", &userID, "class session completed"); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(session).Update("status", "completed").Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSessionRoster(sessionID, userID)
+}
+
+// findManagedSession obtiene una fecha de clase verificando que el usuario sea el dueño del club o el profesor de la clase
+func findManagedSession(db *gorm.DB, sessionID uint, userID uint) (*models.ClassSession, error) {
+	var session models.ClassSession
+	if err := db.Preload("Class.Coach").Preload("Class.Court").First(&session, sessionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, errors.New("failed to fetch session")
+	}
+
+	isCoach := session.Class.Coach.UserID != nil && *session.Class.Coach.UserID == userID
+	if session.Class.OwnerID != userID && !isCoach {
+		return nil, errors.New("session not found")
+	}
+	return &session, nil
+}
+
+// classSessionStartsAt devuelve el momento de inicio de una fecha de clase
+func classSessionStartsAt(session *models.ClassSession) (time.Time, error) {
+	start, err := parseClock(session.StartTime)
+	if err != nil {
+		return time.Time{}, errors.New("invalid class time")
+	}
+	return time.Date(session.Date.Year(), session.Date.Month(), session.Date.Day(), 0, start, 0, 0, time.Local), nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// newClassSession crea una clase del propietario con una fecha que reserva el turno indicado
+func newClassSession(t *testing.T, db *gorm.DB, ownerID uint, date time.Time, start, end string) (models.Class, models.ClassSession) {
+	t.Helper()
+	court := testutil.CreateCourt(t, db, ownerID)
+	coach := models.Coach{OwnerID: ownerID, Name: "Profesor", IsActive: true}
+	if err := db.Create(&coach).Error; err != nil {
+		t.Fatalf("create coach: %v", err)
+	}
+	class := models.Class{OwnerID: ownerID, CoachID: coach.ID, CourtID: court.ID, Name: "Iniciación", Capacity: 4, Price: 5000,
+		StartTime: start, EndTime: end, StartDate: date, EndDate: date, Status: "active"}
+	if err := db.Omit(clause.Associations).Create(&class).Error; err != nil {
+		t.Fatalf("create class: %v", err)
+	}
+	booking := testutil.CreateBooking(t, db, court.ID, ownerID, date, start, end, "confirmed", nil)
+	session := models.ClassSession{ClassID: class.ID, BookingID: booking.ID, Date: date, StartTime: start, EndTime: end, Status: "scheduled"}
+	if err := db.Omit(clause.Associations).Create(&session).Error; err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return class, session
+}
+
+// enrollPaid inscribe al alumno como clase suelta ya cobrada por el club
+func enrollPaid(t *testing.T, db *gorm.DB, session models.ClassSession, userID uint) models.ClassEnrollment {
+	t.Helper()
+	paidAt := time.Now()
+	enrollment := models.ClassEnrollment{SessionID: session.ID, UserID: userID, PaymentType: "single", Amount: 5000, PaidAt: &paidAt, PaymentMethod: "cash", Status: "enrolled"}
+	if err := db.Omit(clause.Associations).Create(&enrollment).Error; err != nil {
+		t.Fatalf("create enrollment: %v", err)
+	}
+	db.Model(&session).Update("enrolled", gorm.Expr("enrolled + 1"))
+	return enrollment
+}
+
+func TestMarkAttendanceCompletesTheSessionBooking(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	student := testutil.CreateUser(t, db, "student@test.com", "user")
+	_, session := newClassSession(t, db, owner.ID, testutil.Day(-1), "18:00", "19:30")
+	enrollment := enrollPaid(t, db, session, student.ID)
+	service := NewClassService(db, NewBookingService(db, NewPaymentService(db, &fakeGateway{})))
+
+	req := &models.MarkAttendanceRequest{Attendance: []models.AttendanceEntry{{EnrollmentID: enrollment.ID, Attended: true}}}
+	if _, err := service.MarkAttendance(session.ID, owner.ID, req); err != nil {
+		t.Fatalf("mark attendance: %v", err)
+	}
+
+	var booking models.Booking
+	db.First(&booking, session.BookingID)
+	if booking.Status != "completed" {
+		t.Fatalf("expected the session booking completed, got %s", booking.Status)
+	}
+}
+
+func TestCancelledPaidEnrollmentsAreFlaggedForRefund(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	student := testutil.CreateUser(t, db, "student@test.com", "user")
+	other := testutil.CreateUser(t, db, "other@test.com", "user")
+	_, session := newClassSession(t, db, owner.ID, testutil.Day(3), "18:00", "19:30")
+	cancelled := enrollPaid(t, db, session, student.ID)
+	remaining := enrollPaid(t, db, session, other.ID)
+	service := NewClassService(db, NewBookingService(db, NewPaymentService(db, &fakeGateway{})))
+
+	// El alumno cancela su clase suelta pagada: el club recibe el aviso de devolución
+	if err := service.CancelEnrollment(cancelled.ID, student.ID); err != nil {
+		t.Fatalf("cancel enrollment: %v", err)
+	}
+	db.First(&cancelled, cancelled.ID)
+	if cancelled.RefundStatus != "pending" {
+		t.Fatalf("expected the paid enrollment flagged for refund, got %q", cancelled.RefundStatus)
+	}
+	var notices int64
+	db.Model(&models.Notification{}).Where("user_id = ? AND type = ?", owner.ID, "class_refund_pending").Count(&notices)
+	if notices != 1 {
+		t.Fatalf("expected the owner to be notified of the refund, got %d notifications", notices)
+	}
+
+	// El club cancela la fecha: el resto de las clases sueltas pagadas también quedan para devolver
+	if err := service.CancelSession(session.ID, owner.ID); err != nil {
+		t.Fatalf("cancel session: %v", err)
+	}
+	db.First(&remaining, remaining.ID)
+	if remaining.Status != "cancelled" || remaining.RefundStatus != "pending" {
+		t.Fatalf("expected the remaining enrollment cancelled and flagged, got %s / %q", remaining.Status, remaining.RefundStatus)
+	}
+
+	refunded, err := service.RecordEnrollmentRefund(cancelled.ID, owner.ID)
+	if err != nil || refunded.RefundStatus != "refunded" {
+		t.Fatalf("expected the refund recorded, got %v", err)
+	}
+	if _, err := service.RecordEnrollmentRefund(cancelled.ID, owner.ID); err == nil || err.Error() != "enrollment has no pending refund" {
+		t.Fatalf("expected the refund to be recorded only once, got %v", err)
+	}
+}
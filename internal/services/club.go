@@ -0,0 +1,437 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+type ClubService struct {
+	db           *gorm.DB
+	courtService *CourtService
+}
+
+func NewClubService(db *gorm.DB, courtService *CourtService) *ClubService {
+	return &ClubService{db: db, courtService: courtService}
+}
+
+// CreateClub da de alta un club con su horario semanal, que usarán las canchas sin horario propio
+func (s *ClubService) CreateClub(ownerID uint, req *models.CreateClubRequest) (*models.Club, error) {
+	if len(req.BusinessHours) == 0 {
+		return nil, errors.New("business_hours are required")
+	}
+	// Validar que las franjas horarias no se superpongan
+	if err := validateWeeklyWindows(req.BusinessHours); err != nil {
+		return nil, err
+	}
+	if req.CancellationPolicy != nil {
+		if err := validateCancellationPolicy(req.CancellationPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	club := models.Club{
+		OwnerID:            ownerID,
+		Name:               req.Name,
+		Address:            req.Address,
+		City:               req.City,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		Description:        req.Description,
+		ImageURL:           req.ImageURL,
+		Phone:              req.Phone,
+		Email:              req.Email,
+		Amenities:          req.Amenities,
+		Rules:              req.Rules,
+		CancellationPolicy: req.CancellationPolicy,
+		SlotDurations:      req.SlotDurations,
+		SlotStepMinutes:    req.SlotStepMinutes,
+		BufferMinutes:      req.BufferMinutes,
+		IsActive:           true,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&club).Error; err != nil {
+			return errors.New("failed to create club")
+		}
+		for _, bh := range req.BusinessHours {
+			businessHour := models.ClubBusinessHour{
+				ClubID:    club.ID,
+				DayOfWeek: bh.DayOfWeek,
+				OpenTime:  bh.OpenTime,
+				CloseTime: bh.CloseTime,
+			}
+			if err := tx.Create(&businessHour).Error; err != nil {
+				return errors.New("failed to create business hours")
+			}
+			club.BusinessHours = append(club.BusinessHours, businessHour)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &club, nil
+}
+
+// GetOwnerClubs obtiene los clubes del propietario con sus canchas
+func (s *ClubService) GetOwnerClubs(ownerID uint) ([]models.Club, error) {
+	var clubs []models.Club
+	if err := s.db.Where("owner_id = ?", ownerID).Preload("BusinessHours").Preload("Courts").Order("name").Find(&clubs).Error; err != nil {
+		return nil, errors.New("failed to fetch owner clubs")
+	}
+	return clubs, nil
+}
+
+// GetClubByID obtiene un club activo con su horario y sus canchas activas
+func (s *ClubService) GetClubByID(id uint) (*models.Club, error) {
+	var club models.Club
+	err := s.db.Where("id = ? AND is_active = ?", id, true).
+		Preload("BusinessHours", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_of_week, open_time")
+		}).
+		Preload("Courts", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("name")
+		}).
+		First(&club).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("club not found")
+		}
+		return nil, errors.New("failed to fetch club")
+	}
+	return &club, nil
+}
+
+// UpdateClub actualiza los datos del club. Los cambios de duraciones y política de cancelación se aplican
+// a las canchas del club que no tienen una configuración propia.
+func (s *ClubService) UpdateClub(id uint, ownerID uint, req *models.UpdateClubRequest) (*models.Club, error) {
+	// Validar que el request no sea nil
+	if err := utils.ValidatePointer(req, "UpdateClubRequest"); err != nil {
+		return nil, err
+	}
+
+	club, err := findOwnerClub(s.db, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Actualizar campos si se proporcionan
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		if err := utils.ValidateStringPointer(req.Name, "name"); err != nil {
+			return nil, err
+		}
+		updates["name"] = *req.Name
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.City != nil {
+		updates["city"] = *req.City
+	}
+	if req.Latitude != nil {
+		updates["latitude"] = *req.Latitude
+	}
+	if req.Longitude != nil {
+		updates["longitude"] = *req.Longitude
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ImageURL != nil {
+		updates["image_url"] = *req.ImageURL
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	// Las columnas JSON se serializan manualmente porque Updates con map no aplica el serializer
+	if req.Amenities != nil {
+		amenities, err := json.Marshal(req.Amenities)
+		if err != nil {
+			return nil, errors.New("invalid amenities")
+		}
+		updates["amenities"] = string(amenities)
+	}
+	if req.Rules != nil {
+		rules, err := json.Marshal(req.Rules)
+		if err != nil {
+			return nil, errors.New("invalid rules")
+		}
+		updates["rules"] = string(rules)
+	}
+	if req.CancellationPolicy != nil {
+		if err := validateCancellationPolicy(req.CancellationPolicy); err != nil {
+			return nil, err
+		}
+		policy, err := json.Marshal(req.CancellationPolicy)
+		if err != nil {
+			return nil, errors.New("invalid cancellation_policy")
+		}
+		updates["cancellation_policy"] = string(policy)
+	}
+	if req.SlotDurations != nil {
+		slotDurations, err := json.Marshal(req.SlotDurations)
+		if err != nil {
+			return nil, errors.New("invalid slot_durations")
+		}
+		updates["slot_durations"] = string(slotDurations)
+	}
+	if req.SlotStepMinutes != nil {
+		if err := utils.ValidateIntPointer(req.SlotStepMinutes, "slot_step_minutes"); err != nil {
+			return nil, err
+		}
+		updates["slot_step_minutes"] = *req.SlotStepMinutes
+	}
+	if req.BufferMinutes != nil {
+		if err := utils.ValidateIntPointer(req.BufferMinutes, "buffer_minutes"); err != nil {
+			return nil, err
+		}
+		updates["buffer_minutes"] = *req.BufferMinutes
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if err := s.db.Model(club).Updates(updates).Error; err != nil {
+		return nil, errors.New("failed to update club")
+	}
+
+	// Cargar el club actualizado
+	if err := s.db.Preload("BusinessHours").Preload("Courts").First(club, id).Error; err != nil {
+		return nil, errors.New("failed to load updated club")
+	}
+
+	return club, nil
+}
+
+// ReplaceClubBusinessHours reemplaza el horario semanal completo del club
+func (s *ClubService) ReplaceClubBusinessHours(clubID uint, ownerID uint, req *models.UpdateBusinessHoursRequest) ([]models.ClubBusinessHour, error) {
+	if _, err := findOwnerClub(s.db, clubID, ownerID); err != nil {
+		return nil, err
+	}
+
+	// Validar que las franjas horarias no se superpongan
+	if err := validateWeeklyWindows(req.BusinessHours); err != nil {
+		return nil, err
+	}
+
+	var businessHours []models.ClubBusinessHour
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("club_id = ?", clubID).Delete(&models.ClubBusinessHour{}).Error; err != nil {
+			return errors.New("failed to delete business hours")
+		}
+
+		for _, bh := range req.BusinessHours {
+			businessHour := models.ClubBusinessHour{
+				ClubID:    clubID,
+				DayOfWeek: bh.DayOfWeek,
+				OpenTime:  bh.OpenTime,
+				CloseTime: bh.CloseTime,
+			}
+			if err := tx.Create(&businessHour).Error; err != nil {
+				return errors.New("failed to create business hours")
+			}
+			businessHours = append(businessHours, businessHour)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return businessHours, nil
+}
+
+// SearchClubs busca clubes activos con al menos una cancha activa que cumpla los filtros, resumiendo sus canchas
+func (s *ClubService) SearchClubs(req *models.SearchClubsRequest) ([]models.ClubSearchResult, error) {
+	query := s.db.Model(&models.Club{}).Where("clubs.is_active = ?", true)
+
+	if req.Name != "" {
+		query = query.Where("clubs.name LIKE ?", "%"+req.Name+"%")
+	}
+	if req.City != "" {
+		query = query.Where("clubs.city = ?", req.City)
+	}
+	if req.Amenity != "" {
+		query = query.Where("JSON_CONTAINS(clubs.amenities, JSON_QUOTE(?))", req.Amenity)
+	}
+
+	// Los filtros de canchas se cumplen si al menos una cancha activa del club los satisface
+	courtFilter := "SELECT 1 FROM courts WHERE courts.club_id = clubs.id AND courts.is_active = true AND courts.deleted_at IS NULL"
+	var courtArgs []interface{}
+	if req.IsIndoor != nil {
+		courtFilter += " AND courts.is_indoor = ?"
+		courtArgs = append(courtArgs, *req.IsIndoor)
+	}
+	if req.MaxPrice != nil {
+		courtFilter += " AND courts.price_per_hour <= ?"
+		courtArgs = append(courtArgs, *req.MaxPrice)
+	}
+	query = query.Where("EXISTS ("+courtFilter+")", courtArgs...)
+
+	// Si se proporcionan coordenadas, filtrar por distancia
+	if req.Latitude != nil && req.Longitude != nil {
+		radius := 20.0 // radio por defecto en km
+		if req.Radius != nil {
+			radius = *req.Radius
+		}
+
+		query = query.Scopes(withinRadius("clubs.latitude", "clubs.longitude", *req.Latitude, *req.Longitude, radius))
+	}
+
+	var clubs []models.Club
+	if err := query.Order("clubs.name").Find(&clubs).Error; err != nil {
+		return nil, errors.New("failed to search clubs")
+	}
+
+	results := make([]models.ClubSearchResult, 0, len(clubs))
+	if len(clubs) == 0 {
+		return results, nil
+	}
+
+	clubIDs := make([]uint, 0, len(clubs))
+	for _, club := range clubs {
+		clubIDs = append(clubIDs, club.ID)
+	}
+	var courts []models.Court
+	if err := s.db.Select("id", "club_id", "price_per_hour", "is_indoor").
+		Where("club_id IN ? AND is_active = ?", clubIDs, true).Find(&courts).Error; err != nil {
+		return nil, errors.New("failed to fetch club courts")
+	}
+	courtsByClub := make(map[uint][]models.Court)
+	for _, court := range courts {
+		courtsByClub[*court.ClubID] = append(courtsByClub[*court.ClubID], court)
+	}
+
+	for i := range clubs {
+		result := models.ClubSearchResult{Club: &clubs[i]}
+		for j, court := range courtsByClub[clubs[i].ID] {
+			result.CourtCount++
+			if court.IsIndoor {
+				result.IndoorCourts++
+			}
+			if j == 0 || court.PricePerHour < result.MinPrice {
+				result.MinPrice = court.PricePerHour
+			}
+			if court.PricePerHour > result.MaxPrice {
+				result.MaxPrice = court.PricePerHour
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetClubAvailability combina los horarios libres de todas las canchas activas del club para una duración
+// (0 usa la primera duración del club). Cada horario indica cuántas canchas están libres y cuáles, con su precio.
+func (s *ClubService) GetClubAvailability(clubID uint, date time.Time, duration int) (*models.ClubAvailabilityResponse, error) {
+	club, err := s.GetClubByID(clubID)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration == 0 {
+		if len(club.SlotDurations) > 0 {
+			duration = club.SlotDurations[0]
+		} else {
+			duration = defaultSlotDurations[0]
+		}
+	}
+
+	response := &models.ClubAvailabilityResponse{
+		ClubID:          club.ID,
+		Date:            date.Format("2006-01-02"),
+		DurationMinutes: duration,
+		Slots:           make([]models.ClubSlot, 0),
+	}
+
+	slotIndex := make(map[string]int)
+	slotOrder := make(map[string]int)
+	for _, court := range club.Courts {
+		availability, err := s.courtService.GetAvailability(court.ID, date, duration)
+		if err != nil {
+			// Las canchas que no ofrecen esta duración no suman horarios
+			if errors.Is(err, ErrCourtDurationNotAllowed) {
+				continue
+			}
+			return nil, err
+		}
+		if availability.IsClosed {
+			continue
+		}
+
+		// Los horarios después de la medianoche de una franja nocturna se ordenan al final del día
+		firstOpen := 0
+		if len(availability.Windows) > 0 {
+			if open, err := parseClock(availability.Windows[0].OpenTime); err == nil {
+				firstOpen = open
+			}
+		}
+
+		for _, slot := range availability.Slots {
+			key := slot.StartTime + "-" + slot.EndTime
+			idx, ok := slotIndex[key]
+			if !ok {
+				start, err := parseClock(slot.StartTime)
+				if err != nil {
+					continue
+				}
+				if start < firstOpen {
+					start += minutesPerDay
+				}
+				response.Slots = append(response.Slots, models.ClubSlot{
+					StartTime:       slot.StartTime,
+					EndTime:         slot.EndTime,
+					DurationMinutes: slot.DurationMinutes,
+					MinPrice:        slot.Price,
+				})
+				idx = len(response.Slots) - 1
+				slotIndex[key] = idx
+				slotOrder[key] = start
+			}
+
+			clubSlot := &response.Slots[idx]
+			clubSlot.Courts = append(clubSlot.Courts, models.ClubSlotCourt{
+				CourtID:   court.ID,
+				CourtName: court.Name,
+				IsIndoor:  court.IsIndoor,
+				Price:     slot.Price,
+			})
+			clubSlot.AvailableCourts++
+			if slot.Price < clubSlot.MinPrice {
+				clubSlot.MinPrice = slot.Price
+			}
+		}
+	}
+
+	sort.SliceStable(response.Slots, func(i, j int) bool {
+		return slotOrder[response.Slots[i].StartTime+"-"+response.Slots[i].EndTime] <
+			slotOrder[response.Slots[j].StartTime+"-"+response.Slots[j].EndTime]
+	})
+
+	return response, nil
+}
+
+// findOwnerClub obtiene un club del propietario
+func findOwnerClub(db *gorm.DB, clubID uint, ownerID uint) (*models.Club, error) {
+	var club models.Club
+	if err := db.Where("id = ? AND owner_id = ?", clubID, ownerID).First(&club).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("club not found")
+		}
+		return nil, errors.New("failed to fetch club")
+	}
+	return &club, nil
+}
@@ -1,410 +1,725 @@
-package services
-
-import (
-	"errors"
-	"fmt"
-	"time"
-
-	"backend-padel-go/internal/models"
-	"backend-padel-go/internal/utils"
-
-	"gorm.io/gorm"
-)
-
-type CourtService struct {
-	db *gorm.DB
-}
-
-func NewCourtService(db *gorm.DB) *CourtService {
-	return &CourtService{db: db}
-}
-
-func (s *CourtService) CreateCourt(ownerID uint, req *models.CreateCourtRequest) (*models.Court, error) {
-	// Crear cancha
-	court := models.Court{
-		Name:               req.Name,
-		Address:            req.Address,
-		Latitude:           req.Latitude,
-		Longitude:          req.Longitude,
-		PricePerHour:       req.PricePerHour,
-		Description:        req.Description,
-		ImageURL:           req.ImageURL,
-		Surface:            req.Surface,
-		HasLighting:        req.HasLighting,
-		IsIndoor:           req.IsIndoor,
-		Amenities:          req.Amenities,
-		MaxPlayers:         req.MaxPlayers,
-		Rules:              req.Rules,
-		CancellationPolicy: req.CancellationPolicy,
-		OwnerID:            ownerID,
-		IsActive:           true,
-	}
-
-	if err := s.db.Create(&court).Error; err != nil {
-		return nil, errors.New("failed to create court")
-	}
-
-	// Crear horarios de atención
-	for _, bh := range req.BusinessHours {
-		businessHour := models.BusinessHour{
-			CourtID:   court.ID,
-			DayOfWeek: bh.DayOfWeek,
-			OpenTime:  bh.OpenTime,
-			CloseTime: bh.CloseTime,
-		}
-		if err := s.db.Create(&businessHour).Error; err != nil {
-			return nil, errors.New("failed to create business hours")
-		}
-	}
-
-	// Cargar relaciones
-	if err := s.db.Preload("BusinessHours").First(&court, court.ID).Error; err != nil {
-		return nil, errors.New("failed to load court with business hours")
-	}
-
-	return &court, nil
-}
-
-func (s *CourtService) GetAllCourts() ([]models.Court, error) {
-	var courts []models.Court
-	if err := s.db.Preload("BusinessHours").Where("is_active = ?", true).Find(&courts).Error; err != nil {
-		return nil, errors.New("failed to fetch courts")
-	}
-	return courts, nil
-}
-
-func (s *CourtService) GetCourtByID(id uint) (*models.Court, error) {
-	var court models.Court
-	if err := s.db.Preload("BusinessHours").Preload("Reviews").First(&court, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("court not found")
-		}
-		return nil, errors.New("failed to fetch court")
-	}
-	return &court, nil
-}
-
-func (s *CourtService) GetOwnerCourts(ownerID uint) ([]models.Court, error) {
-	var courts []models.Court
-	if err := s.db.Preload("BusinessHours").Where("owner_id = ?", ownerID).Find(&courts).Error; err != nil {
-		return nil, errors.New("failed to fetch owner courts")
-	}
-	return courts, nil
-}
-
-func (s *CourtService) UpdateCourt(id uint, ownerID uint, req *models.UpdateCourtRequest) (*models.Court, error) {
-	// Validar que el request no sea nil
-	if err := utils.ValidatePointer(req, "UpdateCourtRequest"); err != nil {
-		return nil, err
-	}
-
-	var court models.Court
-	if err := s.db.Where("id = ? AND owner_id = ?", id, ownerID).First(&court).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("court not found")
-		}
-		return nil, errors.New("failed to fetch court")
-	}
-
-	// Actualizar campos si se proporcionan
-	updates := make(map[string]interface{})
-	if req.Name != nil {
-		if err := utils.ValidateStringPointer(req.Name, "name"); err != nil {
-			return nil, err
-		}
-		updates["name"] = *req.Name
-	}
-	if req.Address != nil {
-		updates["address"] = *req.Address
-	}
-	if req.Latitude != nil {
-		updates["latitude"] = *req.Latitude
-	}
-	if req.Longitude != nil {
-		updates["longitude"] = *req.Longitude
-	}
-	if req.PricePerHour != nil {
-		if err := utils.ValidateNumberPointer(req.PricePerHour, "price_per_hour"); err != nil {
-			return nil, err
-		}
-		updates["price_per_hour"] = *req.PricePerHour
-	}
-	if req.Description != nil {
-		updates["description"] = *req.Description
-	}
-	if req.ImageURL != nil {
-		updates["image_url"] = *req.ImageURL
-	}
-	if req.Surface != nil {
-		updates["surface"] = *req.Surface
-	}
-	if req.HasLighting != nil {
-		updates["has_lighting"] = *req.HasLighting
-	}
-	if req.IsIndoor != nil {
-		updates["is_indoor"] = *req.IsIndoor
-	}
-	if req.Amenities != nil {
-		updates["amenities"] = req.Amenities
-	}
-	if req.MaxPlayers != nil {
-		if err := utils.ValidateIntPointer(req.MaxPlayers, "max_players"); err != nil {
-			return nil, err
-		}
-		updates["max_players"] = *req.MaxPlayers
-	}
-	if req.Rules != nil {
-		updates["rules"] = req.Rules
-	}
-	if req.CancellationPolicy != nil {
-		updates["cancellation_policy"] = *req.CancellationPolicy
-	}
-	if req.IsActive != nil {
-		if err := utils.ValidateBoolPointer(req.IsActive, "is_active"); err != nil {
-			return nil, err
-		}
-		updates["is_active"] = *req.IsActive
-	}
-
-	if err := s.db.Model(&court).Updates(updates).Error; err != nil {
-		return nil, errors.New("failed to update court")
-	}
-
-	// Cargar la cancha actualizada
-	if err := s.db.Preload("BusinessHours").First(&court, id).Error; err != nil {
-		return nil, errors.New("failed to load updated court")
-	}
-
-	return &court, nil
-}
-
-func (s *CourtService) DeleteCourt(id uint, ownerID uint) error {
-	var court models.Court
-	if err := s.db.Where("id = ? AND owner_id = ?", id, ownerID).First(&court).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("court not found")
-		}
-		return errors.New("failed to fetch court")
-	}
-
-	if err := s.db.Delete(&court).Error; err != nil {
-		return errors.New("failed to delete court")
-	}
-
-	return nil
-}
-
-func (s *CourtService) GetNearbyCourts(lat, lng, radius float64) ([]models.Court, error) {
-	var courts []models.Court
-	
-	// Usar fórmula de Haversine para calcular distancia
-	query := `
-		SELECT *, 
-		(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * 
-		cos(radians(longitude) - radians(?)) + 
-		sin(radians(?)) * sin(radians(latitude)))) AS distance
-		FROM courts 
-		WHERE is_active = true
-		HAVING distance <= ?
-		ORDER BY distance
-	`
-
-	if err := s.db.Raw(query, lat, lng, lat, radius).Scan(&courts).Error; err != nil {
-		return nil, errors.New("failed to fetch nearby courts")
-	}
-
-	return courts, nil
-}
-
-func (s *CourtService) SearchCourts(req *models.SearchCourtsRequest) ([]*models.Court, error) {
-	query := s.db.Model(&models.Court{}).Where("is_active = ?", true)
-
-	if req.MinPrice != nil {
-		query = query.Where("price_per_hour >= ?", *req.MinPrice)
-	}
-	if req.MaxPrice != nil {
-		query = query.Where("price_per_hour <= ?", *req.MaxPrice)
-	}
-	if req.Surface != nil {
-		query = query.Where("surface = ?", *req.Surface)
-	}
-	if req.HasLighting != nil {
-		query = query.Where("has_lighting = ?", *req.HasLighting)
-	}
-	if req.IsIndoor != nil {
-		query = query.Where("is_indoor = ?", *req.IsIndoor)
-	}
-	if req.MinRating != nil {
-		query = query.Where("average_rating >= ?", *req.MinRating)
-	}
-
-	// Si se proporcionan coordenadas, filtrar por distancia
-	if req.Latitude != nil && req.Longitude != nil {
-		radius := 20.0 // radio por defecto en km
-		if req.Radius != nil {
-			radius = *req.Radius
-		}
-
-		haversineQuery := `
-			(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * 
-			cos(radians(longitude) - radians(?)) + 
-			sin(radians(?)) * sin(radians(latitude)))) <= ?
-		`
-		query = query.Where(haversineQuery, *req.Latitude, *req.Longitude, *req.Latitude, radius)
-	}
-
-	var courts []*models.Court
-	if err := query.Preload("BusinessHours").Find(&courts).Error; err != nil {
-		return nil, errors.New("failed to search courts")
-	}
-
-	return courts, nil
-}
-
-func (s *CourtService) GetAvailability(courtID uint, date time.Time) ([]*models.TimeSlot, error) {
-	// Obtener cancha
-	court, err := s.GetCourtByID(courtID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Obtener horarios de atención para el día de la semana
-	dayOfWeek := int(date.Weekday())
-	var businessHour models.BusinessHour
-	if err := s.db.Where("court_id = ? AND day_of_week = ?", courtID, dayOfWeek).First(&businessHour).Error; err != nil {
-		return nil, errors.New("no business hours for this day")
-	}
-
-	// Obtener reservas existentes para la fecha
-	var bookings []models.Booking
-	if err := s.db.Where("court_id = ? AND date = ? AND status = ?", courtID, date.Format("2006-01-02"), "confirmed").Find(&bookings).Error; err != nil {
-		return nil, errors.New("failed to fetch bookings")
-	}
-
-	// Generar slots de tiempo disponibles
-	slots := s.generateTimeSlots(businessHour.OpenTime, businessHour.CloseTime, court.PricePerHour)
-	
-	// Filtrar slots ocupados
-	availableSlots := s.filterAvailableSlots(slots, bookings)
-
-	return availableSlots, nil
-}
-
-func (s *CourtService) generateTimeSlots(openTime, closeTime string, pricePerHour float64) []*models.TimeSlot {
-	var slots []*models.TimeSlot
-	
-	// Parsear horarios
-	open, _ := time.Parse("15:04", openTime)
-	close, _ := time.Parse("15:04", closeTime)
-	
-	current := open
-	for current.Before(close) {
-		next := current.Add(time.Hour)
-		if next.After(close) {
-			break
-		}
-		
-		slots = append(slots, &models.TimeSlot{
-			StartTime: current.Format("15:04"),
-			EndTime:   next.Format("15:04"),
-			Available: true,
-			Price:     pricePerHour,
-		})
-		
-		current = next
-	}
-	
-	return slots
-}
-
-func (s *CourtService) filterAvailableSlots(slots []*models.TimeSlot, bookings []models.Booking) []*models.TimeSlot {
-	availableSlots := make([]*models.TimeSlot, 0)
-	
-	for _, slot := range slots {
-		isAvailable := true
-		for _, booking := range bookings {
-			if s.isTimeSlotOverlapping(slot, booking) {
-				isAvailable = false
-				break
-			}
-		}
-		
-		if isAvailable {
-			availableSlots = append(availableSlots, slot)
-		}
-	}
-	
-	return availableSlots
-}
-
-func (s *CourtService) isTimeSlotOverlapping(slot *models.TimeSlot, booking models.Booking) bool {
-	slotStart, _ := time.Parse("15:04", slot.StartTime)
-	slotEnd, _ := time.Parse("15:04", slot.EndTime)
-	bookingStart, _ := time.Parse("15:04", booking.StartTime)
-	bookingEnd, _ := time.Parse("15:04", booking.EndTime)
-	
-	return (slotStart.Before(bookingEnd) && slotEnd.After(bookingStart))
-}
-
-func (s *CourtService) GetCourtStatistics(ownerID uint) ([]models.CourtStatistics, error) {
-	var courts []models.Court
-	if err := s.db.Preload("Bookings").Preload("Reviews").Where("owner_id = ?", ownerID).Find(&courts).Error; err != nil {
-		return nil, errors.New("failed to fetch courts")
-	}
-
-	var statistics []models.CourtStatistics
-	for _, court := range courts {
-		totalBookings := len(court.Bookings)
-		reviewCount := len(court.Reviews)
-		
-		var revenue float64
-		for _, booking := range court.Bookings {
-			revenue += booking.TotalPrice
-		}
-		
-		statistics = append(statistics, models.CourtStatistics{
-			CourtID:       court.ID,
-			CourtName:     court.Name,
-			TotalBookings: totalBookings,
-			AverageRating: court.AverageRating,
-			ReviewCount:   reviewCount,
-			Revenue:       revenue,
-		})
-	}
-
-	return statistics, nil
-}
-
-func (s *CourtService) CreateSpecialHours(courtID uint, ownerID uint, req models.SpecialHour) (*models.SpecialHour, error) {
-	// Verificar que la cancha pertenece al propietario
-	var court models.Court
-	if err := s.db.Where("id = ? AND owner_id = ?", courtID, ownerID).First(&court).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("court not found")
-		}
-		return nil, errors.New("failed to fetch court")
-	}
-
-	specialHour := models.SpecialHour{
-		CourtID:   courtID,
-		Date:      req.Date,
-		OpenTime:  req.OpenTime,
-		CloseTime: req.CloseTime,
-		IsClosed:  req.IsClosed,
-		Reason:    req.Reason,
-	}
-
-	if err := s.db.Create(&specialHour).Error; err != nil {
-		return nil, errors.New("failed to create special hours")
-	}
-
-	return &specialHour, nil
-}
-
-func (s *CourtService) GetSpecialHours(courtID uint, startDate, endDate time.Time) ([]models.SpecialHour, error) {
-	var specialHours []models.SpecialHour
-	if err := s.db.Where("court_id = ? AND date BETWEEN ? AND ?", courtID, startDate, endDate).Find(&specialHours).Error; err != nil {
-		return nil, errors.New("failed to fetch special hours")
-	}
-	return specialHours, nil
-}
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CourtService struct {
+	db *gorm.DB
+}
+
+func NewCourtService(db *gorm.DB) *CourtService {
+	return &CourtService{db: db}
+}
+
+// ErrCourtDurationNotAllowed indica que la duración pedida no está entre las que ofrece la cancha
+var ErrCourtDurationNotAllowed = errors.New("duration not allowed for this court")
+
+func (s *CourtService) CreateCourt(ownerID uint, req *models.CreateCourtRequest) (*models.Court, error) {
+	// Una cancha de un club puede omitir el horario y usar el del club
+	var club *models.Club
+	if req.ClubID != nil {
+		var err error
+		if club, err = findOwnerClub(s.db, *req.ClubID, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.BusinessHours) == 0 && club == nil {
+		return nil, errors.New("business_hours are required")
+	}
+
+	// Validar que las franjas horarias no se superpongan
+	if err := validateWeeklyWindows(req.BusinessHours); err != nil {
+		return nil, err
+	}
+	if req.CancellationPolicy != nil {
+		if err := validateCancellationPolicy(req.CancellationPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Crear cancha
+	court := models.Court{
+		Name:               req.Name,
+		Address:            req.Address,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		PricePerHour:       req.PricePerHour,
+		Description:        req.Description,
+		ImageURL:           req.ImageURL,
+		Surface:            req.Surface,
+		HasLighting:        req.HasLighting,
+		IsIndoor:           req.IsIndoor,
+		Amenities:          req.Amenities,
+		MaxPlayers:         req.MaxPlayers,
+		Rules:              req.Rules,
+		CancellationPolicy: req.CancellationPolicy,
+		SlotDurations:      req.SlotDurations,
+		SlotStepMinutes:    req.SlotStepMinutes,
+		BufferMinutes:      req.BufferMinutes,
+		OwnerID:            ownerID,
+		ClubID:             req.ClubID,
+		IsActive:           true,
+	}
+
+	// La ubicación, los servicios, el intervalo y el margen no indicados no se copian: se resuelven desde el club al leer la cancha
+	if err := s.db.Create(&court).Error; err != nil {
+		return nil, errors.New("failed to create court")
+	}
+
+	// Crear horarios de atención
+	for _, bh := range req.BusinessHours {
+		businessHour := models.BusinessHour{
+			CourtID:   court.ID,
+			DayOfWeek: bh.DayOfWeek,
+			OpenTime:  bh.OpenTime,
+			CloseTime: bh.CloseTime,
+		}
+		if err := s.db.Create(&businessHour).Error; err != nil {
+			return nil, errors.New("failed to create business hours")
+		}
+	}
+
+	// Cargar relaciones
+	if err := s.db.Preload("BusinessHours").Preload("Club").First(&court, court.ID).Error; err != nil {
+		return nil, errors.New("failed to load court with business hours")
+	}
+	applyClubDefaults(&court)
+
+	return &court, nil
+}
+
+func (s *CourtService) GetAllCourts() ([]models.Court, error) {
+	var courts []models.Court
+	if err := s.db.Preload("BusinessHours").Preload("Club").Where("is_active = ?", true).Find(&courts).Error; err != nil {
+		return nil, errors.New("failed to fetch courts")
+	}
+	for i := range courts {
+		applyClubDefaults(&courts[i])
+	}
+	return courts, nil
+}
+
+func (s *CourtService) GetCourtByID(id uint) (*models.Court, error) {
+	var court models.Court
+	if err := s.db.Preload("BusinessHours").Preload("Reviews").Preload("Club").First(&court, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+	applyClubDefaults(&court)
+	return &court, nil
+}
+
+func (s *CourtService) GetOwnerCourts(ownerID uint) ([]models.Court, error) {
+	var courts []models.Court
+	if err := s.db.Preload("BusinessHours").Preload("Club").Where("owner_id = ?", ownerID).Find(&courts).Error; err != nil {
+		return nil, errors.New("failed to fetch owner courts")
+	}
+	for i := range courts {
+		applyClubDefaults(&courts[i])
+	}
+	return courts, nil
+}
+
+func (s *CourtService) UpdateCourt(id uint, ownerID uint, req *models.UpdateCourtRequest) (*models.Court, error) {
+	// Validar que el request no sea nil
+	if err := utils.ValidatePointer(req, "UpdateCourtRequest"); err != nil {
+		return nil, err
+	}
+
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", id, ownerID).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+
+	// Actualizar campos si se proporcionan
+	updates := make(map[string]interface{})
+	if req.ClubID != nil {
+		if *req.ClubID == 0 {
+			updates["club_id"] = nil
+		} else {
+			if _, err := findOwnerClub(s.db, *req.ClubID, ownerID); err != nil {
+				return nil, err
+			}
+			updates["club_id"] = *req.ClubID
+		}
+	}
+	if req.Name != nil {
+		if err := utils.ValidateStringPointer(req.Name, "name"); err != nil {
+			return nil, err
+		}
+		updates["name"] = *req.Name
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.Latitude != nil {
+		updates["latitude"] = *req.Latitude
+	}
+	if req.Longitude != nil {
+		updates["longitude"] = *req.Longitude
+	}
+	if req.PricePerHour != nil {
+		if err := utils.ValidateNumberPointer(req.PricePerHour, "price_per_hour"); err != nil {
+			return nil, err
+		}
+		updates["price_per_hour"] = *req.PricePerHour
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ImageURL != nil {
+		updates["image_url"] = *req.ImageURL
+	}
+	if req.Surface != nil {
+		updates["surface"] = *req.Surface
+	}
+	if req.HasLighting != nil {
+		updates["has_lighting"] = *req.HasLighting
+	}
+	if req.IsIndoor != nil {
+		updates["is_indoor"] = *req.IsIndoor
+	}
+	if req.Amenities != nil {
+		amenities, err := json.Marshal(req.Amenities)
+		if err != nil {
+			return nil, errors.New("invalid amenities")
+		}
+		updates["amenities"] = string(amenities)
+	}
+	if req.MaxPlayers != nil {
+		if err := utils.ValidateIntPointer(req.MaxPlayers, "max_players"); err != nil {
+			return nil, err
+		}
+		updates["max_players"] = *req.MaxPlayers
+	}
+	if req.Rules != nil {
+		updates["rules"] = req.Rules
+	}
+	if req.CancellationPolicy != nil {
+		if err := validateCancellationPolicy(req.CancellationPolicy); err != nil {
+			return nil, err
+		}
+		policy, err := json.Marshal(req.CancellationPolicy)
+		if err != nil {
+			return nil, errors.New("invalid cancellation_policy")
+		}
+		updates["cancellation_policy"] = string(policy)
+	}
+	if req.SlotDurations != nil {
+		// Las columnas JSON se serializan manualmente porque Updates con map no aplica el serializer
+		slotDurations, err := json.Marshal(req.SlotDurations)
+		if err != nil {
+			return nil, errors.New("invalid slot_durations")
+		}
+		updates["slot_durations"] = string(slotDurations)
+	}
+	// -1 quita el intervalo o el margen propios y la cancha vuelve a usar los del club
+	if req.SlotStepMinutes != nil {
+		if *req.SlotStepMinutes == -1 {
+			updates["slot_step_minutes"] = nil
+		} else {
+			if err := utils.ValidateIntPointer(req.SlotStepMinutes, "slot_step_minutes"); err != nil {
+				return nil, err
+			}
+			updates["slot_step_minutes"] = *req.SlotStepMinutes
+		}
+	}
+	if req.BufferMinutes != nil {
+		if *req.BufferMinutes == -1 {
+			updates["buffer_minutes"] = nil
+		} else {
+			if err := utils.ValidateIntPointer(req.BufferMinutes, "buffer_minutes"); err != nil {
+				return nil, err
+			}
+			updates["buffer_minutes"] = *req.BufferMinutes
+		}
+	}
+	if req.IsActive != nil {
+		if err := utils.ValidateBoolPointer(req.IsActive, "is_active"); err != nil {
+			return nil, err
+		}
+		updates["is_active"] = *req.IsActive
+	}
+
+	if err := s.db.Model(&court).Updates(updates).Error; err != nil {
+		return nil, errors.New("failed to update court")
+	}
+
+	// Cargar la cancha actualizada
+	if err := s.db.Preload("BusinessHours").Preload("Club").First(&court, id).Error; err != nil {
+		return nil, errors.New("failed to load updated court")
+	}
+	applyClubDefaults(&court)
+
+	return &court, nil
+}
+
+// ReplaceBusinessHours reemplaza el horario semanal completo de una cancha
+func (s *CourtService) ReplaceBusinessHours(courtID uint, ownerID uint, req *models.UpdateBusinessHoursRequest) ([]models.BusinessHour, error) {
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", courtID, ownerID).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+
+	// Validar que las franjas horarias no se superpongan
+	if err := validateWeeklyWindows(req.BusinessHours); err != nil {
+		return nil, err
+	}
+
+	var businessHours []models.BusinessHour
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("court_id = ?", courtID).Delete(&models.BusinessHour{}).Error; err != nil {
+			return errors.New("failed to delete business hours")
+		}
+
+		for _, bh := range req.BusinessHours {
+			businessHour := models.BusinessHour{
+				CourtID:   courtID,
+				DayOfWeek: bh.DayOfWeek,
+				OpenTime:  bh.OpenTime,
+				CloseTime: bh.CloseTime,
+			}
+			if err := tx.Create(&businessHour).Error; err != nil {
+				return errors.New("failed to create business hours")
+			}
+			businessHours = append(businessHours, businessHour)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return businessHours, nil
+}
+
+// GetBusinessHours obtiene el horario semanal de una cancha ordenado por día y apertura
+func (s *CourtService) GetBusinessHours(courtID uint) ([]models.BusinessHour, error) {
+	var businessHours []models.BusinessHour
+	if err := s.db.Where("court_id = ?", courtID).Order("day_of_week, open_time").Find(&businessHours).Error; err != nil {
+		return nil, errors.New("failed to fetch business hours")
+	}
+	return businessHours, nil
+}
+
+func (s *CourtService) DeleteCourt(id uint, ownerID uint) error {
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", id, ownerID).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("court not found")
+		}
+		return errors.New("failed to fetch court")
+	}
+
+	if err := s.db.Delete(&court).Error; err != nil {
+		return errors.New("failed to delete court")
+	}
+
+	return nil
+}
+
+func (s *CourtService) GetNearbyCourts(lat, lng, radius float64) ([]models.Court, error) {
+	var courts []models.Court
+	
+	// Usar fórmula de Haversine para calcular distancia, con la ubicación del club en las canchas sin dirección propia
+	distance := haversineDistance(courtLatitude, courtLongitude, lat, lng)
+	err := s.db.Model(&models.Court{}).
+		Joins(courtClubJoin).
+		Where("courts.is_active = ?", true).
+		Where("? <= ?", distance, radius).
+		Clauses(clause.OrderBy{Expression: distance}).
+		Preload("Club").
+		Find(&courts).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch nearby courts")
+	}
+	for i := range courts {
+		applyClubDefaults(&courts[i])
+	}
+
+	return courts, nil
+}
+
+// courtClubJoin une cada cancha con su club para resolver la ubicación de las canchas sin dirección propia
+const courtClubJoin = "LEFT JOIN clubs ON clubs.id = courts.club_id AND clubs.deleted_at IS NULL"
+
+// courtLatitude y courtLongitude son las coordenadas de la cancha o, si no tiene dirección propia, las de su club (requieren courtClubJoin)
+const (
+	courtLatitude  = "CASE WHEN courts.address = '' AND clubs.id IS NOT NULL THEN clubs.latitude ELSE courts.latitude END"
+	courtLongitude = "CASE WHEN courts.address = '' AND clubs.id IS NOT NULL THEN clubs.longitude ELSE courts.longitude END"
+)
+
+// haversineDistance devuelve la distancia en km (fórmula de Haversine) entre el punto y las coordenadas indicadas
+func haversineDistance(latitude, longitude string, lat, lng float64) clause.Expr {
+	return gorm.Expr(fmt.Sprintf(`
+		(6371 * acos(cos(radians(?)) * cos(radians(%[1]s)) * 
+		cos(radians(%[2]s) - radians(?)) + 
+		sin(radians(?)) * sin(radians(%[1]s))))
+	`, latitude, longitude), lat, lng, lat)
+}
+
+// withinRadius filtra por distancia en km usando las columnas (o expresiones) de latitud y longitud indicadas
+func withinRadius(latitude, longitude string, lat, lng, radius float64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("? <= ?", haversineDistance(latitude, longitude, lat, lng), radius)
+	}
+}
+
+// applyClubDefaults completa una cancha de club con la ubicación del club cuando no tiene dirección propia y le suma
+// los servicios del club. Requiere el club precargado y solo cambia la cancha leída, no la guardada.
+func applyClubDefaults(court *models.Court) {
+	if court.Club == nil {
+		return
+	}
+	if court.Address == "" {
+		court.Address = court.Club.Address
+		court.Latitude = court.Club.Latitude
+		court.Longitude = court.Club.Longitude
+	}
+
+	amenities := make([]string, 0, len(court.Club.Amenities)+len(court.Amenities))
+	seen := make(map[string]bool)
+	for _, amenity := range append(append([]string{}, court.Club.Amenities...), court.Amenities...) {
+		if !seen[amenity] {
+			seen[amenity] = true
+			amenities = append(amenities, amenity)
+		}
+	}
+	court.Amenities = amenities
+}
+
+// courtAddress devuelve la dirección de la cancha o, si no tiene una propia, la de su club precargado
+func courtAddress(court *models.Court) string {
+	if court.Address == "" && court.Club != nil {
+		return court.Club.Address
+	}
+	return court.Address
+}
+
+func (s *CourtService) SearchCourts(req *models.SearchCourtsRequest) ([]*models.Court, error) {
+	query := s.db.Model(&models.Court{}).Joins(courtClubJoin).Where("courts.is_active = ?", true)
+
+	if req.MinPrice != nil {
+		query = query.Where("courts.price_per_hour >= ?", *req.MinPrice)
+	}
+	if req.MaxPrice != nil {
+		query = query.Where("courts.price_per_hour <= ?", *req.MaxPrice)
+	}
+	if req.Surface != nil {
+		query = query.Where("courts.surface = ?", *req.Surface)
+	}
+	if req.HasLighting != nil {
+		query = query.Where("courts.has_lighting = ?", *req.HasLighting)
+	}
+	if req.IsIndoor != nil {
+		query = query.Where("courts.is_indoor = ?", *req.IsIndoor)
+	}
+	if req.MinRating != nil {
+		query = query.Where("courts.average_rating >= ?", *req.MinRating)
+	}
+
+	// Si se proporcionan coordenadas, filtrar por distancia
+	if req.Latitude != nil && req.Longitude != nil {
+		radius := 20.0 // radio por defecto en km
+		if req.Radius != nil {
+			radius = *req.Radius
+		}
+
+		query = query.Scopes(withinRadius(courtLatitude, courtLongitude, *req.Latitude, *req.Longitude, radius))
+	}
+
+	var courts []*models.Court
+	if err := query.Preload("BusinessHours").Preload("Club").Find(&courts).Error; err != nil {
+		return nil, errors.New("failed to search courts")
+	}
+	for _, court := range courts {
+		applyClubDefaults(court)
+	}
+
+	return courts, nil
+}
+
+// GetAvailability devuelve los horarios de inicio libres para una duración dada (0 usa la primera duración permitida de la cancha)
+func (s *CourtService) GetAvailability(courtID uint, date time.Time, duration int) (*models.AvailabilityResponse, error) {
+	// Obtener cancha
+	court, err := s.GetCourtByID(courtID)
+	if err != nil {
+		return nil, err
+	}
+
+	slotCfg := courtSlotConfig(court)
+	if duration == 0 {
+		duration = slotCfg.Durations[0]
+	}
+	if !slotCfg.allowsDuration(duration) {
+		return nil, ErrCourtDurationNotAllowed
+	}
+
+	availability := &models.AvailabilityResponse{
+		CourtID:         courtID,
+		Date:            date.Format("2006-01-02"),
+		DurationMinutes: duration,
+		Slots:           make([]*models.TimeSlot, 0),
+	}
+
+	// Obtener el horario efectivo del día (horario habitual más cierres y horarios especiales)
+	schedule, err := resolveDaySchedule(s.db, courtID, date)
+	if err != nil {
+		return nil, err
+	}
+	availability.IsClosed = schedule.IsClosed
+	availability.Reason = schedule.Reason
+	if schedule.IsClosed {
+		return availability, nil
+	}
+	availability.Windows = schedule.openingWindows()
+
+	// Obtener reservas existentes para la fecha
+	var bookings []models.Booking
+	if err := s.db.Scopes(blockingBookings).Where("court_id = ? AND date = ?", courtID, date.Format("2006-01-02")).Find(&bookings).Error; err != nil {
+		return nil, errors.New("failed to fetch bookings")
+	}
+
+	// Los turnos ofrecidos a la lista de espera no se muestran como libres mientras dura el plazo exclusivo
+	claims, err := activeWaitlistClaims(s.db, courtID, date)
+	if err != nil {
+		return nil, err
+	}
+	for _, claim := range claims {
+		bookings = append(bookings, models.Booking{StartTime: claim.ClaimStartTime, EndTime: claim.ClaimEndTime})
+	}
+
+	// Generar slots de tiempo disponibles
+	var slots []*models.TimeSlot
+	for _, window := range schedule.Windows {
+		slots = append(slots, s.generateTimeSlots(window, duration, slotCfg.StepMinutes)...)
+	}
+	
+	// Filtrar slots ocupados
+	availability.Slots = s.filterAvailableSlots(slots, bookings, schedule, slotCfg.BufferMinutes)
+
+	// Calcular el precio de cada turno libre según las reglas de precio de la cancha
+	rules, err := loadPricingRules(s.db, courtID)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range availability.Slots {
+		start, end, err := schedule.toOperational(slot.StartTime, slot.EndTime)
+		if err != nil {
+			continue
+		}
+		slot.Price = calculatePrice(court, rules, date, start, end).Total
+	}
+
+	return availability, nil
+}
+
+func (s *CourtService) generateTimeSlots(window timeWindow, duration, step int) []*models.TimeSlot {
+	var slots []*models.TimeSlot
+	
+	// Cada horario de inicio válido según el intervalo de la cancha, siempre que el turno termine antes del cierre de la franja
+	for start := window.Open; start+duration <= window.Close; start += step {
+		slots = append(slots, &models.TimeSlot{
+			StartTime:       formatClock(start),
+			EndTime:         formatClock(start + duration),
+			DurationMinutes: duration,
+			Available:       true,
+		})
+	}
+	
+	return slots
+}
+
+func (s *CourtService) filterAvailableSlots(slots []*models.TimeSlot, bookings []models.Booking, schedule *daySchedule, bufferMinutes int) []*models.TimeSlot {
+	availableSlots := make([]*models.TimeSlot, 0)
+	
+	for _, slot := range slots {
+		isAvailable := true
+		for _, booking := range bookings {
+			if overlapsWithBuffer(schedule, slot.StartTime, slot.EndTime, booking.StartTime, booking.EndTime, bufferMinutes) {
+				isAvailable = false
+				break
+			}
+		}
+		
+		if isAvailable {
+			availableSlots = append(availableSlots, slot)
+		}
+	}
+	
+	return availableSlots
+}
+
+func (s *CourtService) GetCourtStatistics(ownerID uint) ([]models.CourtStatistics, error) {
+	var courts []models.Court
+	if err := s.db.Preload("Bookings").Preload("Reviews").Where("owner_id = ?", ownerID).Find(&courts).Error; err != nil {
+		return nil, errors.New("failed to fetch courts")
+	}
+
+	var statistics []models.CourtStatistics
+	for _, court := range courts {
+		totalBookings := len(court.Bookings)
+		reviewCount := len(court.Reviews)
+		
+		var revenue float64
+		for _, booking := range court.Bookings {
+			revenue += booking.TotalPrice
+		}
+		
+		statistics = append(statistics, models.CourtStatistics{
+			CourtID:       court.ID,
+			CourtName:     court.Name,
+			TotalBookings: totalBookings,
+			AverageRating: court.AverageRating,
+			ReviewCount:   reviewCount,
+			Revenue:       revenue,
+		})
+	}
+
+	return statistics, nil
+}
+
+func (s *CourtService) CreateSpecialHours(courtID uint, ownerID uint, req models.SpecialHour) (*models.SpecialHour, error) {
+	// Verificar que la cancha pertenece al propietario
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", courtID, ownerID).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+
+	specialHour := models.SpecialHour{
+		CourtID:   courtID,
+		Date:      req.Date,
+		OpenTime:  req.OpenTime,
+		CloseTime: req.CloseTime,
+		IsClosed:  req.IsClosed,
+		Reason:    req.Reason,
+	}
+
+	if err := s.db.Create(&specialHour).Error; err != nil {
+		return nil, errors.New("failed to create special hours")
+	}
+
+	return &specialHour, nil
+}
+
+func (s *CourtService) GetSpecialHours(courtID uint, startDate, endDate time.Time) ([]models.SpecialHour, error) {
+	var specialHours []models.SpecialHour
+	if err := s.db.Where("court_id = ? AND date BETWEEN ? AND ?", courtID, startDate, endDate).Find(&specialHours).Error; err != nil {
+		return nil, errors.New("failed to fetch special hours")
+	}
+	return specialHours, nil
+}
+
+func (s *CourtService) CreatePricingRule(courtID uint, ownerID uint, req *models.CreatePricingRuleRequest) (*models.PricingRule, error) {
+	// Verificar que la cancha pertenece al propietario
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", courtID, ownerID).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+
+	// La franja horaria debe indicar inicio y fin
+	if (req.StartTime == nil) != (req.EndTime == nil) {
+		return nil, errors.New("start_time and end_time must be provided together")
+	}
+	if req.StartTime != nil {
+		if _, err := parseClock(*req.StartTime); err != nil {
+			return nil, errors.New("invalid start_time format")
+		}
+		if _, err := parseClock(*req.EndTime); err != nil {
+			return nil, errors.New("invalid end_time format")
+		}
+	}
+	if req.Adjustment == "fixed" && req.Value <= 0 {
+		return nil, errors.New("fixed price must be greater than zero")
+	}
+
+	rule := models.PricingRule{
+		CourtID:          courtID,
+		Name:             req.Name,
+		Adjustment:       req.Adjustment,
+		Value:            req.Value,
+		DaysOfWeek:       req.DaysOfWeek,
+		StartTime:        req.StartTime,
+		EndTime:          req.EndTime,
+		RequiresLighting: req.RequiresLighting,
+		Priority:         req.Priority,
+		IsActive:         true,
+	}
+
+	if req.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start_date format")
+		}
+		rule.StartDate = &startDate
+	}
+	if req.EndDate != nil {
+		endDate, err := time.Parse("2006-01-02", *req.EndDate)
+		if err != nil {
+			return nil, errors.New("invalid end_date format")
+		}
+		rule.EndDate = &endDate
+	}
+	if rule.StartDate != nil && rule.EndDate != nil && rule.EndDate.Before(*rule.StartDate) {
+		return nil, errors.New("end_date must be after start_date")
+	}
+
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, errors.New("failed to create pricing rule")
+	}
+
+	return &rule, nil
+}
+
+func (s *CourtService) GetPricingRules(courtID uint) ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	if err := s.db.Where("court_id = ?", courtID).Order("priority DESC, id").Find(&rules).Error; err != nil {
+		return nil, errors.New("failed to fetch pricing rules")
+	}
+	return rules, nil
+}
+
+func (s *CourtService) DeletePricingRule(courtID uint, ruleID uint, ownerID uint) error {
+	// Verificar que la cancha pertenece al propietario
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", courtID, ownerID).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("court not found")
+		}
+		return errors.New("failed to fetch court")
+	}
+
+	result := s.db.Where("id = ? AND court_id = ?", ruleID, courtID).Delete(&models.PricingRule{})
+	if result.Error != nil {
+		return errors.New("failed to delete pricing rule")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("pricing rule not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,773 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeagueService struct {
+	db             *gorm.DB
+	bookingService *BookingService
+}
+
+func NewLeagueService(db *gorm.DB, bookingService *BookingService) *LeagueService {
+	return &LeagueService{db: db, bookingService: bookingService}
+}
+
+// CreateLeague crea una liga con sus divisiones, ordenadas de la más alta a la más baja
+func (s *LeagueService) CreateLeague(ownerID uint, req *models.CreateLeagueRequest) (*models.League, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format")
+	}
+	if startDate.Format("2006-01-02") < time.Now().Format("2006-01-02") {
+		return nil, errors.New("start date is in the past")
+	}
+	if len(req.Divisions) == 0 {
+		return nil, errors.New("at least one division is required")
+	}
+	if req.RoundDays < 0 {
+		return nil, errors.New("invalid round days")
+	}
+
+	league := models.League{
+		OwnerID:         ownerID,
+		Name:            req.Name,
+		Description:     req.Description,
+		Season:          1,
+		Status:          "registration",
+		StartDate:       startDate,
+		RoundDays:       7,
+		PointsWin:       3,
+		PointsLoss:      1,
+		PromotionSpots:  2,
+		RelegationSpots: 2,
+	}
+	if req.RoundDays > 0 {
+		league.RoundDays = req.RoundDays
+	}
+	for target, value := range map[*int]*int{
+		&league.PointsWin:       req.PointsWin,
+		&league.PointsLoss:      req.PointsLoss,
+		&league.PointsPerSet:    req.PointsPerSet,
+		&league.PromotionSpots:  req.PromotionSpots,
+		&league.RelegationSpots: req.RelegationSpots,
+	} {
+		if value == nil {
+			continue
+		}
+		if *value < 0 {
+			return nil, errors.New("points and promotion/relegation spots cannot be negative")
+		}
+		*target = *value
+	}
+
+	for i, name := range req.Divisions {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("division name is required")
+		}
+		league.Divisions = append(league.Divisions, models.LeagueDivision{Name: name, Level: i + 1})
+	}
+
+	if err := s.db.Create(&league).Error; err != nil {
+		return nil, errors.New("failed to create league")
+	}
+	return &league, nil
+}
+
+// GetLeagues lista las ligas con sus divisiones, de la temporada más reciente a la más antigua
+func (s *LeagueService) GetLeagues() ([]models.League, error) {
+	var leagues []models.League
+	err := s.db.Preload("Divisions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level")
+	}).Order("start_date DESC").Find(&leagues).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch leagues")
+	}
+	return leagues, nil
+}
+
+// GetOwnerLeagues lista las ligas del propietario
+func (s *LeagueService) GetOwnerLeagues(ownerID uint) ([]models.League, error) {
+	var leagues []models.League
+	err := s.db.Where("owner_id = ?", ownerID).Preload("Divisions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level")
+	}).Order("start_date DESC").Find(&leagues).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch leagues")
+	}
+	return leagues, nil
+}
+
+// GetLeagueByID obtiene una liga con sus divisiones y las parejas de cada una
+func (s *LeagueService) GetLeagueByID(id uint) (*models.League, error) {
+	var league models.League
+	err := s.db.Preload("Divisions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level")
+	}).Preload("Divisions.Teams").First(&league, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("league not found")
+		}
+		return nil, errors.New("failed to fetch league")
+	}
+	return &league, nil
+}
+
+// RegisterTeam inscribe al usuario y su compañero en una división mientras la liga acepta inscripciones
+func (s *LeagueService) RegisterTeam(leagueID uint, userID uint, req *models.RegisterLeagueTeamRequest) (*models.LeagueTeam, error) {
+	if req.PartnerID == 0 || req.PartnerID == userID {
+		return nil, errors.New("a partner is required")
+	}
+
+	var team models.LeagueTeam
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var league models.League
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&league, leagueID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("league not found")
+			}
+			return errors.New("failed to fetch league")
+		}
+		if league.Status != "registration" {
+			return errors.New("league registration is closed")
+		}
+
+		var division models.LeagueDivision
+		if err := tx.Where("id = ? AND league_id = ?", req.DivisionID, league.ID).First(&division).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("division not found")
+			}
+			return errors.New("failed to fetch division")
+		}
+
+		var player, partner models.User
+		if err := tx.First(&player, userID).Error; err != nil {
+			return errors.New("failed to fetch user")
+		}
+		if err := tx.Where("id = ? AND is_active = ?", req.PartnerID, true).First(&partner).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("partner not found")
+			}
+			return errors.New("failed to fetch user")
+		}
+
+		playerIDs := []uint{userID, req.PartnerID}
+		var registered int64
+		if err := tx.Model(&models.LeagueTeam{}).
+			Where("league_id = ? AND (player1_id IN ? OR player2_id IN ?)", league.ID, playerIDs, playerIDs).
+			Count(&registered).Error; err != nil {
+			return errors.New("failed to fetch league teams")
+		}
+		if registered > 0 {
+			return errors.New("player already registered")
+		}
+
+		team = models.LeagueTeam{
+			LeagueID:   league.ID,
+			DivisionID: division.ID,
+			Player1ID:  userID,
+			Player2ID:  req.PartnerID,
+			Name:       leagueTeamName(&player, &partner),
+		}
+		if err := tx.Create(&team).Error; err != nil {
+			return errors.New("failed to register team")
+		}
+
+		message := fmt.Sprintf("%s %s registered you as partner in the league %s (%s).", player.FirstName, player.LastName, league.Name, division.Name)
+		return notifyUser(tx, partner.ID, "league_registration", "You were registered in a league", message, "league", &league.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// StartLeague cierra la inscripción y genera las fechas de cada división con un todos contra todos.
+// Cada fecha tiene RoundDays días para jugarse a partir del inicio de la liga.
+func (s *LeagueService) StartLeague(leagueID uint, ownerID uint) (*models.League, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var league models.League
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", leagueID, ownerID).
+			Preload("Divisions.Teams").
+			First(&league).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("league not found")
+			}
+			return errors.New("failed to fetch league")
+		}
+		if league.Status != "registration" {
+			return errors.New("league already started")
+		}
+
+		var playerIDs []uint
+		for _, division := range league.Divisions {
+			teams := division.Teams
+			if len(teams) < 2 {
+				return errors.New("each division needs at least 2 teams")
+			}
+
+			for r, round := range roundRobin(len(teams)) {
+				dueDate := league.StartDate.AddDate(0, 0, (r+1)*league.RoundDays-1)
+				for _, game := range round {
+					// Se alterna la localía entre fechas
+					home, away := teams[game[0]], teams[game[1]]
+					if r%2 == 1 {
+						home, away = away, home
+					}
+					fixture := models.LeagueFixture{
+						LeagueID:   league.ID,
+						DivisionID: division.ID,
+						Round:      r + 1,
+						HomeTeamID: home.ID,
+						AwayTeamID: away.ID,
+						DueDate:    dueDate,
+						Status:     "pending",
+					}
+					if err := tx.Create(&fixture).Error; err != nil {
+						return errors.New("failed to create fixtures")
+					}
+				}
+			}
+			for _, team := range teams {
+				playerIDs = append(playerIDs, team.Player1ID, team.Player2ID)
+			}
+		}
+
+		if err := tx.Model(&league).Update("status", "in_progress").Error; err != nil {
+			return errors.New("failed to update league")
+		}
+
+		message := fmt.Sprintf("The fixtures of the league %s are ready. Book a court with your rivals before each round deadline.", league.Name)
+		for _, playerID := range playerIDs {
+			if err := notifyUser(tx, playerID, "league_started", "League started", message, "league", &league.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLeagueByID(leagueID)
+}
+
+// GetFixtures lista los partidos de la liga, con filtros por división y fecha
+func (s *LeagueService) GetFixtures(leagueID uint, filters *models.GetLeagueFixturesRequest) ([]models.LeagueFixture, error) {
+	query := s.db.Where("league_id = ?", leagueID).Preload("HomeTeam").Preload("AwayTeam")
+	if filters.DivisionID != nil {
+		query = query.Where("division_id = ?", *filters.DivisionID)
+	}
+	if filters.Round != nil {
+		query = query.Where("round = ?", *filters.Round)
+	}
+
+	var fixtures []models.LeagueFixture
+	if err := query.Order("round, division_id, id").Find(&fixtures).Error; err != nil {
+		return nil, errors.New("failed to fetch fixtures")
+	}
+	return fixtures, nil
+}
+
+// BookFixture reserva una cancha del club para jugar el partido. La reserva queda a nombre del jugador,
+// dividida entre los cuatro jugadores, que quedan como participantes para ver la reserva y pagar su parte.
+func (s *LeagueService) BookFixture(leagueID uint, fixtureID uint, userID uint, req *models.BookLeagueFixtureRequest) (*models.LeagueFixture, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format")
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		league, fixture, _, err := lockPlayerFixture(tx, leagueID, fixtureID, userID)
+		if err != nil {
+			return err
+		}
+		if fixture.Status == "scheduled" && fixture.BookingID != nil {
+			// Se puede volver a reservar si la reserva anterior se canceló o venció
+			var previous models.Booking
+			if err := tx.First(&previous, *fixture.BookingID).Error; err == nil && previous.Status != "cancelled" && previous.Status != "expired" {
+				return errors.New("fixture already scheduled")
+			}
+		} else if fixture.Status != "pending" {
+			return errors.New("fixture already scheduled")
+		}
+		if date.After(fixture.DueDate) {
+			return errors.New("date is after the round deadline")
+		}
+
+		// Solo se juega en canchas del club que organiza la liga
+		var court models.Court
+		if err := tx.Where("id = ? AND owner_id = ?", req.CourtID, league.OwnerID).First(&court).Error; err != nil {
+			return errors.New("court not found")
+		}
+
+		players := []uint{fixture.HomeTeam.Player1ID, fixture.HomeTeam.Player2ID, fixture.AwayTeam.Player1ID, fixture.AwayTeam.Player2ID}
+		booking, err := s.bookingService.createOnlineBooking(tx, userID, date, &models.CreateBookingRequest{
+			CourtID:      court.ID,
+			Date:         req.Date,
+			StartTime:    req.StartTime,
+			EndTime:      req.EndTime,
+			SplitPlayers: len(players),
+			Notes:        fmt.Sprintf("%s - round %d: %s vs %s", league.Name, fixture.Round, fixture.HomeTeam.Name, fixture.AwayTeam.Name),
+		})
+		if err != nil {
+			return err
+		}
+
+		var users []models.User
+		if err := tx.Where("id IN ?", players).Find(&users).Error; err != nil {
+			return errors.New("failed to fetch users")
+		}
+		now := time.Now()
+		message := fmt.Sprintf("Your league match of round %d was booked at %s on %s from %s to %s.",
+			fixture.Round, court.Name, req.Date, req.StartTime, req.EndTime)
+		for i := range users {
+			if users[i].ID == userID {
+				continue
+			}
+			participant := models.BookingParticipant{
+				BookingID:   booking.ID,
+				UserID:      &users[i].ID,
+				Email:       users[i].Email,
+				Status:      "accepted",
+				InvitedBy:   userID,
+				RespondedAt: &now,
+			}
+			if err := tx.Create(&participant).Error; err != nil {
+				return errors.New("failed to add participants")
+			}
+			if err := notifyUser(tx, users[i].ID, "league_fixture_scheduled", "League match booked", message, "booking", &booking.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(fixture).Updates(map[string]interface{}{"booking_id": booking.ID, "status": "scheduled"}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFixture(fixtureID)
+}
+
+// ReportResult carga el resultado del partido informado por una de las parejas; queda pendiente de la confirmación de la rival
+func (s *LeagueService) ReportResult(leagueID uint, fixtureID uint, userID uint, req *models.ReportLeagueResultRequest) (*models.LeagueFixture, error) {
+	winnerTeam, err := matchWinner(req.Sets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		league, fixture, teamID, err := lockPlayerFixture(tx, leagueID, fixtureID, userID)
+		if err != nil {
+			return err
+		}
+		if fixture.Status == "confirmed" {
+			return errors.New("result already confirmed")
+		}
+		if fixture.Status == "reported" && *fixture.ReportedTeamID != teamID {
+			return errors.New("result already reported by the opponent")
+		}
+
+		applyLeagueScore(fixture, req.Sets, winnerTeam)
+		fixture.Status = "reported"
+		fixture.ReportedTeamID = &teamID
+		fixture.ReportedBy = &userID
+		if err := tx.Omit(clause.Associations).Save(fixture).Error; err != nil {
+			return errors.New("failed to report result")
+		}
+
+		opponent := fixture.AwayTeam
+		if teamID == fixture.AwayTeamID {
+			opponent = fixture.HomeTeam
+		}
+		message := fmt.Sprintf("The result of your league match of round %d in %s was reported: %s. Confirm it or dispute it.",
+			fixture.Round, league.Name, formatSets(req.Sets))
+		for _, playerID := range []uint{opponent.Player1ID, opponent.Player2ID} {
+			if err := notifyUser(tx, playerID, "league_result_reported", "Confirm your league result", message, "league_fixture", &fixture.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFixture(fixtureID)
+}
+
+// ConfirmResult confirma el resultado informado por la pareja rival; desde ese momento cuenta para la tabla
+func (s *LeagueService) ConfirmResult(leagueID uint, fixtureID uint, userID uint) (*models.LeagueFixture, error) {
+	return s.respondResult(leagueID, fixtureID, userID, true)
+}
+
+// DisputeResult rechaza el resultado informado por la pareja rival; el organizador define el resultado
+func (s *LeagueService) DisputeResult(leagueID uint, fixtureID uint, userID uint) (*models.LeagueFixture, error) {
+	return s.respondResult(leagueID, fixtureID, userID, false)
+}
+
+func (s *LeagueService) respondResult(leagueID uint, fixtureID uint, userID uint, confirm bool) (*models.LeagueFixture, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		league, fixture, teamID, err := lockPlayerFixture(tx, leagueID, fixtureID, userID)
+		if err != nil {
+			return err
+		}
+		if fixture.Status != "reported" {
+			return errors.New("no result to confirm")
+		}
+		if *fixture.ReportedTeamID == teamID {
+			return errors.New("the result must be confirmed by the opponent")
+		}
+
+		updates := map[string]interface{}{"status": "disputed"}
+		notificationType, title := "league_result_disputed", "League result disputed"
+		if confirm {
+			now := time.Now()
+			updates = map[string]interface{}{"status": "confirmed", "confirmed_by": userID, "confirmed_at": now}
+			notificationType, title = "league_result_confirmed", "League result confirmed"
+		}
+		if err := tx.Model(fixture).Updates(updates).Error; err != nil {
+			return errors.New("failed to update result")
+		}
+
+		message := fmt.Sprintf("The result of your league match of round %d in %s was %s by your opponent.",
+			fixture.Round, league.Name, updates["status"])
+		recipients := []uint{*fixture.ReportedBy}
+		if !confirm {
+			recipients = append(recipients, league.OwnerID)
+		}
+		for _, recipient := range recipients {
+			if err := notifyUser(tx, recipient, notificationType, title, message, "league_fixture", &fixture.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFixture(fixtureID)
+}
+
+// OwnerRecordResult permite al organizador cargar o corregir el resultado de un partido (por ejemplo, uno en disputa); queda confirmado
+func (s *LeagueService) OwnerRecordResult(leagueID uint, fixtureID uint, ownerID uint, req *models.ReportLeagueResultRequest) (*models.LeagueFixture, error) {
+	winnerTeam, err := matchWinner(req.Sets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		fixture, err := lockOwnerFixture(tx, leagueID, fixtureID, ownerID)
+		if err != nil {
+			return err
+		}
+
+		applyLeagueScore(fixture, req.Sets, winnerTeam)
+		now := time.Now()
+		fixture.Status = "confirmed"
+		fixture.ConfirmedBy = &ownerID
+		fixture.ConfirmedAt = &now
+		if err := tx.Omit(clause.Associations).Save(fixture).Error; err != nil {
+			return errors.New("failed to record result")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFixture(fixtureID)
+}
+
+// GetStandings calcula la tabla de cada división con los resultados confirmados
+func (s *LeagueService) GetStandings(leagueID uint) (*models.LeagueStandingsResponse, error) {
+	league, err := s.GetLeagueByID(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	divisions, err := leagueStandings(s.db, league)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LeagueStandingsResponse{LeagueID: league.ID, Season: league.Season, Divisions: divisions}, nil
+}
+
+// StartNextSeason cierra la temporada y crea la siguiente con las mismas divisiones y reglas. Las primeras
+// parejas de cada división ascienden y las últimas descienden según PromotionSpots y RelegationSpots.
+func (s *LeagueService) StartNextSeason(leagueID uint, ownerID uint, req *models.NextSeasonRequest) (*models.League, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format")
+	}
+
+	var next models.League
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var league models.League
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", leagueID, ownerID).
+			Preload("Divisions", func(db *gorm.DB) *gorm.DB {
+				return db.Order("level")
+			}).Preload("Divisions.Teams").
+			First(&league).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("league not found")
+			}
+			return errors.New("failed to fetch league")
+		}
+		if league.Status != "in_progress" {
+			return errors.New("league is not in progress")
+		}
+		if !startDate.After(league.StartDate) {
+			return errors.New("next season must start after the current one")
+		}
+
+		standings, err := leagueStandings(tx, &league)
+		if err != nil {
+			return err
+		}
+
+		next = league
+		next.ID = 0
+		next.Season = league.Season + 1
+		next.PreviousLeagueID = &league.ID
+		next.Status = "registration"
+		next.StartDate = startDate
+		next.CreatedAt, next.UpdatedAt = time.Time{}, time.Time{}
+		next.Divisions = make([]models.LeagueDivision, len(league.Divisions))
+		for i, division := range league.Divisions {
+			next.Divisions[i] = models.LeagueDivision{Name: division.Name, Level: division.Level}
+		}
+		if err := tx.Create(&next).Error; err != nil {
+			return errors.New("failed to create next season")
+		}
+
+		// Cada pareja pasa a la división de arriba, a la de abajo o se queda según su posición
+		teamsByID := make(map[uint]models.LeagueTeam)
+		for _, division := range league.Divisions {
+			for _, team := range division.Teams {
+				teamsByID[team.ID] = team
+			}
+		}
+		for i, division := range standings {
+			for _, row := range division.Standings {
+				target := i
+				if row.Movement == "promotion" {
+					target = i - 1
+				} else if row.Movement == "relegation" {
+					target = i + 1
+				}
+				team := teamsByID[row.TeamID]
+				newTeam := models.LeagueTeam{
+					LeagueID:   next.ID,
+					DivisionID: next.Divisions[target].ID,
+					Player1ID:  team.Player1ID,
+					Player2ID:  team.Player2ID,
+					Name:       team.Name,
+				}
+				if err := tx.Create(&newTeam).Error; err != nil {
+					return errors.New("failed to create next season teams")
+				}
+
+				message := fmt.Sprintf("Season %d of %s starts on %s. Your team plays in %s.",
+					next.Season, next.Name, req.StartDate, next.Divisions[target].Name)
+				for _, playerID := range []uint{team.Player1ID, team.Player2ID} {
+					if err := notifyUser(tx, playerID, "league_new_season", "New league season", message, "league", &next.ID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if err := tx.Model(&league).Update("status", "finished").Error; err != nil {
+			return errors.New("failed to update league")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLeagueByID(next.ID)
+}
+
+// leagueStandings calcula la tabla de cada división y marca las posiciones de ascenso y descenso.
+// Se ordena por puntos, diferencia de sets y diferencia de games.
+func leagueStandings(db *gorm.DB, league *models.League) ([]models.DivisionStandings, error) {
+	var fixtures []models.LeagueFixture
+	if err := db.Where("league_id = ? AND status = ?", league.ID, "confirmed").Find(&fixtures).Error; err != nil {
+		return nil, errors.New("failed to fetch fixtures")
+	}
+
+	divisions := make([]models.DivisionStandings, 0, len(league.Divisions))
+	for i, division := range league.Divisions {
+		rows := make([]models.LeagueStanding, len(division.Teams))
+		index := make(map[uint]*models.LeagueStanding, len(division.Teams))
+		for t, team := range division.Teams {
+			rows[t] = models.LeagueStanding{TeamID: team.ID, Name: team.Name}
+			index[team.ID] = &rows[t]
+		}
+
+		for _, fixture := range fixtures {
+			home, away := index[fixture.HomeTeamID], index[fixture.AwayTeamID]
+			if home == nil || away == nil {
+				continue
+			}
+			homeGames, awayGames := 0, 0
+			for _, set := range fixture.Sets {
+				homeGames += set.Team1
+				awayGames += set.Team2
+			}
+			addLeagueResult(league, home, fixture.HomeSets, fixture.AwaySets, homeGames, awayGames)
+			addLeagueResult(league, away, fixture.AwaySets, fixture.HomeSets, awayGames, homeGames)
+		}
+
+		sort.SliceStable(rows, func(a, b int) bool {
+			x, y := rows[a], rows[b]
+			if x.Points != y.Points {
+				return x.Points > y.Points
+			}
+			if x.SetsFor-x.SetsAgainst != y.SetsFor-y.SetsAgainst {
+				return x.SetsFor-x.SetsAgainst > y.SetsFor-y.SetsAgainst
+			}
+			return x.GamesFor-x.GamesAgainst > y.GamesFor-y.GamesAgainst
+		})
+
+		// La división más alta no asciende y la más baja no desciende
+		for position := range rows {
+			if i > 0 && position < league.PromotionSpots {
+				rows[position].Movement = "promotion"
+			} else if i < len(league.Divisions)-1 && position >= len(rows)-league.RelegationSpots {
+				rows[position].Movement = "relegation"
+			}
+		}
+
+		divisions = append(divisions, models.DivisionStandings{
+			DivisionID: division.ID,
+			Name:       division.Name,
+			Level:      division.Level,
+			Standings:  rows,
+		})
+	}
+	return divisions, nil
+}
+
+func addLeagueResult(league *models.League, row *models.LeagueStanding, setsFor, setsAgainst, gamesFor, gamesAgainst int) {
+	row.Played++
+	row.SetsFor += setsFor
+	row.SetsAgainst += setsAgainst
+	row.GamesFor += gamesFor
+	row.GamesAgainst += gamesAgainst
+	row.Points += setsFor * league.PointsPerSet
+	if setsFor > setsAgainst {
+		row.Won++
+		row.Points += league.PointsWin
+	} else {
+		row.Lost++
+		row.Points += league.PointsLoss
+	}
+}
+
+// lockPlayerFixture bloquea el partido verificando que el usuario juegue en él y que la liga esté en curso.
+// Devuelve la pareja del usuario.
+func lockPlayerFixture(tx *gorm.DB, leagueID uint, fixtureID uint, userID uint) (*models.League, *models.LeagueFixture, uint, error) {
+	var fixture models.LeagueFixture
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND league_id = ?", fixtureID, leagueID).
+		Preload("HomeTeam").Preload("AwayTeam").
+		First(&fixture).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, 0, errors.New("fixture not found")
+		}
+		return nil, nil, 0, errors.New("failed to fetch fixture")
+	}
+
+	var league models.League
+	if err := tx.First(&league, fixture.LeagueID).Error; err != nil {
+		return nil, nil, 0, errors.New("failed to fetch league")
+	}
+	if league.Status != "in_progress" {
+		return nil, nil, 0, errors.New("league is not in progress")
+	}
+
+	switch userID {
+	case fixture.HomeTeam.Player1ID, fixture.HomeTeam.Player2ID:
+		return &league, &fixture, fixture.HomeTeamID, nil
+	case fixture.AwayTeam.Player1ID, fixture.AwayTeam.Player2ID:
+		return &league, &fixture, fixture.AwayTeamID, nil
+	}
+	return nil, nil, 0, errors.New("only players of this fixture can do this")
+}
+
+// lockOwnerFixture bloquea el partido de una liga en curso del organizador, igual que lockPlayerFixture para los
+// jugadores, para que su resultado no se cruce con una confirmación o un reporte simultáneo
+func lockOwnerFixture(tx *gorm.DB, leagueID uint, fixtureID uint, ownerID uint) (*models.LeagueFixture, error) {
+	var league models.League
+	if err := tx.Where("id = ? AND owner_id = ?", leagueID, ownerID).First(&league).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("league not found")
+		}
+		return nil, errors.New("failed to fetch league")
+	}
+	if league.Status != "in_progress" {
+		return nil, errors.New("league is not in progress")
+	}
+
+	var fixture models.LeagueFixture
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND league_id = ?", fixtureID, league.ID).First(&fixture).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("fixture not found")
+		}
+		return nil, errors.New("failed to fetch fixture")
+	}
+	return &fixture, nil
+}
+
+// applyLeagueScore calcula los sets de cada pareja y la ganadora; Team1 es la local
+func applyLeagueScore(fixture *models.LeagueFixture, sets []models.SetScore, winnerTeam int) {
+	fixture.HomeSets, fixture.AwaySets = 0, 0
+	for _, set := range sets {
+		if set.Team1 > set.Team2 {
+			fixture.HomeSets++
+		} else {
+			fixture.AwaySets++
+		}
+	}
+	winner := fixture.HomeTeamID
+	if winnerTeam == 2 {
+		winner = fixture.AwayTeamID
+	}
+	fixture.WinnerTeamID = &winner
+	fixture.Sets = sets
+}
+
+func formatSets(sets []models.SetScore) string {
+	parts := make([]string, 0, len(sets))
+	for _, set := range sets {
+		parts = append(parts, fmt.Sprintf("%d-%d", set.Team1, set.Team2))
+	}
+	return strings.Join(parts, " ")
+}
+
+func leagueTeamName(player, partner *models.User) string {
+	return strings.TrimSpace(player.FirstName+" "+player.LastName) + " / " + strings.TrimSpace(partner.FirstName+" "+partner.LastName)
+}
+
+func (s *LeagueService) getFixture(id uint) (*models.LeagueFixture, error) {
+	var fixture models.LeagueFixture
+	if err := s.db.Preload("HomeTeam").Preload("AwayTeam").Preload("Booking").First(&fixture, id).Error; err != nil {
+		return nil, errors.New("failed to load fixture")
+	}
+	return &fixture, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// newPlayedLeague crea una liga de dos divisiones con tres parejas cada una, con un ascenso y un descenso, y
+// carga todos los resultados: en cada división la pareja inscripta antes le gana a las siguientes
+func newPlayedLeague(t *testing.T, db *gorm.DB, service *LeagueService, owner models.User) (*models.League, [][]models.LeagueTeam) {
+	t.Helper()
+	one := 1
+	league, err := service.CreateLeague(owner.ID, &models.CreateLeagueRequest{
+		Name:            "Liga",
+		StartDate:       testutil.Day(1).Format("2006-01-02"),
+		Divisions:       []string{"Primera", "Segunda"},
+		PromotionSpots:  &one,
+		RelegationSpots: &one,
+	})
+	if err != nil {
+		t.Fatalf("create league: %v", err)
+	}
+
+	teams := make([][]models.LeagueTeam, len(league.Divisions))
+	for d, division := range league.Divisions {
+		for i := 0; i < 3; i++ {
+			player := testutil.CreateUser(t, db, fmt.Sprintf("d%d-t%d-a@test.com", d, i), "user")
+			partner := testutil.CreateUser(t, db, fmt.Sprintf("d%d-t%d-b@test.com", d, i), "user")
+			team, err := service.RegisterTeam(league.ID, player.ID, &models.RegisterLeagueTeamRequest{DivisionID: division.ID, PartnerID: partner.ID})
+			if err != nil {
+				t.Fatalf("register team: %v", err)
+			}
+			teams[d] = append(teams[d], *team)
+		}
+	}
+	if _, err := service.StartLeague(league.ID, owner.ID); err != nil {
+		t.Fatalf("start league: %v", err)
+	}
+
+	fixtures, err := service.GetFixtures(league.ID, &models.GetLeagueFixturesRequest{})
+	if err != nil {
+		t.Fatalf("fetch fixtures: %v", err)
+	}
+	for _, fixture := range fixtures {
+		sets := []models.SetScore{{Team1: 6, Team2: 2}, {Team1: 6, Team2: 3}}
+		if fixture.AwayTeamID < fixture.HomeTeamID {
+			sets = []models.SetScore{{Team1: 2, Team2: 6}, {Team1: 3, Team2: 6}}
+		}
+		if _, err := service.OwnerRecordResult(league.ID, fixture.ID, owner.ID, &models.ReportLeagueResultRequest{Sets: sets}); err != nil {
+			t.Fatalf("record result: %v", err)
+		}
+	}
+	return league, teams
+}
+
+func TestLeagueStandingsRankTeamsAndMarkMovements(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	service := NewLeagueService(db, NewBookingService(db, NewPaymentService(db, &fakeGateway{})))
+	league, teams := newPlayedLeague(t, db, service, owner)
+
+	standings, err := service.GetStandings(league.ID)
+	if err != nil {
+		t.Fatalf("standings: %v", err)
+	}
+	// Victoria 3 puntos y derrota 1: 6, 4 y 2 puntos. La primera división no asciende y la última no desciende.
+	expected := [][]struct {
+		points   int
+		movement string
+	}{
+		{{6, ""}, {4, ""}, {2, "relegation"}},
+		{{6, "promotion"}, {4, ""}, {2, ""}},
+	}
+	for d, division := range standings.Divisions {
+		for position, row := range division.Standings {
+			want := expected[d][position]
+			if row.TeamID != teams[d][position].ID || row.Played != 2 || row.Points != want.points || row.Movement != want.movement {
+				t.Fatalf("division %s position %d: expected team %d with %d points (%q), got %+v",
+					division.Name, position+1, teams[d][position].ID, want.points, want.movement, row)
+			}
+		}
+	}
+}
+
+func TestOwnerResultOverridesAPendingReport(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	service := NewLeagueService(db, NewBookingService(db, NewPaymentService(db, &fakeGateway{})))
+	league, _ := newPlayedLeague(t, db, service, owner)
+
+	fixtures, _ := service.GetFixtures(league.ID, &models.GetLeagueFixturesRequest{})
+	fixture := fixtures[0]
+	// El partido vuelve a quedar sin resultado para que lo informe una de las parejas
+	db.Model(&models.LeagueFixture{}).Where("id = ?", fixture.ID).Update("status", "pending")
+
+	sets := []models.SetScore{{Team1: 6, Team2: 4}, {Team1: 6, Team2: 4}}
+	if _, err := service.ReportResult(league.ID, fixture.ID, fixture.HomeTeam.Player1ID, &models.ReportLeagueResultRequest{Sets: sets}); err != nil {
+		t.Fatalf("report result: %v", err)
+	}
+	recorded, err := service.OwnerRecordResult(league.ID, fixture.ID, owner.ID, &models.ReportLeagueResultRequest{Sets: []models.SetScore{{Team1: 1, Team2: 6}, {Team1: 2, Team2: 6}}})
+	if err != nil {
+		t.Fatalf("owner record result: %v", err)
+	}
+	if recorded.Status != "confirmed" || recorded.WinnerTeamID == nil || *recorded.WinnerTeamID != fixture.AwayTeamID {
+		t.Fatalf("expected the owner's result confirmed for the away team, got %s", recorded.Status)
+	}
+	if _, err := service.ConfirmResult(league.ID, fixture.ID, fixture.AwayTeam.Player1ID); err == nil || err.Error() != "no result to confirm" {
+		t.Fatalf("expected the overridden report not to be confirmable, got %v", err)
+	}
+	if _, err := service.OwnerRecordResult(league.ID, fixture.ID, fixture.HomeTeam.Player1ID, &models.ReportLeagueResultRequest{Sets: sets}); err == nil || err.Error() != "league not found" {
+		t.Fatalf("expected only the organizer to record results, got %v", err)
+	}
+}
+
+func TestNextSeasonPromotesAndRelegatesTeams(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	service := NewLeagueService(db, NewBookingService(db, NewPaymentService(db, &fakeGateway{})))
+	league, teams := newPlayedLeague(t, db, service, owner)
+
+	next, err := service.StartNextSeason(league.ID, owner.ID, &models.NextSeasonRequest{StartDate: testutil.Day(60).Format("2006-01-02")})
+	if err != nil {
+		t.Fatalf("start next season: %v", err)
+	}
+	if next.Season != 2 || next.Status != "registration" || next.PreviousLeagueID == nil || *next.PreviousLeagueID != league.ID {
+		t.Fatalf("expected season 2 open for registration, got season %d (%s)", next.Season, next.Status)
+	}
+
+	// El último de Primera desciende y el primero de Segunda asciende; el resto se queda en su división
+	expected := [][]models.LeagueTeam{
+		{teams[0][0], teams[0][1], teams[1][0]},
+		{teams[0][2], teams[1][1], teams[1][2]},
+	}
+	for d, division := range next.Divisions {
+		players := map[uint]bool{}
+		for _, team := range division.Teams {
+			players[team.Player1ID] = true
+		}
+		if len(division.Teams) != len(expected[d]) {
+			t.Fatalf("division %s: expected %d teams, got %d", division.Name, len(expected[d]), len(division.Teams))
+		}
+		for _, team := range expected[d] {
+			if !players[team.Player1ID] {
+				t.Fatalf("division %s: expected team %s, got %+v", division.Name, team.Name, division.Teams)
+			}
+		}
+	}
+
+	var previous models.League
+	db.First(&previous, league.ID)
+	if previous.Status != "finished" {
+		t.Fatalf("expected the previous season finished, got %s", previous.Status)
+	}
+}
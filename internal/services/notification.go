@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationService struct {
+	db *gorm.DB
+}
+
+func NewNotificationService(db *gorm.DB) *NotificationService {
+	return &NotificationService{db: db}
+}
+
+func (s *NotificationService) GetUserNotifications(userID uint, filters *models.GetNotificationsRequest) ([]models.Notification, error) {
+	query := s.db.Where("user_id = ?", userID)
+	if filters.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Limit(100).Find(&notifications).Error; err != nil {
+		return nil, errors.New("failed to fetch notifications")
+	}
+	return notifications, nil
+}
+
+func (s *NotificationService) MarkAsRead(id uint, userID uint) (*models.Notification, error) {
+	var notification models.Notification
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, errors.New("failed to fetch notification")
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		if err := s.db.Model(&notification).Update("read_at", now).Error; err != nil {
+			return nil, errors.New("failed to update notification")
+		}
+		notification.ReadAt = &now
+	}
+	return &notification, nil
+}
+
+// notifyUser registra una notificación para un usuario. Se ejecuta dentro de la transacción que origina el aviso.
+func notifyUser(tx *gorm.DB, userID uint, notificationType, title, message, referenceType string, referenceID *uint) error {
+	notification := models.Notification{
+		UserID:        userID,
+		Type:          notificationType,
+		Title:         title,
+		Message:       message,
+		ReferenceType: referenceType,
+		ReferenceID:   referenceID,
+	}
+	if err := tx.Create(&notification).Error; err != nil {
+		return errors.New("failed to create notification")
+	}
+	return nil
+}
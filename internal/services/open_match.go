@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PublishOpenMatch publica una reserva del organizador como partido abierto para que otros jugadores se sumen.
+// Si la reserva todavía no tiene pagos, el precio se divide entre los jugadores de la cancha para que cada uno pague su parte.
+func (s *BookingService) PublishOpenMatch(bookingID uint, organizerID uint, req *models.PublishOpenMatchRequest) (*models.OpenMatchResponse, error) {
+	if req.MinLevel < 1 || req.MaxLevel > 7 || req.MinLevel > req.MaxLevel {
+		return nil, errors.New("invalid level range")
+	}
+
+	var match models.OpenMatch
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var booking models.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND user_id = ?", bookingID, organizerID).Preload("Court").First(&booking).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("booking not found")
+			}
+			return errors.New("failed to fetch booking")
+		}
+		if booking.Status != "pending" && booking.Status != "confirmed" {
+			return errors.New("booking cannot be published in its current status")
+		}
+		if booking.Date.Format("2006-01-02") < time.Now().Format("2006-01-02") {
+			return ErrBookingInPast
+		}
+
+		// Los lugares libres no pueden superar los que quedan después del organizador y los invitados
+		var active int64
+		if err := tx.Model(&models.BookingParticipant{}).Scopes(activeParticipants).Where("booking_id = ?", booking.ID).Count(&active).Error; err != nil {
+			return errors.New("failed to fetch participants")
+		}
+		if req.FreeSpots < 1 || req.FreeSpots > booking.Court.MaxPlayers-1-int(active) {
+			return errors.New("not enough spots in this booking")
+		}
+
+		if err := splitAmongCourtPlayers(tx, &booking); err != nil {
+			return err
+		}
+
+		err := tx.Where("booking_id = ?", booking.ID).First(&match).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to fetch open match")
+		}
+		match.BookingID = booking.ID
+		match.OrganizerID = organizerID
+		match.MinLevel = req.MinLevel
+		match.MaxLevel = req.MaxLevel
+		match.FreeSpots = req.FreeSpots
+		match.Status = "open"
+		match.Description = req.Description
+		if err := tx.Save(&match).Error; err != nil {
+			return errors.New("failed to publish open match")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getOpenMatch(match.ID)
+}
+
+// CloseOpenMatch retira la publicación del partido abierto; los jugadores que ya se sumaron siguen en la reserva
+func (s *BookingService) CloseOpenMatch(bookingID uint, organizerID uint) error {
+	result := s.db.Model(&models.OpenMatch{}).
+		Where("booking_id = ? AND organizer_id = ? AND status <> ?", bookingID, organizerID, "closed").
+		Update("status", "closed")
+	if result.Error != nil {
+		return errors.New("failed to close open match")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("open match not found")
+	}
+	return nil
+}
+
+// SearchOpenMatches busca partidos abiertos con lugares libres, por cercanía, fecha y nivel.
+// Si no se indica un nivel se usa el que corresponde al rating del jugador.
+func (s *BookingService) SearchOpenMatches(userID uint, req *models.SearchOpenMatchesRequest) ([]*models.OpenMatchResponse, error) {
+	query := s.db.Model(&models.OpenMatch{}).
+		Joins("JOIN bookings ON bookings.id = open_matches.booking_id AND bookings.deleted_at IS NULL").
+		Joins("JOIN courts ON courts.id = bookings.court_id AND courts.is_active = ?", true).
+		Where("open_matches.status = ? AND open_matches.free_spots > 0", "open").
+		Where("bookings.status IN ? AND bookings.date >= ?", []string{"pending", "confirmed"}, time.Now().Format("2006-01-02"))
+
+	if req.Date != nil {
+		query = query.Where("bookings.date = ?", *req.Date)
+	}
+	level, hasLevel := playerLevel(s.db, userID)
+	if req.Level != nil {
+		level, hasLevel = *req.Level, true
+	}
+	if hasLevel {
+		query = query.Where("open_matches.min_level <= ? AND open_matches.max_level >= ?", level, level)
+	}
+	if req.Latitude != nil && req.Longitude != nil {
+		radius := 20.0 // radio por defecto en km
+		if req.Radius != nil {
+			radius = *req.Radius
+		}
+		query = query.Joins(courtClubJoin).Scopes(withinRadius(courtLatitude, courtLongitude, *req.Latitude, *req.Longitude, radius))
+	}
+
+	var matches []models.OpenMatch
+	err := query.Scopes(preloadOpenMatch).
+		Order("bookings.date, bookings.start_time").
+		Limit(100).
+		Find(&matches).Error
+	if err != nil {
+		return nil, errors.New("failed to search open matches")
+	}
+
+	responses := make([]*models.OpenMatchResponse, 0, len(matches))
+	for i := range matches {
+		responses = append(responses, toOpenMatchResponse(&matches[i]))
+	}
+	return responses, nil
+}
+
+// JoinOpenMatch suma al usuario como participante del partido. Si la reserva todavía no tiene pagos su precio
+// se divide entre los jugadores de la cancha, y el jugador paga su parte como en cualquier reserva dividida.
+func (s *BookingService) JoinOpenMatch(matchID uint, userID uint) (*models.OpenMatchResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var match models.OpenMatch
+		if err := tx.First(&match, matchID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("open match not found")
+			}
+			return errors.New("failed to fetch open match")
+		}
+
+		// Bloquear la reserva, como al invitar jugadores, para que dos jugadores no ocupen el mismo lugar
+		var booking models.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Court").First(&booking, match.BookingID).Error; err != nil {
+			return errors.New("failed to fetch booking")
+		}
+		// Con la reserva bloqueada se relee el partido para contar los lugares libres actuales
+		if err := tx.First(&match, matchID).Error; err != nil {
+			return errors.New("failed to fetch open match")
+		}
+
+		if match.Status == "closed" || (booking.Status != "pending" && booking.Status != "confirmed") {
+			return errors.New("match is not open")
+		}
+		if booking.UserID != nil && *booking.UserID == userID {
+			return errors.New("already in this match")
+		}
+
+		// El nivel del jugador según su rating tiene que estar dentro del rango publicado
+		if level, ok := playerLevel(tx, userID); ok && (level < match.MinLevel || level > match.MaxLevel) {
+			return errors.New("player level out of range for this match")
+		}
+
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return errors.New("failed to fetch user")
+		}
+
+		var participant models.BookingParticipant
+		err := tx.Where("booking_id = ? AND (user_id = ? OR email = ?)", booking.ID, userID, user.Email).First(&participant).Error
+		if err == nil && participant.Status == "accepted" {
+			return errors.New("already in this match")
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to fetch participants")
+		}
+
+		// Un jugador invitado ya tiene su lugar reservado: no compite por los lugares publicados
+		wasInvited := err == nil && participant.Status == "invited"
+		if !wasInvited {
+			if match.Status == "full" || match.FreeSpots <= 0 {
+				return errors.New("match is full")
+			}
+			var active int64
+			if err := tx.Model(&models.BookingParticipant{}).Scopes(activeParticipants).Where("booking_id = ?", booking.ID).Count(&active).Error; err != nil {
+				return errors.New("failed to fetch participants")
+			}
+			if int(active)+1 >= booking.Court.MaxPlayers {
+				return errors.New("match is full")
+			}
+		}
+
+		// Un jugador invitado que se suma desde el partido abierto acepta la invitación
+		now := time.Now()
+		participant.BookingID = booking.ID
+		participant.UserID = &userID
+		participant.Email = user.Email
+		participant.Status = "accepted"
+		participant.RespondedAt = &now
+		if participant.InvitedBy == 0 {
+			participant.InvitedBy = match.OrganizerID
+		}
+		if err := tx.Save(&participant).Error; err != nil {
+			return errors.New("failed to join match")
+		}
+
+		// Si ya tenía lugar como invitado no ocupa uno de los lugares publicados
+		if !wasInvited {
+			updates := map[string]interface{}{"free_spots": match.FreeSpots - 1}
+			if match.FreeSpots-1 == 0 {
+				updates["status"] = "full"
+			}
+			if err := tx.Model(&match).Updates(updates).Error; err != nil {
+				return errors.New("failed to update open match")
+			}
+		}
+
+		if err := splitAmongCourtPlayers(tx, &booking); err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("%s %s joined your match at %s on %s from %s to %s.",
+			user.FirstName, user.LastName, booking.Court.Name, booking.Date.Format("2006-01-02"), booking.StartTime, booking.EndTime)
+		return notifyUser(tx, match.OrganizerID, "open_match_joined", "A player joined your match", message, "booking", &booking.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getOpenMatch(matchID)
+}
+
+// splitAmongCourtPlayers divide el precio de una reserva pendiente entre los jugadores de la cancha para que cada
+// uno pague su parte. Una vez iniciado algún pago la división ya no puede cambiarse.
+func splitAmongCourtPlayers(tx *gorm.DB, booking *models.Booking) error {
+	if booking.Status != "pending" || booking.SplitPlayers >= booking.Court.MaxPlayers {
+		return nil
+	}
+
+	var payments int64
+	if err := tx.Model(&models.Payment{}).Where("booking_id = ? AND status IN ?", booking.ID, []string{"pending", "approved"}).Count(&payments).Error; err != nil {
+		return errors.New("failed to fetch payments")
+	}
+	if payments > 0 {
+		return nil
+	}
+
+	if err := tx.Model(booking).Update("split_players", booking.Court.MaxPlayers).Error; err != nil {
+		return errors.New("failed to update booking")
+	}
+	booking.SplitPlayers = booking.Court.MaxPlayers
+	return nil
+}
+
+// reopenMatchSpot devuelve al partido abierto el lugar de un jugador que dejó la reserva
+func reopenMatchSpot(db *gorm.DB, bookingID uint) error {
+	return db.Model(&models.OpenMatch{}).
+		Where("booking_id = ? AND status IN ?", bookingID, []string{"open", "full"}).
+		Updates(map[string]interface{}{"free_spots": gorm.Expr("free_spots + 1"), "status": "open"}).Error
+}
+
+func (s *BookingService) getOpenMatch(id uint) (*models.OpenMatchResponse, error) {
+	var match models.OpenMatch
+	if err := s.db.Scopes(preloadOpenMatch).First(&match, id).Error; err != nil {
+		return nil, errors.New("failed to load open match")
+	}
+	return toOpenMatchResponse(&match), nil
+}
+
+func preloadOpenMatch(db *gorm.DB) *gorm.DB {
+	return db.Preload("Booking.Court.Club").
+		Preload("Booking.User").
+		Preload("Booking.Participants", activeParticipants).
+		Preload("Booking.Participants.User")
+}
+
+func toOpenMatchResponse(match *models.OpenMatch) *models.OpenMatchResponse {
+	booking := &match.Booking
+
+	// Cada jugador paga su parte: la división acordada o, si no hay, una parte por jugador de la cancha
+	players := booking.SplitPlayers
+	if players <= 1 {
+		players = booking.Court.MaxPlayers
+	}
+	shareAmount := booking.TotalPrice
+	if players > 1 {
+		shareAmount = roundPrice(booking.TotalPrice / float64(players))
+	}
+
+	return &models.OpenMatchResponse{
+		ID:          match.ID,
+		BookingID:   booking.ID,
+		Date:        booking.Date,
+		StartTime:   booking.StartTime,
+		EndTime:     booking.EndTime,
+		MinLevel:    match.MinLevel,
+		MaxLevel:    match.MaxLevel,
+		FreeSpots:   match.FreeSpots,
+		ShareAmount: shareAmount,
+		Status:      match.Status,
+		Description: match.Description,
+		Court: models.CourtInfo{
+			ID:           booking.Court.ID,
+			Name:         booking.Court.Name,
+			Address:      courtAddress(&booking.Court),
+			PricePerHour: booking.Court.PricePerHour,
+			Surface:      booking.Court.Surface,
+			HasLighting:  booking.Court.HasLighting,
+			IsIndoor:     booking.Court.IsIndoor,
+		},
+		Organizer: models.UserInfo{
+			ID:        booking.User.ID,
+			FirstName: booking.User.FirstName,
+			LastName:  booking.User.LastName,
+		},
+		Players: toParticipantInfos(booking.Participants),
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+func TestJoinerPaysTheirShareOfTheOpenMatch(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	organizer := testutil.CreateUser(t, db, "organizer@test.com", "user")
+	joiner := testutil.CreateUser(t, db, "joiner@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	paymentService := NewPaymentService(db, &fakeGateway{})
+	service := NewBookingService(db, paymentService)
+
+	booking := testutil.CreateBooking(t, db, court.ID, organizer.ID, testutil.Day(3), "18:00", "19:30", "pending", nil)
+
+	// Al publicar, el organizador tenía iniciado el pago del total, por lo que la reserva no se dividió
+	attempt := models.Payment{ID: "attempt", BookingID: booking.ID, UserID: organizer.ID, Amount: booking.TotalPrice, Provider: "fake", Status: "pending", PreferenceID: "pref-attempt"}
+	if err := db.Omit("Booking", "User").Create(&attempt).Error; err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+	match, err := service.PublishOpenMatch(booking.ID, organizer.ID, &models.PublishOpenMatchRequest{MinLevel: 1, MaxLevel: 7, FreeSpots: 1})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// El pago fue rechazado: quien se suma después paga su parte de la reserva dividida
+	db.Model(&attempt).Update("status", "rejected")
+	joined, err := service.JoinOpenMatch(match.ID, joiner.ID)
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	db.First(&booking, booking.ID)
+	if booking.SplitPlayers != court.MaxPlayers {
+		t.Fatalf("expected the booking split among %d players, got %d", court.MaxPlayers, booking.SplitPlayers)
+	}
+
+	share := roundPrice(booking.TotalPrice / float64(court.MaxPlayers))
+	if joined.ShareAmount != share {
+		t.Fatalf("expected a share of %.2f, got %.2f", share, joined.ShareAmount)
+	}
+	if _, err := paymentService.CreatePreference(joiner.ID, &models.CreatePreferenceRequest{BookingID: booking.ID, PayerEmail: joiner.Email}); err != nil {
+		t.Fatalf("expected the joiner to pay their share: %v", err)
+	}
+	var payment models.Payment
+	db.Where("booking_id = ? AND user_id = ?", booking.ID, joiner.ID).First(&payment)
+	if payment.Amount != share {
+		t.Fatalf("expected the joiner's payment to be %.2f, got %.2f", share, payment.Amount)
+	}
+}
+
+func TestInvitedPlayerJoinsFullOpenMatch(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	organizer := testutil.CreateUser(t, db, "organizer@test.com", "user")
+	invited := testutil.CreateUser(t, db, "invited@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	service := NewBookingService(db, NewPaymentService(db, &fakeGateway{}))
+
+	booking := testutil.CreateBooking(t, db, court.ID, organizer.ID, testutil.Day(3), "18:00", "19:30", "confirmed", nil)
+	invitation := models.BookingParticipant{BookingID: booking.ID, UserID: &invited.ID, Email: invited.Email, Status: "invited", InvitedBy: organizer.ID}
+	if err := db.Omit("Booking", "User").Create(&invitation).Error; err != nil {
+		t.Fatalf("create invitation: %v", err)
+	}
+	match, err := service.PublishOpenMatch(booking.ID, organizer.ID, &models.PublishOpenMatchRequest{MinLevel: 1, MaxLevel: 7, FreeSpots: 2})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Los lugares publicados se completan con otros jugadores
+	for _, email := range []string{"first@test.com", "second@test.com"} {
+		joiner := testutil.CreateUser(t, db, email, "user")
+		if _, err := service.JoinOpenMatch(match.ID, joiner.ID); err != nil {
+			t.Fatalf("join %s: %v", email, err)
+		}
+	}
+	late := testutil.CreateUser(t, db, "late@test.com", "user")
+	if _, err := service.JoinOpenMatch(match.ID, late.ID); err == nil || err.Error() != "match is full" {
+		t.Fatalf("expected the full match to reject new players, got %v", err)
+	}
+
+	// El invitado conserva su lugar aunque el partido esté lleno
+	if _, err := service.JoinOpenMatch(match.ID, invited.ID); err != nil {
+		t.Fatalf("expected the invited player to keep their place: %v", err)
+	}
+	db.First(&invitation, invitation.ID)
+	if invitation.Status != "accepted" {
+		t.Fatalf("expected the invitation accepted, got %s", invitation.Status)
+	}
+}
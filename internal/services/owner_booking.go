@@ -0,0 +1,428 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetOwnerBookings lista las reservas de las canchas del propietario con filtros por cancha, rango de fechas y estado
+func (s *BookingService) GetOwnerBookings(ownerID uint, filters *models.OwnerBookingsRequest) ([]*models.BookingResponse, error) {
+	query := s.db.Model(&models.Booking{}).
+		Joins("JOIN courts ON courts.id = bookings.court_id").
+		Where("courts.owner_id = ?", ownerID)
+
+	// Aplicar filtros
+	if filters.CourtID != nil {
+		query = query.Where("bookings.court_id = ?", *filters.CourtID)
+	}
+	if filters.StartDate != nil {
+		if _, err := time.Parse("2006-01-02", *filters.StartDate); err != nil {
+			return nil, errors.New("invalid date format")
+		}
+		query = query.Where("bookings.date >= ?", *filters.StartDate)
+	}
+	if filters.EndDate != nil {
+		if _, err := time.Parse("2006-01-02", *filters.EndDate); err != nil {
+			return nil, errors.New("invalid date format")
+		}
+		query = query.Where("bookings.date <= ?", *filters.EndDate)
+	}
+	if filters.Status != nil && *filters.Status != "" {
+		query = query.Where("bookings.status IN ?", strings.Split(*filters.Status, ","))
+	}
+
+	var bookings []models.Booking
+	if err := query.Preload("Court.Club").Preload("User").Order("bookings.date, bookings.start_time").Find(&bookings).Error; err != nil {
+		return nil, errors.New("failed to fetch bookings")
+	}
+
+	// Convertir a respuesta
+	responses := make([]*models.BookingResponse, 0, len(bookings))
+	for i := range bookings {
+		responses = append(responses, s.toBookingResponse(&bookings[i]))
+	}
+
+	return responses, nil
+}
+
+// GetCourtDaySchedule arma la grilla del día de una cancha del propietario: las franjas de apertura divididas
+// en el intervalo de inicio de la cancha, indicando qué reserva ocupa cada casillero
+func (s *BookingService) GetCourtDaySchedule(courtID uint, ownerID uint, date time.Time) (*models.CourtDayScheduleResponse, error) {
+	var court models.Court
+	if err := s.db.Where("id = ? AND owner_id = ?", courtID, ownerID).Preload("Club").First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+
+	schedule, err := resolveDaySchedule(s.db, court.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Las reservas canceladas o vencidas no ocupan la grilla
+	var bookings []models.Booking
+	if err := s.db.Where("court_id = ? AND date = ? AND status NOT IN ?", court.ID, date.Format("2006-01-02"), []string{"cancelled", "expired"}).
+		Preload("Court.Club").Preload("User").Order("start_time").Find(&bookings).Error; err != nil {
+		return nil, errors.New("failed to fetch bookings")
+	}
+
+	step := courtSlotConfig(&court).StepMinutes
+	response := &models.CourtDayScheduleResponse{
+		CourtID:     court.ID,
+		Date:        date.Format("2006-01-02"),
+		IsClosed:    schedule.IsClosed,
+		Reason:      schedule.Reason,
+		StepMinutes: step,
+		Slots:       make([]models.ScheduleSlot, 0),
+		Bookings:    make([]*models.BookingResponse, 0, len(bookings)),
+	}
+	for i := range bookings {
+		response.Bookings = append(response.Bookings, s.toBookingResponse(&bookings[i]))
+	}
+	if schedule.IsClosed {
+		return response, nil
+	}
+	response.Windows = schedule.openingWindows()
+
+	for _, window := range schedule.Windows {
+		for start := window.Open; start < window.Close; start += step {
+			end := start + step
+			if end > window.Close {
+				end = window.Close
+			}
+
+			slot := models.ScheduleSlot{
+				StartTime: formatClock(start),
+				EndTime:   formatClock(end),
+				Available: true,
+			}
+			for i := range bookings {
+				bookingStart, bookingEnd, err := schedule.toOperational(bookings[i].StartTime, bookings[i].EndTime)
+				if err != nil || !rangesOverlap(start, end, bookingStart, bookingEnd, 0) {
+					continue
+				}
+				bookingID := bookings[i].ID
+				slot.Available = false
+				slot.BookingID = &bookingID
+				slot.BookingStatus = bookings[i].Status
+				slot.CustomerName = bookingCustomerName(&bookings[i])
+				break
+			}
+			response.Slots = append(response.Slots, slot)
+		}
+	}
+
+	return response, nil
+}
+
+// OwnerConfirmBooking confirma manualmente una reserva pendiente de una cancha del propietario (por ejemplo, pagada en el club)
+func (s *BookingService) OwnerConfirmBooking(id uint, ownerID uint) (*models.BookingResponse, error) {
+	booking, err := s.findOwnerBooking(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Si el bloqueo de pago venció, otra reserva pudo haber tomado el turno mientras tanto
+		held, err := lockSlotForConfirmation(tx, booking)
+		if err != nil {
+			return err
+		}
+		if booking.Status == "pending" && !held {
+			return errors.New("time slot not available")
+		}
+		return transitionBooking(tx, booking, "confirmed", &ownerID, "confirmed by owner")
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidBookingTransition) {
+			return nil, errors.New("booking cannot be confirmed in its current status")
+		}
+		return nil, err
+	}
+
+	return s.toBookingResponse(booking), nil
+}
+
+// OwnerCancelBooking cancela una reserva de una cancha del propietario. Al cancelar el club se reembolsa
+// el pago completo, sin aplicar la política de cancelación pensada para los jugadores.
+func (s *BookingService) OwnerCancelBooking(id uint, ownerID uint, reason string) (*models.CancellationResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("cancellation reason is required")
+	}
+
+	booking, err := s.findOwnerBooking(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := transitionBooking(s.db, booking, "cancelled", &ownerID, reason); err != nil {
+		if errors.Is(err, ErrInvalidBookingTransition) {
+			return nil, errors.New("booking cannot be cancelled in its current status")
+		}
+		return nil, errors.New("failed to cancel booking")
+	}
+
+	return s.refundCancelledBooking(booking, 100, "booking cancelled by club: "+reason), nil
+}
+
+// MarkNoShow marca como no_show una reserva confirmada cuyo turno ya comenzó. Si la política de cancelación
+// de la cancha lo prevé, se reembolsa el porcentaje configurado para las ausencias.
+func (s *BookingService) MarkNoShow(id uint, ownerID uint, reason string) (*models.CancellationResponse, error) {
+	booking, err := s.findOwnerBooking(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := resolveDaySchedule(s.db, booking.CourtID, booking.Date)
+	if err != nil {
+		return nil, err
+	}
+	startsAt, err := bookingStartsAt(schedule, booking)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(startsAt) {
+		return nil, errors.New("booking has not started yet")
+	}
+
+	if reason = strings.TrimSpace(reason); reason == "" {
+		reason = "player did not show up"
+	}
+	if err := transitionBooking(s.db, booking, "no_show", &ownerID, reason); err != nil {
+		if errors.Is(err, ErrInvalidBookingTransition) {
+			return nil, errors.New("booking cannot be marked as no-show in its current status")
+		}
+		return nil, err
+	}
+
+	policy := courtCancellationPolicy(&booking.Court)
+	return s.refundCancelledBooking(booking, policy.NoShowRefundPercent, "no-show refund"), nil
+}
+
+// OwnerCreateBooking carga una reserva telefónica o presencial en una cancha del propietario, a nombre de un usuario
+// registrado o de un invitado. La reserva queda confirmada y ocupa el turno como cualquier otra; si se informa
+// el medio de pago se registra el cobro manual en lugar de una preferencia de MercadoPago.
+func (s *BookingService) OwnerCreateBooking(ownerID uint, req *models.OwnerCreateBookingRequest) (*models.BookingResponse, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format")
+	}
+
+	// La reserva es de un usuario registrado o de un invitado identificado por nombre y teléfono
+	guestName := strings.TrimSpace(req.GuestName)
+	guestPhone := strings.TrimSpace(req.GuestPhone)
+	if req.UserID == nil && (guestName == "" || guestPhone == "") {
+		return nil, errors.New("user_id or guest name and phone are required")
+	}
+	if req.UserID != nil {
+		var user models.User
+		if err := s.db.Where("id = ? AND is_active = ?", *req.UserID, true).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("user not found")
+			}
+			return nil, errors.New("failed to fetch user")
+		}
+		guestName, guestPhone = "", ""
+	}
+
+	source := req.Source
+	if source == "" {
+		source = "phone"
+	}
+	if source != "phone" && source != "walk_in" {
+		return nil, errors.New("invalid booking source")
+	}
+	if req.PaymentMethod != "" && req.PaymentMethod != "cash" && req.PaymentMethod != "transfer" {
+		return nil, errors.New("invalid payment method")
+	}
+
+	var booking models.Booking
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		court, priceBreakdown, err := s.reserveSlot(tx, req.CourtID, date, req.StartTime, req.EndTime, false, nil)
+		if err != nil {
+			return err
+		}
+		if court.OwnerID != ownerID {
+			return errors.New("court not found")
+		}
+
+		booking = models.Booking{
+			CourtID:        court.ID,
+			UserID:         req.UserID,
+			Date:           date,
+			StartTime:      req.StartTime,
+			EndTime:        req.EndTime,
+			Status:         "confirmed",
+			Source:         source,
+			GuestName:      guestName,
+			GuestPhone:     guestPhone,
+			CreatedBy:      &ownerID,
+			TotalPrice:     priceBreakdown.Total,
+			PriceBreakdown: priceBreakdown,
+			Notes:          req.Notes,
+		}
+		if err := tx.Create(&booking).Error; err != nil {
+			return errors.New("failed to create booking")
+		}
+
+		history := models.BookingStatusHistory{
+			BookingID: booking.ID,
+			ToStatus:  "confirmed",
+			ActorID:   &ownerID,
+			Reason:    "booking created by owner (" + source + ")",
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return errors.New("failed to record booking status history")
+		}
+
+		if req.PaymentMethod != "" {
+			payerID := ownerID
+			if req.UserID != nil {
+				payerID = *req.UserID
+			}
+			if _, err := recordManualPayment(tx, &booking, payerID, req.PaymentMethod, booking.TotalPrice); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Cargar relaciones
+	if err := s.db.Preload("Court.Club").Preload("User").First(&booking, booking.ID).Error; err != nil {
+		return nil, errors.New("failed to load booking with relations")
+	}
+
+	return s.toBookingResponse(&booking), nil
+}
+
+// OwnerRecordPayment registra el cobro manual (efectivo o transferencia) de una reserva de una cancha del propietario.
+// Una reserva pendiente queda confirmada al registrarse el pago.
+func (s *BookingService) OwnerRecordPayment(id uint, ownerID uint, req *models.ManualPaymentRequest) (*models.Payment, error) {
+	booking, err := s.findOwnerBooking(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != "pending" && booking.Status != "confirmed" {
+		return nil, errors.New("booking cannot be paid in its current status")
+	}
+
+	var payment *models.Payment
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Una reserva pendiente se confirma al cobrarla: su turno debe seguir libre si el bloqueo de pago venció
+		if booking.Status == "pending" {
+			held, err := lockSlotForConfirmation(tx, booking)
+			if err != nil {
+				return err
+			}
+			if booking.Status == "pending" && !held {
+				return errors.New("time slot not available")
+			}
+		}
+
+		// En una reserva dividida el club cobra el saldo que los jugadores no pagaron online
+		paid, err := bookingPaidAmount(tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		balance := roundPrice(booking.TotalPrice - paid)
+		if balance <= 0 {
+			return errors.New("booking already paid")
+		}
+
+		payerID := ownerID
+		if booking.UserID != nil {
+			payerID = *booking.UserID
+		}
+		payment, err = recordManualPayment(tx, booking, payerID, req.PaymentMethod, balance)
+		if err != nil {
+			return err
+		}
+
+		if booking.Status == "pending" {
+			return transitionBooking(tx, booking, "confirmed", &ownerID, "paid at the club ("+req.PaymentMethod+")")
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidBookingTransition) {
+			return nil, errors.New("booking cannot be paid in its current status")
+		}
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// reserveEventSlot reserva un turno para un evento del propio club (torneos), sin cliente ni cobro.
+// Respeta las reglas de la cancha y las reservas existentes; se ejecuta dentro de la transacción del evento.
+func (s *BookingService) reserveEventSlot(tx *gorm.DB, ownerID uint, courtID uint, date time.Time, startTime, endTime, source, notes string) (*models.Booking, error) {
+	court, _, err := s.reserveSlot(tx, courtID, date, startTime, endTime, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if court.OwnerID != ownerID {
+		return nil, errors.New("court not found")
+	}
+
+	booking := models.Booking{
+		CourtID:   court.ID,
+		Date:      date,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Status:    "confirmed",
+		Source:    source,
+		CreatedBy: &ownerID,
+		Notes:     notes,
+	}
+	if err := tx.Create(&booking).Error; err != nil {
+		return nil, errors.New("failed to create booking")
+	}
+
+	history := models.BookingStatusHistory{
+		BookingID: booking.ID,
+		ToStatus:  "confirmed",
+		ActorID:   &ownerID,
+		Reason:    "court reserved for " + source,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return nil, errors.New("failed to record booking status history")
+	}
+
+	return &booking, nil
+}
+
+// findOwnerBooking obtiene una reserva verificando que la cancha pertenezca al propietario
+func (s *BookingService) findOwnerBooking(id uint, ownerID uint) (*models.Booking, error) {
+	var booking models.Booking
+	err := s.db.Joins("JOIN courts ON courts.id = bookings.court_id").
+		Where("bookings.id = ? AND courts.owner_id = ?", id, ownerID).
+		Preload("Court.Club").Preload("User").
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+	return &booking, nil
+}
+
+// bookingCustomerName devuelve el nombre de quien reservó: el usuario registrado o el invitado cargado por el club
+func bookingCustomerName(booking *models.Booking) string {
+	if booking.UserID == nil {
+		return booking.GuestName
+	}
+	return strings.TrimSpace(booking.User.FirstName + " " + booking.User.LastName)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+func TestOwnerCannotConfirmLapsedBookingWhoseSlotWasTaken(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	first := testutil.CreateUser(t, db, "first@test.com", "user")
+	second := testutil.CreateUser(t, db, "second@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	date := testutil.Day(3)
+	service := NewBookingService(db, NewPaymentService(db, &fakeGateway{}))
+
+	lapsed := time.Now().Add(-time.Minute)
+	late := testutil.CreateBooking(t, db, court.ID, first.ID, date, "18:00", "19:30", "pending", &lapsed)
+	testutil.CreateBooking(t, db, court.ID, second.ID, date, "19:00", "20:00", "confirmed", nil)
+
+	if _, err := service.OwnerConfirmBooking(late.ID, owner.ID); err == nil || err.Error() != "time slot not available" {
+		t.Fatalf("expected the taken slot to block the confirmation, got %v", err)
+	}
+	if _, err := service.OwnerRecordPayment(late.ID, owner.ID, &models.ManualPaymentRequest{PaymentMethod: "cash"}); err == nil || err.Error() != "time slot not available" {
+		t.Fatalf("expected the taken slot to block the payment, got %v", err)
+	}
+
+	var payments int64
+	db.Model(&models.Payment{}).Where("booking_id = ?", late.ID).Count(&payments)
+	db.First(&late, late.ID)
+	if late.Status != "pending" || payments != 0 {
+		t.Fatalf("expected the booking to stay pending without payments, got %s with %d payments", late.Status, payments)
+	}
+}
+
+func TestOwnerConfirmsLapsedBookingWhileSlotIsFree(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	service := NewBookingService(db, NewPaymentService(db, &fakeGateway{}))
+
+	lapsed := time.Now().Add(-time.Minute)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, testutil.Day(3), "18:00", "19:30", "pending", &lapsed)
+
+	if _, err := service.OwnerRecordPayment(booking.ID, owner.ID, &models.ManualPaymentRequest{PaymentMethod: "cash"}); err != nil {
+		t.Fatalf("record payment: %v", err)
+	}
+	db.First(&booking, booking.ID)
+	if booking.Status != "confirmed" {
+		t.Fatalf("expected the paid booking to be confirmed, got %s", booking.Status)
+	}
+}
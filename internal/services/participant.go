@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InviteParticipant invita a un jugador a la reserva del organizador, por usuario o por email.
+// Entre el organizador y los invitados no se puede superar la cantidad máxima de jugadores de la cancha.
+func (s *BookingService) InviteParticipant(bookingID uint, organizerID uint, req *models.InviteParticipantRequest) (*models.BookingParticipant, error) {
+	if req.UserID == nil && req.Email == "" {
+		return nil, errors.New("user_id or email is required")
+	}
+
+	var participant models.BookingParticipant
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Bloquear la reserva para que dos invitaciones simultáneas no superen el cupo
+		var booking models.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND user_id = ?", bookingID, organizerID).Preload("Court").Preload("User").First(&booking).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("booking not found")
+			}
+			return errors.New("failed to fetch booking")
+		}
+		if booking.Status != "pending" && booking.Status != "confirmed" {
+			return errors.New("booking cannot receive participants in its current status")
+		}
+
+		// Resolver el invitado: un usuario registrado o un email que se vincula al registrarse
+		var invitee models.User
+		email := req.Email
+		if req.UserID != nil {
+			if err := tx.Where("id = ? AND is_active = ?", *req.UserID, true).First(&invitee).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("user not found")
+				}
+				return errors.New("failed to fetch user")
+			}
+			email = invitee.Email
+		} else if err := tx.Where("email = ?", email).First(&invitee).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to fetch user")
+		}
+
+		if invitee.ID == organizerID || email == booking.User.Email {
+			return errors.New("cannot invite the organizer")
+		}
+
+		var active int64
+		if err := tx.Model(&models.BookingParticipant{}).Scopes(activeParticipants).Where("booking_id = ?", booking.ID).Count(&active).Error; err != nil {
+			return errors.New("failed to fetch participants")
+		}
+
+		// Una invitación rechazada o dada de baja se reactiva en lugar de duplicarse
+		err := tx.Where("booking_id = ? AND email = ?", booking.ID, email).First(&participant).Error
+		if err == nil && (participant.Status == "invited" || participant.Status == "accepted") {
+			return errors.New("player already invited")
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to fetch participants")
+		}
+
+		// El organizador ocupa uno de los lugares
+		if int(active)+1 >= booking.Court.MaxPlayers {
+			return errors.New("booking is full")
+		}
+
+		participant.BookingID = booking.ID
+		participant.Email = email
+		participant.Status = "invited"
+		participant.InvitedBy = organizerID
+		participant.RespondedAt = nil
+		if invitee.ID != 0 {
+			participant.UserID = &invitee.ID
+		}
+		if err := tx.Save(&participant).Error; err != nil {
+			return errors.New("failed to invite player")
+		}
+
+		if participant.UserID != nil {
+			message := fmt.Sprintf("You have been invited to play at %s on %s from %s to %s.",
+				booking.Court.Name, booking.Date.Format("2006-01-02"), booking.StartTime, booking.EndTime)
+			if err := notifyUser(tx, *participant.UserID, "booking_invitation", "New booking invitation", message, "participant", &participant.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+// GetBookingParticipants lista los jugadores de una reserva visible para el usuario
+func (s *BookingService) GetBookingParticipants(bookingID uint, userID uint) ([]models.ParticipantInfo, error) {
+	var booking models.Booking
+	err := s.db.Where("id = ?", bookingID).Scopes(visibleBookings(userID)).
+		Preload("Participants", activeParticipants).Preload("Participants.User").
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	return toParticipantInfos(booking.Participants), nil
+}
+
+// RemoveParticipant da de baja a un jugador de la reserva. Puede hacerlo el organizador o el propio jugador.
+func (s *BookingService) RemoveParticipant(bookingID uint, participantID uint, userID uint) error {
+	var participant models.BookingParticipant
+	if err := s.db.Where("id = ? AND booking_id = ?", participantID, bookingID).Preload("Booking").First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("participant not found")
+		}
+		return errors.New("failed to fetch participant")
+	}
+
+	isOrganizer := participant.Booking.UserID != nil && *participant.Booking.UserID == userID
+	isSelf := participant.UserID != nil && *participant.UserID == userID
+	if !isOrganizer && !isSelf {
+		return errors.New("participant not found")
+	}
+	if participant.Status != "invited" && participant.Status != "accepted" {
+		return errors.New("participant already removed")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&participant).Update("status", "removed").Error; err != nil {
+			return errors.New("failed to remove participant")
+		}
+		// Si la reserva está publicada como partido abierto, el lugar vuelve a ofrecerse
+		if participant.Status == "accepted" {
+			if err := reopenMatchSpot(tx, participant.BookingID); err != nil {
+				return errors.New("failed to update open match")
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// GetUserInvitations lista las invitaciones pendientes de respuesta del usuario para reservas activas
+func (s *BookingService) GetUserInvitations(userID uint) ([]models.BookingParticipant, error) {
+	var invitations []models.BookingParticipant
+	err := s.db.Joins("JOIN bookings ON bookings.id = booking_participants.booking_id").
+		Where("booking_participants.user_id = ? AND booking_participants.status = ?", userID, "invited").
+		Where("bookings.status IN ? AND bookings.date >= ?", []string{"pending", "confirmed"}, time.Now().Format("2006-01-02")).
+		Preload("Booking.Court").
+		Order("bookings.date, bookings.start_time").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch invitations")
+	}
+	return invitations, nil
+}
+
+// RespondInvitation acepta o rechaza una invitación y avisa al organizador
+func (s *BookingService) RespondInvitation(participantID uint, userID uint, accept bool) (*models.BookingParticipant, error) {
+	var participant models.BookingParticipant
+	if err := s.db.Where("id = ? AND user_id = ?", participantID, userID).Preload("Booking.Court").First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, errors.New("failed to fetch invitation")
+	}
+
+	if participant.Status != "invited" {
+		return nil, errors.New("invitation already answered")
+	}
+	booking := participant.Booking
+	if booking.Status != "pending" && booking.Status != "confirmed" {
+		return nil, errors.New("booking is no longer active")
+	}
+
+	status := "declined"
+	if accept {
+		status = "accepted"
+	}
+
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.BookingParticipant{}).Where("id = ? AND status = ?", participant.ID, "invited").
+			Updates(map[string]interface{}{"status": status, "responded_at": now})
+		if result.Error != nil {
+			return errors.New("failed to update invitation")
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invitation already answered")
+		}
+
+		if booking.UserID != nil {
+			message := fmt.Sprintf("%s %s your invitation to play at %s on %s from %s to %s.",
+				participant.Email, status, booking.Court.Name, booking.Date.Format("2006-01-02"), booking.StartTime, booking.EndTime)
+			if err := notifyUser(tx, *booking.UserID, "booking_invitation_"+status, "Invitation "+status, message, "booking", &booking.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	participant.Status = status
+	participant.RespondedAt = &now
+	return &participant, nil
+}
+
+// visibleBookings filtra las reservas que el usuario organiza o a las que fue invitado como participante
+func visibleBookings(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		participations := db.Session(&gorm.Session{NewDB: true}).Model(&models.BookingParticipant{}).
+			Select("booking_id").
+			Where("user_id = ? AND status IN ?", userID, []string{"invited", "accepted"})
+		return db.Where("(bookings.user_id = ? OR bookings.id IN (?))", userID, participations)
+	}
+}
+
+// activeParticipants filtra los participantes que ocupan un lugar: invitados pendientes de respuesta y aceptados
+func activeParticipants(db *gorm.DB) *gorm.DB {
+	return db.Where("status IN ?", []string{"invited", "accepted"})
+}
+
+func toParticipantInfos(participants []models.BookingParticipant) []models.ParticipantInfo {
+	if len(participants) == 0 {
+		return nil
+	}
+
+	infos := make([]models.ParticipantInfo, 0, len(participants))
+	for _, participant := range participants {
+		info := models.ParticipantInfo{
+			ID:     participant.ID,
+			UserID: participant.UserID,
+			Email:  participant.Email,
+			Status: participant.Status,
+		}
+		if participant.User != nil {
+			info.FirstName = participant.User.FirstName
+			info.LastName = participant.User.LastName
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// linkParticipantInvitations vincula al nuevo usuario las invitaciones que recibió por email antes de tener cuenta
+func linkParticipantInvitations(db *gorm.DB, user *models.User) {
+	if err := db.Model(&models.BookingParticipant{}).Where("email = ? AND user_id IS NULL", user.Email).Update("user_id", user.ID).Error; err != nil {
+		log.Printf("failed to link invitations for user %d: %v", user.ID, err)
+	}
+}
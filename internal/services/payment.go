@@ -1,224 +1,702 @@
-package services
-
-import (
-	"errors"
-	"fmt"
-	"time"
-
-	"backend-padel-go/internal/config"
-	"backend-padel-go/internal/models"
-
-	"github.com/google/uuid"
-	"github.com/mercadopago/sdk-go"
-	"gorm.io/gorm"
-)
-
-type PaymentService struct {
-	db *gorm.DB
-}
-
-func NewPaymentService(db *gorm.DB) *PaymentService {
-	return &PaymentService{db: db}
-}
-
-func (s *PaymentService) CreatePreference(userID uint, req *models.CreatePreferenceRequest) (*models.PreferenceResponse, error) {
-	// Verificar que la reserva existe y pertenece al usuario
-	var booking models.Booking
-	if err := s.db.Where("id = ? AND user_id = ?", req.BookingID, userID).Preload("Court").First(&booking).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("booking not found")
-		}
-		return nil, errors.New("failed to fetch booking")
-	}
-
-	// Verificar que la reserva no tenga ya un pago
-	var existingPayment models.Payment
-	if err := s.db.Where("booking_id = ?", req.BookingID).First(&existingPayment).Error; err == nil {
-		return nil, errors.New("payment already exists for this booking")
-	}
-
-	// Crear pago en la base de datos
-	paymentID := uuid.New().String()
-	payment := models.Payment{
-		ID:           paymentID,
-		BookingID:    req.BookingID,
-		UserID:       userID,
-		Amount:       booking.TotalPrice,
-		Currency:     "ARS",
-		Status:       "pending",
-		PayerEmail:   req.PayerEmail,
-	}
-
-	if err := s.db.Create(&payment).Error; err != nil {
-		return nil, errors.New("failed to create payment")
-	}
-
-	// Crear preferencia en MercadoPago
-	cfg := config.Load()
-	client := mercadopago.NewClient(cfg.MercadoPago.AccessToken)
-
-	preferenceRequest := mercadopago.PreferenceRequest{
-		Items: []mercadopago.PreferenceItem{
-			{
-				Title:       fmt.Sprintf("Reserva - %s", booking.Court.Name),
-				Description: fmt.Sprintf("Reserva para %s el %s de %s a %s", booking.Court.Name, booking.Date.Format("2006-01-02"), booking.StartTime, booking.EndTime),
-				Quantity:    1,
-				UnitPrice:   booking.TotalPrice,
-			},
-		},
-		Payer: &mercadopago.PreferencePayer{
-			Email: req.PayerEmail,
-		},
-		BackUrls: &mercadopago.PreferenceBackUrls{
-			Success: fmt.Sprintf("%s/payment/success?payment_id=%s", cfg.MercadoPago.FrontendURL, paymentID),
-			Failure: fmt.Sprintf("%s/payment/failure?payment_id=%s", cfg.MercadoPago.FrontendURL, paymentID),
-			Pending: fmt.Sprintf("%s/payment/pending?payment_id=%s", cfg.MercadoPago.FrontendURL, paymentID),
-		},
-		AutoReturn: "approved",
-		ExternalReference: paymentID,
-		NotificationURL:   fmt.Sprintf("%s/api/v1/payments/webhook", cfg.MercadoPago.BackendURL),
-	}
-
-	preference, err := client.CreatePreference(preferenceRequest)
-	if err != nil {
-		// Eliminar pago de la base de datos si falla la creación de preferencia
-		s.db.Delete(&payment)
-		return nil, errors.New("failed to create MercadoPago preference")
-	}
-
-	// Actualizar pago con preference ID
-	payment.PreferenceID = preference.ID
-	if err := s.db.Save(&payment).Error; err != nil {
-		return nil, errors.New("failed to update payment with preference ID")
-	}
-
-	return &models.PreferenceResponse{
-		ID:        preference.ID,
-		InitPoint: preference.InitPoint,
-	}, nil
-}
-
-func (s *PaymentService) GetPaymentStatus(userID uint, paymentID string) (*models.PaymentStatusResponse, error) {
-	var payment models.Payment
-	if err := s.db.Where("id = ? AND user_id = ?", paymentID, userID).First(&payment).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
-		}
-		return nil, errors.New("failed to fetch payment")
-	}
-
-	return &models.PaymentStatusResponse{
-		ID:            payment.ID,
-		Status:        payment.Status,
-		Amount:        payment.Amount,
-		Currency:      payment.Currency,
-		PaymentMethod: payment.PaymentMethod,
-		PayerEmail:    payment.PayerEmail,
-		CreatedAt:     payment.CreatedAt,
-		UpdatedAt:     payment.UpdatedAt,
-	}, nil
-}
-
-func (s *PaymentService) HandleWebhook(action string, data map[string]interface{}) error {
-	paymentID, ok := data["id"].(string)
-	if !ok {
-		return errors.New("invalid payment ID in webhook data")
-	}
-
-	// Obtener información del pago desde MercadoPago
-	cfg := config.Load()
-	client := mercadopago.NewClient(cfg.MercadoPago.AccessToken)
-
-	paymentInfo, err := client.GetPayment(paymentID)
-	if err != nil {
-		return errors.New("failed to get payment info from MercadoPago")
-	}
-
-	// Buscar pago en la base de datos por preference ID
-	var payment models.Payment
-	if err := s.db.Where("preference_id = ?", paymentInfo.PreferenceID).First(&payment).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("payment not found")
-		}
-		return errors.New("failed to fetch payment")
-	}
-
-	// Actualizar estado del pago
-	payment.MercadoPagoID = paymentID
-	payment.Status = s.mapMercadoPagoStatus(paymentInfo.Status)
-	payment.PaymentMethod = paymentInfo.PaymentMethodID
-
-	if err := s.db.Save(&payment).Error; err != nil {
-		return errors.New("failed to update payment status")
-	}
-
-	// Si el pago fue aprobado, actualizar estado de la reserva
-	if payment.Status == "approved" {
-		if err := s.db.Model(&models.Booking{}).Where("id = ?", payment.BookingID).Update("status", "confirmed").Error; err != nil {
-			return errors.New("failed to update booking status")
-		}
-	}
-
-	return nil
-}
-
-func (s *PaymentService) mapMercadoPagoStatus(status string) string {
-	switch status {
-	case "approved":
-		return "approved"
-	case "pending":
-		return "pending"
-	case "rejected":
-		return "rejected"
-	case "cancelled":
-		return "cancelled"
-	default:
-		return "pending"
-	}
-}
-
-func (s *PaymentService) GetPaymentStatistics() (*models.PaymentStatistics, error) {
-	var totalPayments int64
-	var approvedPayments int64
-	var pendingPayments int64
-	var rejectedPayments int64
-	var totalRevenue float64
-
-	// Contar total de pagos
-	if err := s.db.Model(&models.Payment{}).Count(&totalPayments).Error; err != nil {
-		return nil, errors.New("failed to count total payments")
-	}
-
-	// Contar pagos por estado
-	if err := s.db.Model(&models.Payment{}).Where("status = ?", "approved").Count(&approvedPayments).Error; err != nil {
-		return nil, errors.New("failed to count approved payments")
-	}
-
-	if err := s.db.Model(&models.Payment{}).Where("status = ?", "pending").Count(&pendingPayments).Error; err != nil {
-		return nil, errors.New("failed to count pending payments")
-	}
-
-	if err := s.db.Model(&models.Payment{}).Where("status = ?", "rejected").Count(&rejectedPayments).Error; err != nil {
-		return nil, errors.New("failed to count rejected payments")
-	}
-
-	// Calcular ingresos totales
-	if err := s.db.Model(&models.Payment{}).Where("status = ?", "approved").Select("COALESCE(SUM(amount), 0)").Scan(&totalRevenue).Error; err != nil {
-		return nil, errors.New("failed to calculate total revenue")
-	}
-
-	// Calcular promedio
-	var averageAmount float64
-	if totalPayments > 0 {
-		averageAmount = totalRevenue / float64(approvedPayments)
-	}
-
-	return &models.PaymentStatistics{
-		TotalPayments:    int(totalPayments),
-		ApprovedPayments: int(approvedPayments),
-		PendingPayments:  int(pendingPayments),
-		RejectedPayments: int(rejectedPayments),
-		TotalRevenue:     totalRevenue,
-		AverageAmount:    averageAmount,
-	}, nil
-}
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentService struct {
+	db      *gorm.DB
+	gateway PaymentGateway
+}
+
+func NewPaymentService(db *gorm.DB, gateway PaymentGateway) *PaymentService {
+	return &PaymentService{db: db, gateway: gateway}
+}
+
+func (s *PaymentService) CreatePreference(userID uint, req *models.CreatePreferenceRequest) (*models.PreferenceResponse, error) {
+	// Verificar que la reserva existe y pertenece al usuario o lo tiene como participante
+	var booking models.Booking
+	if err := s.db.Where("id = ?", req.BookingID).Scopes(visibleBookings(userID)).Preload("Court").First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	// Los participantes solo pagan su parte de una reserva dividida; el pago único lo hace el organizador
+	isOrganizer := booking.UserID != nil && *booking.UserID == userID
+	if !isOrganizer && (booking.SplitPlayers <= 1 || req.PayRemainder) {
+		return nil, errors.New("only the organizer can pay this booking")
+	}
+
+	// Las reservas divididas admiten un pago por cada parte; las demás, un único pago por el total
+	amount := booking.TotalPrice
+	if booking.SplitPlayers > 1 {
+		var err error
+		amount, err = s.nextShareAmount(&booking, req)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var existingPayment models.Payment
+		if err := s.db.Where("booking_id = ?", req.BookingID).First(&existingPayment).Error; err == nil {
+			return nil, errors.New("payment already exists for this booking")
+		}
+	}
+
+	// Crear pago en la base de datos
+	paymentID := uuid.New().String()
+	payment := models.Payment{
+		ID:           paymentID,
+		BookingID:    req.BookingID,
+		UserID:       userID,
+		Amount:       amount,
+		Currency:     "ARS",
+		Provider:     s.gateway.Name(),
+		Status:       "pending",
+		PayerEmail:   req.PayerEmail,
+	}
+
+	if err := s.db.Create(&payment).Error; err != nil {
+		return nil, errors.New("failed to create payment")
+	}
+
+	// Crear preferencia en el proveedor de pagos
+	cfg := config.Load()
+	preference, err := s.gateway.CreatePreference(&GatewayPreferenceRequest{
+		Title:             fmt.Sprintf("Reserva - %s", booking.Court.Name),
+		Description:       fmt.Sprintf("Reserva para %s el %s de %s a %s", booking.Court.Name, booking.Date.Format("2006-01-02"), booking.StartTime, booking.EndTime),
+		Amount:            amount,
+		Currency:          payment.Currency,
+		PayerEmail:        req.PayerEmail,
+		ExternalReference: paymentID,
+		SuccessURL:        fmt.Sprintf("%s/payment/success?payment_id=%s", cfg.MercadoPago.FrontendURL, paymentID),
+		FailureURL:        fmt.Sprintf("%s/payment/failure?payment_id=%s", cfg.MercadoPago.FrontendURL, paymentID),
+		PendingURL:        fmt.Sprintf("%s/payment/pending?payment_id=%s", cfg.MercadoPago.FrontendURL, paymentID),
+		NotificationURL:   fmt.Sprintf("%s/api/v1/payments/webhook", cfg.MercadoPago.BackendURL),
+	})
+	if err != nil {
+		// Eliminar pago de la base de datos si falla la creación de preferencia
+		s.db.Delete(&payment)
+		return nil, fmt.Errorf("failed to create %s preference", s.gateway.Name())
+	}
+
+	// Actualizar pago con preference ID
+	payment.PreferenceID = preference.ID
+	if err := s.db.Save(&payment).Error; err != nil {
+		return nil, errors.New("failed to update payment with preference ID")
+	}
+
+	return &models.PreferenceResponse{
+		ID:        preference.ID,
+		InitPoint: preference.InitPoint,
+	}, nil
+}
+
+// nextShareAmount calcula el monto de la próxima parte de una reserva dividida. La última parte absorbe el redondeo
+// y con PayRemainder el organizador paga todo el saldo que todavía no tiene preferencia.
+func (s *PaymentService) nextShareAmount(booking *models.Booking, req *models.CreatePreferenceRequest) (float64, error) {
+	if booking.Status != "pending" {
+		return 0, errors.New("booking is not awaiting payment")
+	}
+
+	var payments []models.Payment
+	if err := s.db.Where("booking_id = ? AND status IN ?", booking.ID, []string{"pending", "approved"}).Find(&payments).Error; err != nil {
+		return 0, errors.New("failed to fetch payments")
+	}
+
+	var assigned float64
+	for _, payment := range payments {
+		if payment.Status == "pending" && payment.PayerEmail == req.PayerEmail {
+			return 0, errors.New("payer already has a pending payment for this booking")
+		}
+		assigned += payment.Amount
+	}
+
+	unassigned := roundPrice(booking.TotalPrice - assigned)
+	if unassigned <= 0 {
+		return 0, errors.New("all shares of this booking are already assigned")
+	}
+	if req.PayRemainder {
+		return unassigned, nil
+	}
+
+	share := roundPrice(booking.TotalPrice / float64(booking.SplitPlayers))
+	if len(payments) >= booking.SplitPlayers-1 || share > unassigned {
+		return unassigned, nil
+	}
+	return share, nil
+}
+
+// GetBookingPayments resume los pagos de una reserva del usuario o en la que participa: lo pagado, lo pendiente y el saldo sin asignar
+func (s *PaymentService) GetBookingPayments(bookingID uint, userID uint) (*models.BookingPaymentSummary, error) {
+	var booking models.Booking
+	if err := s.db.Where("id = ?", bookingID).Scopes(visibleBookings(userID)).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	var payments []models.Payment
+	if err := s.db.Where("booking_id = ?", booking.ID).Order("created_at").Find(&payments).Error; err != nil {
+		return nil, errors.New("failed to fetch payments")
+	}
+
+	summary := &models.BookingPaymentSummary{
+		BookingID:     booking.ID,
+		Status:        booking.Status,
+		TotalPrice:    booking.TotalPrice,
+		SplitPlayers:  booking.SplitPlayers,
+		ShareAmount:   booking.TotalPrice,
+		HoldExpiresAt: booking.HoldExpiresAt,
+		Payments:      make([]models.PaymentStatusResponse, 0, len(payments)),
+	}
+	if booking.SplitPlayers > 1 {
+		summary.ShareAmount = roundPrice(booking.TotalPrice / float64(booking.SplitPlayers))
+	}
+
+	for _, payment := range payments {
+		switch payment.Status {
+		case "approved":
+			summary.PaidAmount += payment.Amount - payment.RefundedAmount
+		case "pending":
+			summary.PendingAmount += payment.Amount
+		}
+		summary.Payments = append(summary.Payments, models.PaymentStatusResponse{
+			ID:             payment.ID,
+			Status:         payment.Status,
+			Amount:         payment.Amount,
+			Currency:       payment.Currency,
+			PaymentMethod:  payment.PaymentMethod,
+			PayerEmail:     payment.PayerEmail,
+			RefundedAmount: payment.RefundedAmount,
+			CreatedAt:      payment.CreatedAt,
+			UpdatedAt:      payment.UpdatedAt,
+		})
+	}
+	summary.PaidAmount = roundPrice(summary.PaidAmount)
+	summary.PendingAmount = roundPrice(summary.PendingAmount)
+	if unassigned := roundPrice(booking.TotalPrice - summary.PaidAmount - summary.PendingAmount); unassigned > 0 {
+		summary.UnassignedAmount = unassigned
+	}
+
+	return summary, nil
+}
+
+// bookingPaidAmount suma lo cobrado (descontando reembolsos) por los pagos aprobados de una reserva
+func bookingPaidAmount(db *gorm.DB, bookingID uint) (float64, error) {
+	var paid float64
+	if err := db.Model(&models.Payment{}).
+		Where("booking_id = ? AND status = ?", bookingID, "approved").
+		Select("COALESCE(SUM(amount - refunded_amount), 0)").
+		Scan(&paid).Error; err != nil {
+		return 0, errors.New("failed to fetch payments")
+	}
+	return roundPrice(paid), nil
+}
+
+func (s *PaymentService) GetPaymentStatus(userID uint, paymentID string) (*models.PaymentStatusResponse, error) {
+	var payment models.Payment
+	if err := s.db.Where("id = ? AND user_id = ?", paymentID, userID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, errors.New("failed to fetch payment")
+	}
+
+	return &models.PaymentStatusResponse{
+		ID:            payment.ID,
+		Status:        payment.Status,
+		Amount:        payment.Amount,
+		Currency:      payment.Currency,
+		PaymentMethod: payment.PaymentMethod,
+		PayerEmail:    payment.PayerEmail,
+		RefundedAmount: payment.RefundedAmount,
+		CreatedAt:     payment.CreatedAt,
+		UpdatedAt:     payment.UpdatedAt,
+	}, nil
+}
+
+func (s *PaymentService) HandleWebhook(action string, data map[string]interface{}) error {
+	paymentID, ok := data["id"].(string)
+	if !ok {
+		return errors.New("invalid payment ID in webhook data")
+	}
+
+	_, err := s.syncGatewayPayment(paymentID)
+	return err
+}
+
+// syncGatewayPayment consulta un pago en el proveedor y actualiza el pago y la reserva asociados.
+// Devuelve false si la notificación se ignoró por retroceder el estado del pago (ej: pending después de approved).
+func (s *PaymentService) syncGatewayPayment(paymentID string) (bool, error) {
+	// Obtener información del pago desde el proveedor
+	paymentInfo, err := s.gateway.GetPayment(paymentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get payment info from %s", s.gateway.Name())
+	}
+
+	// Buscar el pago por preference ID con la fila bloqueada: un reembolso o una notificación concurrente
+	// esperan a que se actualice el estado en lugar de pisarlo con una copia anterior
+	var payment models.Payment
+	applied, newlyApproved := false, false
+	status := s.mapMercadoPagoStatus(paymentInfo.Status)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("preference_id = ?", paymentInfo.PreferenceID).First(&payment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("payment not found")
+			}
+			return errors.New("failed to fetch payment")
+		}
+
+		// Las notificaciones pueden llegar desordenadas: nunca se retrocede a un estado anterior
+		if paymentStatusRank(status) < paymentStatusRank(payment.Status) {
+			return nil
+		}
+
+		// Solo se actualizan los datos que informa el proveedor; el monto reembolsado lo maneja refund
+		updates := map[string]interface{}{
+			"status":          status,
+			"mercado_pago_id": paymentID,
+			"payment_method":  paymentInfo.PaymentMethodID,
+		}
+		newlyApproved = status == "approved" && payment.Status != "approved"
+		if err := tx.Model(&payment).Updates(updates).Error; err != nil {
+			return errors.New("failed to update payment status")
+		}
+		applied = true
+		payment.Status = status
+		payment.MercadoPagoID = paymentID
+		payment.PaymentMethod = paymentInfo.PaymentMethodID
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if !applied {
+		return false, nil
+	}
+
+	if payment.Status != "approved" {
+		return true, nil
+	}
+
+	// Si el pago fue aprobado, confirmar la reserva con la cancha bloqueada: si el bloqueo de pago venció y
+	// otro tomó el turno mientras tanto, la reserva vence en lugar de confirmarse junto a la otra
+	var booking models.Booking
+	lapsed, released := false, false
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&booking, payment.BookingID).Error; err != nil {
+			return errors.New("failed to fetch booking")
+		}
+		if booking.Status != "pending" {
+			released = booking.Status != "confirmed"
+			return nil
+		}
+
+		// Una reserva dividida se confirma recién cuando se pagaron todas las partes
+		paid, err := bookingPaidAmount(tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		if paid < roundPrice(booking.TotalPrice) {
+			return nil
+		}
+
+		held, err := lockSlotForConfirmation(tx, &booking)
+		if err != nil {
+			return err
+		}
+		if booking.Status != "pending" {
+			released = booking.Status != "confirmed"
+			return nil
+		}
+		if !held {
+			lapsed = true
+			return transitionBooking(tx, &booking, "expired", nil, "payment approved after the hold expired and the slot was taken")
+		}
+		if err := transitionBooking(tx, &booking, "confirmed", nil, "payment approved"); err != nil && !errors.Is(err, ErrInvalidBookingTransition) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// El pago llegó sin turno para la reserva: se devuelve en lugar de confirmarla
+	switch {
+	case lapsed:
+		if _, err := s.RefundBookingPayments(booking.ID, 100, "booking slot taken after the payment hold expired"); err != nil {
+			log.Printf("payment %s: failed to refund booking %d after the hold expired: %v", payment.ID, booking.ID, err)
+		}
+	case released && newlyApproved:
+		if _, err := s.refund(&payment, payment.Amount-payment.RefundedAmount, "payment approved for a "+booking.Status+" booking", nil); err != nil {
+			log.Printf("payment %s approved for booking %d in status %s could not be refunded: %v", payment.ID, booking.ID, booking.Status, err)
+		}
+	}
+
+	return true, nil
+}
+
+// paymentStatusRank ordena los estados de un pago según su avance: un pago rechazado puede
+// aprobarse en un nuevo intento, pero uno aprobado solo puede pasar a reembolsado
+func paymentStatusRank(status string) int {
+	switch status {
+	case "pending":
+		return 0
+	case "rejected", "cancelled":
+		return 1
+	case "approved":
+		return 2
+	case "refunded":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// RefundBookingPayments reembolsa el porcentaje indicado de cada pago aprobado de una reserva (en las reservas
+// divididas, la parte de cada jugador). Devuelve el monto total a reembolsar, 0 si la reserva no tiene pagos aprobados.
+func (s *PaymentService) RefundBookingPayments(bookingID uint, percent float64, reason string) (float64, error) {
+	var payments []models.Payment
+	if err := s.db.Where("booking_id = ? AND status = ?", bookingID, "approved").Find(&payments).Error; err != nil {
+		return 0, errors.New("failed to fetch payment")
+	}
+
+	var total float64
+	var failed error
+	for i := range payments {
+		payment := &payments[i]
+
+		// Nunca se devuelve más que el saldo pendiente de reembolso
+		amount := roundPrice(payment.Amount * percent / 100)
+		if remaining := roundPrice(payment.Amount - payment.RefundedAmount); amount > remaining {
+			amount = remaining
+		}
+		if amount <= 0 {
+			continue
+		}
+		total += amount
+
+		if _, err := s.refund(payment, amount, reason, nil); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return roundPrice(total), failed
+}
+
+// RefundPayment reembolsa total o parcialmente un pago a pedido del dueño de la cancha o de un administrador
+func (s *PaymentService) RefundPayment(paymentID string, userID uint, role string, req *models.CreateRefundRequest) (*models.Refund, error) {
+	payment, err := s.findManagedPayment(paymentID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := roundPrice(payment.Amount - payment.RefundedAmount)
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+
+	return s.refund(payment, amount, req.Reason, &userID)
+}
+
+// GetPaymentRefunds lista los reembolsos de un pago gestionado por el dueño de la cancha o un administrador
+func (s *PaymentService) GetPaymentRefunds(paymentID string, userID uint, role string) ([]models.Refund, error) {
+	payment, err := s.findManagedPayment(paymentID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	var refunds []models.Refund
+	if err := s.db.Where("payment_id = ?", payment.ID).Order("created_at DESC").Find(&refunds).Error; err != nil {
+		return nil, errors.New("failed to fetch refunds")
+	}
+	return refunds, nil
+}
+
+// findManagedPayment obtiene un pago verificando que pertenezca a una cancha del usuario, salvo para administradores
+func (s *PaymentService) findManagedPayment(paymentID string, userID uint, role string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := s.db.Where("id = ?", paymentID).Preload("Booking.Court").First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, errors.New("failed to fetch payment")
+	}
+
+	if role != "admin" && payment.Booking.Court.OwnerID != userID {
+		return nil, errors.New("payment not found")
+	}
+	return &payment, nil
+}
+
+// refund registra y ejecuta un reembolso en el proveedor. Al devolverse el pago completo se marca como
+// refunded y la reserva asociada se cancela, liberando el turno.
+// El monto se reserva con el pago bloqueado antes de llamar al proveedor: los reembolsos pendientes cuentan
+// contra el saldo, por lo que reembolsos concurrentes nunca superan lo cobrado.
+func (s *PaymentService) refund(payment *models.Payment, amount float64, reason string, requestedBy *uint) (*models.Refund, error) {
+	amount = roundPrice(amount)
+	if amount <= 0 {
+		return nil, errors.New("invalid refund amount")
+	}
+
+	var refund models.Refund
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", payment.ID).Error; err != nil {
+			return errors.New("failed to fetch payment")
+		}
+		if locked.Status != "approved" {
+			return errors.New("payment is not refundable")
+		}
+
+		inProgress, err := pendingRefundAmount(tx, locked.ID)
+		if err != nil {
+			return err
+		}
+		if amount > roundPrice(locked.Amount-locked.RefundedAmount-inProgress) {
+			return errors.New("refund amount exceeds refundable balance")
+		}
+
+		refund = models.Refund{
+			PaymentID:   locked.ID,
+			BookingID:   locked.BookingID,
+			Amount:      amount,
+			Status:      "pending",
+			Reason:      reason,
+			RequestedBy: requestedBy,
+		}
+		if err := tx.Create(&refund).Error; err != nil {
+			return errors.New("failed to create refund")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Los cobros manuales los devuelve el club directamente; solo se registra el reembolso
+	result := &GatewayRefund{Status: "approved"}
+	if payment.Provider != "manual" {
+		result, err = s.gateway.RefundPayment(payment.MercadoPagoID, amount)
+		if err != nil {
+			if updateErr := s.db.Model(&refund).Update("status", "rejected").Error; updateErr != nil {
+				log.Printf("refund %d rejected by %s could not be recorded: %v", refund.ID, s.gateway.Name(), updateErr)
+			}
+			return nil, fmt.Errorf("failed to refund payment: %w", err)
+		}
+	}
+
+	// Si no se puede registrar, el reembolso queda pendiente con su monto reservado y lo completa la conciliación
+	if err := s.completeRefund(&refund, result.ID); err != nil {
+		log.Printf("refund %d approved by %s (%s) could not be recorded: %v", refund.ID, s.gateway.Name(), result.ID, err)
+		return nil, err
+	}
+
+	payment.RefundedAmount = roundPrice(payment.RefundedAmount + amount)
+	if payment.RefundedAmount >= payment.Amount {
+		payment.Status = "refunded"
+	}
+	return &refund, nil
+}
+
+// pendingRefundAmount suma los reembolsos de un pago que todavía no se completaron
+func pendingRefundAmount(tx *gorm.DB, paymentID string) (float64, error) {
+	var total float64
+	if err := tx.Model(&models.Refund{}).Where("payment_id = ? AND status = ?", paymentID, "pending").Select("COALESCE(SUM(amount), 0)").Scan(&total).Error; err != nil {
+		return 0, errors.New("failed to fetch refunds")
+	}
+	return roundPrice(total), nil
+}
+
+// completeRefund registra como aprobado un reembolso que el proveedor ya ejecutó y descuenta su monto del pago.
+// Primero guarda el ID del proveedor para que la conciliación pueda completarlo si el resto falla. Es idempotente:
+// un reembolso que ya no está pendiente no se vuelve a aplicar.
+func (s *PaymentService) completeRefund(refund *models.Refund, providerRefundID string) error {
+	if providerRefundID != "" && refund.ProviderRefundID == "" {
+		if err := s.db.Model(refund).Update("provider_refund_id", providerRefundID).Error; err != nil {
+			return errors.New("failed to update refund")
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var payment models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, "id = ?", refund.PaymentID).Error; err != nil {
+			return errors.New("failed to fetch payment")
+		}
+
+		result := tx.Model(&models.Refund{}).Where("id = ? AND status = ?", refund.ID, "pending").Updates(map[string]interface{}{"status": "approved", "provider_refund_id": providerRefundID})
+		if result.Error != nil {
+			return errors.New("failed to update refund")
+		}
+		refund.Status = "approved"
+		refund.ProviderRefundID = providerRefundID
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		refunded := roundPrice(payment.RefundedAmount + refund.Amount)
+		updates := map[string]interface{}{"refunded_amount": refunded}
+		if refunded >= payment.Amount {
+			updates["status"] = "refunded"
+		}
+		if err := tx.Model(&payment).Updates(updates).Error; err != nil {
+			return errors.New("failed to update payment")
+		}
+
+		// Un reembolso total deja la reserva sin pago: se cancela para liberar el turno, salvo que
+		// otros jugadores de una reserva dividida mantengan su parte pagada
+		if refunded >= payment.Amount {
+			var others int64
+			if err := tx.Model(&models.Payment{}).Where("booking_id = ? AND status = ? AND id <> ?", payment.BookingID, "approved", payment.ID).Count(&others).Error; err != nil {
+				return errors.New("failed to fetch payments")
+			}
+			if others > 0 {
+				return nil
+			}
+
+			var booking models.Booking
+			if err := tx.First(&booking, payment.BookingID).Error; err != nil {
+				return errors.New("failed to fetch booking")
+			}
+			if canTransitionBooking(booking.Status, "cancelled") {
+				if err := transitionBooking(tx, &booking, "cancelled", refund.RequestedBy, "payment refunded: "+refund.Reason); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// SandboxEnabled indica si los pagos se procesan con el proveedor simulado (PAYMENT_PROVIDER=sandbox)
+func (s *PaymentService) SandboxEnabled() bool {
+	_, ok := s.gateway.(*SandboxGateway)
+	return ok
+}
+
+// SimulateSandboxPayment aprueba o rechaza el pago de una preferencia del proveedor sandbox y lo procesa
+// como si hubiera llegado la notificación del webhook. Solo está disponible con PAYMENT_PROVIDER=sandbox.
+func (s *PaymentService) SimulateSandboxPayment(preferenceID string, req *models.SandboxPaymentRequest) (*models.PaymentStatusResponse, error) {
+	sandbox, ok := s.gateway.(*SandboxGateway)
+	if !ok {
+		return nil, errors.New("sandbox payments are disabled")
+	}
+
+	gatewayPayment, err := sandbox.SimulatePayment(preferenceID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.HandleWebhook("payment.created", map[string]interface{}{"id": gatewayPayment.ID}); err != nil {
+		return nil, err
+	}
+
+	var payment models.Payment
+	if err := s.db.Where("preference_id = ?", preferenceID).First(&payment).Error; err != nil {
+		return nil, errors.New("failed to fetch payment")
+	}
+	return s.GetPaymentStatus(payment.UserID, payment.ID)
+}
+
+// recordManualPayment registra como aprobado un cobro en efectivo o por transferencia realizado por el club.
+// payerID es el usuario que pagó, o el propietario que cobró cuando la reserva es de un invitado.
+// amount es el total de la reserva o, si parte ya se pagó online, el saldo restante.
+func recordManualPayment(tx *gorm.DB, booking *models.Booking, payerID uint, method string, amount float64) (*models.Payment, error) {
+	if method != "cash" && method != "transfer" {
+		return nil, errors.New("invalid payment method")
+	}
+
+	paymentID := uuid.New().String()
+	payment := models.Payment{
+		ID:        paymentID,
+		BookingID: booking.ID,
+		UserID:    payerID,
+		Amount:    amount,
+		Currency:  "ARS",
+		Provider:  "manual",
+		Status:    "approved",
+		// Los cobros manuales no tienen preferencia; se usa un valor propio para respetar el índice único
+		PreferenceID:  "manual-" + paymentID,
+		PaymentMethod: method,
+	}
+	if err := tx.Create(&payment).Error; err != nil {
+		return nil, errors.New("failed to create payment")
+	}
+	return &payment, nil
+}
+
+func (s *PaymentService) mapMercadoPagoStatus(status string) string {
+	switch status {
+	case "approved":
+		return "approved"
+	case "pending":
+		return "pending"
+	case "rejected":
+		return "rejected"
+	case "cancelled":
+		return "cancelled"
+	case "refunded":
+		return "refunded"
+	default:
+		return "pending"
+	}
+}
+
+func (s *PaymentService) GetPaymentStatistics() (*models.PaymentStatistics, error) {
+	var totalPayments int64
+	var approvedPayments int64
+	var pendingPayments int64
+	var rejectedPayments int64
+	var totalRevenue float64
+
+	// Contar total de pagos
+	if err := s.db.Model(&models.Payment{}).Count(&totalPayments).Error; err != nil {
+		return nil, errors.New("failed to count total payments")
+	}
+
+	// Contar pagos por estado
+	if err := s.db.Model(&models.Payment{}).Where("status = ?", "approved").Count(&approvedPayments).Error; err != nil {
+		return nil, errors.New("failed to count approved payments")
+	}
+
+	if err := s.db.Model(&models.Payment{}).Where("status = ?", "pending").Count(&pendingPayments).Error; err != nil {
+		return nil, errors.New("failed to count pending payments")
+	}
+
+	if err := s.db.Model(&models.Payment{}).Where("status = ?", "rejected").Count(&rejectedPayments).Error; err != nil {
+		return nil, errors.New("failed to count rejected payments")
+	}
+
+	// Calcular ingresos totales
+	if err := s.db.Model(&models.Payment{}).Where("status = ?", "approved").Select("COALESCE(SUM(amount), 0)").Scan(&totalRevenue).Error; err != nil {
+		return nil, errors.New("failed to calculate total revenue")
+	}
+
+	// Calcular promedio
+	var averageAmount float64
+	if totalPayments > 0 {
+		averageAmount = totalRevenue / float64(approvedPayments)
+	}
+
+	return &models.PaymentStatistics{
+		TotalPayments:    int(totalPayments),
+		ApprovedPayments: int(approvedPayments),
+		PendingPayments:  int(pendingPayments),
+		RejectedPayments: int(rejectedPayments),
+		TotalRevenue:     totalRevenue,
+		AverageAmount:    averageAmount,
+	}, nil
+}
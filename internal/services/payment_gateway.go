@@ -0,0 +1,141 @@
+package services
+
+import (
+	"backend-padel-go/internal/config"
+
+	"github.com/mercadopago/sdk-go"
+)
+
+// PaymentGateway abstrae al proveedor de pagos para poder reemplazarlo por uno simulado en pruebas y desarrollo local
+type PaymentGateway interface {
+	Name() string
+	CreatePreference(req *GatewayPreferenceRequest) (*GatewayPreference, error)
+	GetPayment(providerPaymentID string) (*GatewayPayment, error)
+	SearchPayments(externalReference string) ([]GatewayPayment, error)
+	RefundPayment(providerPaymentID string, amount float64) (*GatewayRefund, error)
+}
+
+// GatewayPreferenceRequest describe el cobro de una reserva
+type GatewayPreferenceRequest struct {
+	Title             string
+	Description       string
+	Amount            float64
+	Currency          string
+	PayerEmail        string
+	ExternalReference string // ID del pago en nuestra base de datos
+	SuccessURL        string
+	FailureURL        string
+	PendingURL        string
+	NotificationURL   string
+}
+
+// GatewayPreference es la preferencia de pago creada en el proveedor
+type GatewayPreference struct {
+	ID        string
+	InitPoint string
+}
+
+// GatewayPayment es el estado de un pago según el proveedor
+type GatewayPayment struct {
+	ID                string
+	PreferenceID      string
+	ExternalReference string
+	Status            string
+	PaymentMethodID   string
+}
+
+// GatewayRefund es la respuesta del proveedor a una devolución
+type GatewayRefund struct {
+	ID     string
+	Status string
+}
+
+// NewPaymentGateway crea el proveedor de pagos configurado en PAYMENT_PROVIDER
+func NewPaymentGateway(cfg *config.Config) PaymentGateway {
+	if cfg.Payment.Provider == "sandbox" {
+		return NewSandboxGateway(cfg.MercadoPago.BackendURL)
+	}
+	return NewMercadoPagoGateway(cfg.MercadoPago.AccessToken)
+}
+
+// MercadoPagoGateway cobra y devuelve pagos a través de la API de MercadoPago
+type MercadoPagoGateway struct {
+	client *mercadopago.Client
+}
+
+func NewMercadoPagoGateway(accessToken string) *MercadoPagoGateway {
+	return &MercadoPagoGateway{client: mercadopago.NewClient(accessToken)}
+}
+
+func (g *MercadoPagoGateway) Name() string {
+	return "mercadopago"
+}
+
+func (g *MercadoPagoGateway) CreatePreference(req *GatewayPreferenceRequest) (*GatewayPreference, error) {
+	preference, err := g.client.CreatePreference(mercadopago.PreferenceRequest{
+		Items: []mercadopago.PreferenceItem{
+			{
+				Title:       req.Title,
+				Description: req.Description,
+				Quantity:    1,
+				UnitPrice:   req.Amount,
+			},
+		},
+		Payer: &mercadopago.PreferencePayer{
+			Email: req.PayerEmail,
+		},
+		BackUrls: &mercadopago.PreferenceBackUrls{
+			Success: req.SuccessURL,
+			Failure: req.FailureURL,
+			Pending: req.PendingURL,
+		},
+		AutoReturn:        "approved",
+		ExternalReference: req.ExternalReference,
+		NotificationURL:   req.NotificationURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GatewayPreference{ID: preference.ID, InitPoint: preference.InitPoint}, nil
+}
+
+func (g *MercadoPagoGateway) GetPayment(providerPaymentID string) (*GatewayPayment, error) {
+	payment, err := g.client.GetPayment(providerPaymentID)
+	if err != nil {
+		return nil, err
+	}
+	return &GatewayPayment{
+		ID:                providerPaymentID,
+		PreferenceID:      payment.PreferenceID,
+		ExternalReference: payment.ExternalReference,
+		Status:            payment.Status,
+		PaymentMethodID:   payment.PaymentMethodID,
+	}, nil
+}
+
+func (g *MercadoPagoGateway) SearchPayments(externalReference string) ([]GatewayPayment, error) {
+	results, err := g.client.SearchPayments(externalReference)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]GatewayPayment, 0, len(results))
+	for _, payment := range results {
+		payments = append(payments, GatewayPayment{
+			ID:                payment.ID,
+			PreferenceID:      payment.PreferenceID,
+			ExternalReference: payment.ExternalReference,
+			Status:            payment.Status,
+			PaymentMethodID:   payment.PaymentMethodID,
+		})
+	}
+	return payments, nil
+}
+
+func (g *MercadoPagoGateway) RefundPayment(providerPaymentID string, amount float64) (*GatewayRefund, error) {
+	refund, err := g.client.RefundPayment(providerPaymentID, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &GatewayRefund{ID: refund.ID, Status: refund.Status}, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeGateway registra las devoluciones pedidas al proveedor y permite simular sus fallas
+type fakeGateway struct {
+	mu        sync.Mutex
+	refunded  float64
+	refunds   int
+	refundErr error
+	delay     time.Duration
+}
+
+func (g *fakeGateway) Name() string { return "fake" }
+
+func (g *fakeGateway) CreatePreference(req *GatewayPreferenceRequest) (*GatewayPreference, error) {
+	return &GatewayPreference{ID: "fake-pref-" + uuid.New().String()}, nil
+}
+
+func (g *fakeGateway) GetPayment(providerPaymentID string) (*GatewayPayment, error) {
+	return nil, errors.New("fake payment not found")
+}
+
+func (g *fakeGateway) SearchPayments(externalReference string) ([]GatewayPayment, error) {
+	return nil, nil
+}
+
+func (g *fakeGateway) RefundPayment(providerPaymentID string, amount float64) (*GatewayRefund, error) {
+	time.Sleep(g.delay)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.refundErr != nil {
+		return nil, g.refundErr
+	}
+	g.refunded = roundPrice(g.refunded + amount)
+	g.refunds++
+	return &GatewayRefund{ID: "fake-refund-" + uuid.New().String(), Status: "approved"}, nil
+}
+
+// newRefundFixture crea una reserva confirmada con su pago aprobado en el proveedor
+func newRefundFixture(t *testing.T, db *gorm.DB) (models.Booking, models.Payment) {
+	t.Helper()
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, testutil.Day(5), "18:00", "19:30", "confirmed", nil)
+
+	payment := models.Payment{
+		ID:            uuid.New().String(),
+		BookingID:     booking.ID,
+		UserID:        player.ID,
+		Amount:        booking.TotalPrice,
+		Provider:      "mercadopago",
+		Status:        "approved",
+		PreferenceID:  "pref-" + uuid.New().String(),
+		MercadoPagoID: "mp-1",
+	}
+	if err := db.Omit("Booking", "User").Create(&payment).Error; err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+	return booking, payment
+}
+
+func TestConcurrentPartialRefundsNeverExceedThePayment(t *testing.T) {
+	db := testutil.NewDB(t)
+	booking, payment := newRefundFixture(t, db)
+	gateway := &fakeGateway{delay: 20 * time.Millisecond}
+	service := NewPaymentService(db, gateway)
+
+	// Cada pedido parte de la misma lectura del pago, como dos dueños reembolsando a la vez
+	const attempts = 8
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stale := payment
+			_, errs[i] = service.refund(&stale, 3000, "partial refund", nil)
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else if err.Error() != "refund amount exceeds refundable balance" && err.Error() != "payment is not refundable" {
+			t.Fatalf("unexpected refund error: %v", err)
+		}
+	}
+	if succeeded != 4 || gateway.refunds != 4 || gateway.refunded != payment.Amount {
+		t.Fatalf("expected 4 refunds totalling %.2f, got %d succeeded and %d sent for %.2f", payment.Amount, succeeded, gateway.refunds, gateway.refunded)
+	}
+
+	db.First(&payment, "id = ?", payment.ID)
+	if payment.Status != "refunded" || payment.RefundedAmount != payment.Amount {
+		t.Fatalf("expected the payment fully refunded, got %s with %.2f refunded", payment.Status, payment.RefundedAmount)
+	}
+	db.First(&booking, booking.ID)
+	if booking.Status != "cancelled" {
+		t.Fatalf("expected the booking cancelled after the full refund, got %s", booking.Status)
+	}
+}
+
+func TestRejectedRefundReleasesTheReservedAmount(t *testing.T) {
+	db := testutil.NewDB(t)
+	_, payment := newRefundFixture(t, db)
+	gateway := &fakeGateway{refundErr: errors.New("provider unavailable")}
+	service := NewPaymentService(db, gateway)
+
+	if _, err := service.refund(&payment, payment.Amount, "full refund", nil); err == nil {
+		t.Fatal("expected the refund to fail")
+	}
+	var refund models.Refund
+	db.Where("payment_id = ?", payment.ID).First(&refund)
+	if refund.Status != "rejected" {
+		t.Fatalf("expected the failed refund to be rejected, got %s", refund.Status)
+	}
+
+	gateway.refundErr = nil
+	if _, err := service.refund(&payment, payment.Amount, "full refund", nil); err != nil {
+		t.Fatalf("expected the retry to refund the full amount: %v", err)
+	}
+}
+
+func TestReconciliationCompletesRefundsLeftPending(t *testing.T) {
+	db := testutil.NewDB(t)
+	_, payment := newRefundFixture(t, db)
+	gateway := &fakeGateway{}
+	service := NewPaymentService(db, gateway)
+
+	// El proveedor devolvió 5000 pero no se pudo registrar; otros 2000 quedaron sin respuesta del proveedor
+	executed := models.Refund{PaymentID: payment.ID, BookingID: payment.BookingID, Amount: 5000, Status: "pending", ProviderRefundID: "fake-refund-1"}
+	unknown := models.Refund{PaymentID: payment.ID, BookingID: payment.BookingID, Amount: 2000, Status: "pending"}
+	db.Create(&executed)
+	db.Create(&unknown)
+
+	// Mientras tanto ambos montos siguen reservados
+	if _, err := service.refund(&payment, payment.Amount-6000, "refund", nil); err == nil {
+		t.Fatal("expected the pending refunds to count against the refundable balance")
+	}
+
+	report, err := service.ReconcilePendingPayments(0)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(report.Mismatches) != 2 {
+		t.Fatalf("expected both pending refunds in the report, got %+v", report.Mismatches)
+	}
+
+	db.First(&executed, executed.ID)
+	if executed.Status != "approved" {
+		t.Fatalf("expected the executed refund to be completed, got %s", executed.Status)
+	}
+	db.First(&unknown, unknown.ID)
+	if unknown.Status != "pending" {
+		t.Fatalf("expected the refund with unknown outcome to stay pending, got %s", unknown.Status)
+	}
+	db.First(&payment, "id = ?", payment.ID)
+	if payment.RefundedAmount != 5000 || payment.Status != "approved" {
+		t.Fatalf("expected 5000 refunded on an approved payment, got %.2f (%s)", payment.RefundedAmount, payment.Status)
+	}
+	if gateway.refunds != 0 {
+		t.Fatalf("expected reconciliation not to refund again at the provider, got %d refunds", gateway.refunds)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// SandboxGateway simula un proveedor de pagos en memoria. Permite recorrer el flujo
+// reserva → pago → confirmación sin credenciales de MercadoPago, aprobando o rechazando
+// pagos a pedido y notificándolos como si llegara el webhook del proveedor.
+type SandboxGateway struct {
+	mu          sync.Mutex
+	baseURL     string
+	preferences map[string]*GatewayPreferenceRequest
+	payments    map[string]*sandboxPayment
+}
+
+type sandboxPayment struct {
+	GatewayPayment
+	Amount   float64
+	Refunded float64
+}
+
+func NewSandboxGateway(baseURL string) *SandboxGateway {
+	return &SandboxGateway{
+		baseURL:     baseURL,
+		preferences: make(map[string]*GatewayPreferenceRequest),
+		payments:    make(map[string]*sandboxPayment),
+	}
+}
+
+func (g *SandboxGateway) Name() string {
+	return "sandbox"
+}
+
+func (g *SandboxGateway) CreatePreference(req *GatewayPreferenceRequest) (*GatewayPreference, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("invalid preference amount")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := "sandbox-pref-" + uuid.New().String()
+	stored := *req
+	g.preferences[id] = &stored
+
+	return &GatewayPreference{
+		ID:        id,
+		InitPoint: fmt.Sprintf("%s/api/v1/payments/sandbox/%s", g.baseURL, id),
+	}, nil
+}
+
+func (g *SandboxGateway) GetPayment(providerPaymentID string) (*GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[providerPaymentID]
+	if !ok {
+		return nil, errors.New("sandbox payment not found")
+	}
+	result := payment.GatewayPayment
+	return &result, nil
+}
+
+func (g *SandboxGateway) SearchPayments(externalReference string) ([]GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var payments []GatewayPayment
+	for _, payment := range g.payments {
+		if payment.ExternalReference == externalReference {
+			payments = append(payments, payment.GatewayPayment)
+		}
+	}
+	return payments, nil
+}
+
+func (g *SandboxGateway) RefundPayment(providerPaymentID string, amount float64) (*GatewayRefund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[providerPaymentID]
+	if !ok {
+		return nil, errors.New("sandbox payment not found")
+	}
+	if payment.Status != "approved" {
+		return nil, errors.New("sandbox payment is not refundable")
+	}
+	if amount <= 0 || roundPrice(payment.Refunded+amount) > payment.Amount {
+		return nil, errors.New("invalid sandbox refund amount")
+	}
+
+	payment.Refunded = roundPrice(payment.Refunded + amount)
+	if payment.Refunded >= payment.Amount {
+		payment.Status = "refunded"
+	}
+
+	return &GatewayRefund{ID: "sandbox-refund-" + uuid.New().String(), Status: "approved"}, nil
+}
+
+// SimulatePayment registra el pago de una preferencia con el estado indicado (approved, rejected, pending o cancelled)
+func (g *SandboxGateway) SimulatePayment(preferenceID, status string) (*GatewayPayment, error) {
+	switch status {
+	case "approved", "rejected", "pending", "cancelled":
+	default:
+		return nil, errors.New("invalid sandbox payment status")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	preference, ok := g.preferences[preferenceID]
+	if !ok {
+		return nil, errors.New("sandbox preference not found")
+	}
+
+	payment := &sandboxPayment{
+		GatewayPayment: GatewayPayment{
+			ID:                "sandbox-pay-" + uuid.New().String(),
+			PreferenceID:      preferenceID,
+			ExternalReference: preference.ExternalReference,
+			Status:            status,
+			PaymentMethodID:   "sandbox",
+		},
+		Amount: preference.Amount,
+	}
+	g.payments[payment.ID] = payment
+
+	result := payment.GatewayPayment
+	return &result, nil
+}
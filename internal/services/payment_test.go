@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newSandboxPayment crea en el sandbox la preferencia de una reserva y su pago pendiente
+func newSandboxPayment(t *testing.T, db *gorm.DB, gateway *SandboxGateway, booking *models.Booking, amount float64) models.Payment {
+	t.Helper()
+	payment := models.Payment{ID: uuid.New().String(), BookingID: booking.ID, UserID: *booking.UserID, Amount: amount, Provider: "sandbox", Status: "pending"}
+	preference, err := gateway.CreatePreference(&GatewayPreferenceRequest{Title: "Reserva", Amount: amount, Currency: "ARS", ExternalReference: payment.ID})
+	if err != nil {
+		t.Fatalf("create preference: %v", err)
+	}
+	payment.PreferenceID = preference.ID
+	if err := db.Omit("Booking", "User").Create(&payment).Error; err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+	return payment
+}
+
+// approveSandboxPayment aprueba el pago en el sandbox y procesa la notificación del proveedor
+func approveSandboxPayment(t *testing.T, service *PaymentService, gateway *SandboxGateway, payment *models.Payment) {
+	t.Helper()
+	gatewayPayment, err := gateway.SimulatePayment(payment.PreferenceID, "approved")
+	if err != nil {
+		t.Fatalf("simulate payment: %v", err)
+	}
+	if _, err := service.syncGatewayPayment(gatewayPayment.ID); err != nil {
+		t.Fatalf("sync payment: %v", err)
+	}
+}
+
+func TestLatePaymentForTakenSlotIsRefunded(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	first := testutil.CreateUser(t, db, "first@test.com", "user")
+	second := testutil.CreateUser(t, db, "second@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	date := testutil.Day(3)
+
+	// El bloqueo de la primera reserva venció y otro jugador reservó y pagó el mismo turno
+	lapsed := time.Now().Add(-time.Minute)
+	late := testutil.CreateBooking(t, db, court.ID, first.ID, date, "18:00", "19:30", "pending", &lapsed)
+	taken := testutil.CreateBooking(t, db, court.ID, second.ID, date, "18:00", "19:30", "confirmed", nil)
+
+	gateway := NewSandboxGateway("http://localhost:8080")
+	service := NewPaymentService(db, gateway)
+	payment := newSandboxPayment(t, db, gateway, &late, late.TotalPrice)
+	approveSandboxPayment(t, service, gateway, &payment)
+
+	db.First(&late, late.ID)
+	if late.Status != "expired" {
+		t.Fatalf("expected the late booking to expire, got %s", late.Status)
+	}
+	db.First(&taken, taken.ID)
+	if taken.Status != "confirmed" {
+		t.Fatalf("expected the other booking to stay confirmed, got %s", taken.Status)
+	}
+	db.First(&payment, "id = ?", payment.ID)
+	if payment.Status != "refunded" || payment.RefundedAmount != payment.Amount {
+		t.Fatalf("expected the late payment to be refunded, got %s with %.2f refunded", payment.Status, payment.RefundedAmount)
+	}
+}
+
+func TestLatePaymentForFreeSlotConfirmsBooking(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+
+	lapsed := time.Now().Add(-time.Minute)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, testutil.Day(3), "18:00", "19:30", "pending", &lapsed)
+
+	gateway := NewSandboxGateway("http://localhost:8080")
+	service := NewPaymentService(db, gateway)
+	payment := newSandboxPayment(t, db, gateway, &booking, booking.TotalPrice)
+	approveSandboxPayment(t, service, gateway, &payment)
+
+	db.First(&booking, booking.ID)
+	if booking.Status != "confirmed" {
+		t.Fatalf("expected the booking to be confirmed while its slot is free, got %s", booking.Status)
+	}
+	db.First(&payment, "id = ?", payment.ID)
+	if payment.Status != "approved" {
+		t.Fatalf("expected the payment to stay approved, got %s", payment.Status)
+	}
+}
@@ -0,0 +1,527 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// El rating sigue la escala de nivel de padel: cada nivel equivale a ratingPerLevel puntos a partir de baseRating,
+// de modo que el nivel 1 arranca en 1000 y el nivel 7 en 2200
+const (
+	baseRating         = 1000.0
+	ratingPerLevel     = 200.0
+	defaultPlayerLevel = 3.0
+	// Como en Glicko, el rating de un jugador con pocos partidos es incierto y se mueve más rápido
+	provisionalMatches = 10
+	provisionalKFactor = 48.0
+	establishedKFactor = 24.0
+)
+
+type PlayerService struct {
+	db *gorm.DB
+}
+
+func NewPlayerService(db *gorm.DB) *PlayerService {
+	return &PlayerService{db: db}
+}
+
+// GetProfile obtiene el perfil de padel de un usuario; si todavía no lo completó se crea con el nivel por defecto
+func (s *PlayerService) GetProfile(userID uint) (*models.PlayerProfileResponse, error) {
+	profile, err := findOrCreatePlayerProfile(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toPlayerProfileResponse(profile), nil
+}
+
+// UpdateProfile actualiza el nivel declarado, el lado preferido y la mano hábil del jugador.
+// Mientras no haya jugado partidos, el nivel declarado define su rating inicial.
+func (s *PlayerService) UpdateProfile(userID uint, req *models.UpdatePlayerProfileRequest) (*models.PlayerProfileResponse, error) {
+	if req.DeclaredLevel != nil && (*req.DeclaredLevel < 1 || *req.DeclaredLevel > 7) {
+		return nil, errors.New("level must be between 1 and 7")
+	}
+	if req.PreferredSide != nil && *req.PreferredSide != "drive" && *req.PreferredSide != "reves" && *req.PreferredSide != "both" {
+		return nil, errors.New("invalid preferred side")
+	}
+	if req.DominantHand != nil && *req.DominantHand != "right" && *req.DominantHand != "left" {
+		return nil, errors.New("invalid dominant hand")
+	}
+
+	var profile *models.PlayerProfile
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		profile, err = findOrCreatePlayerProfile(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.PreferredSide != nil {
+			updates["preferred_side"] = *req.PreferredSide
+		}
+		if req.DominantHand != nil {
+			updates["dominant_hand"] = *req.DominantHand
+		}
+		if req.DeclaredLevel != nil {
+			updates["declared_level"] = *req.DeclaredLevel
+			if profile.MatchesPlayed == 0 {
+				newRating := ratingForLevel(*req.DeclaredLevel)
+				if newRating != profile.Rating {
+					history := models.RatingHistory{
+						UserID:    userID,
+						OldRating: profile.Rating,
+						NewRating: newRating,
+						Change:    roundPrice(newRating - profile.Rating),
+						Reason:    "declared_level",
+					}
+					if err := tx.Create(&history).Error; err != nil {
+						return errors.New("failed to record rating history")
+					}
+					updates["rating"] = newRating
+				}
+			}
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(profile).Updates(updates).Error; err != nil {
+			return errors.New("failed to update player profile")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProfile(profile.UserID)
+}
+
+// GetRatingHistory obtiene los últimos cambios de rating del jugador, del más reciente al más antiguo
+func (s *PlayerService) GetRatingHistory(userID uint) ([]models.RatingHistory, error) {
+	var history []models.RatingHistory
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(100).Find(&history).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch rating history")
+	}
+	return history, nil
+}
+
+// RecordMatchResult registra el resultado del partido de una reserva completada informado por uno de sus jugadores.
+// Como en las ligas, queda pendiente de que un jugador del equipo rival lo confirme o lo dispute; el rating se
+// actualiza recién al confirmarse. Un resultado en disputa puede volver a informarse.
+func (s *PlayerService) RecordMatchResult(bookingID uint, userID uint, req *models.RecordMatchResultRequest) (*models.MatchResult, error) {
+	if len(req.Team1) < 1 || len(req.Team1) > 2 || len(req.Team1) != len(req.Team2) {
+		return nil, errors.New("teams must have the same number of players (1 or 2)")
+	}
+	winnerTeam, err := matchWinner(req.Sets)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.MatchResult
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var booking models.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", bookingID).Scopes(visibleBookings(userID)).
+			Preload("Court").
+			Preload("Participants", "status = ?", "accepted").
+			First(&booking).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("booking not found")
+			}
+			return errors.New("failed to fetch booking")
+		}
+		if booking.Status != "completed" {
+			return errors.New("match result can only be recorded for completed bookings")
+		}
+
+		// Jugadores de la reserva: el organizador y los participantes que aceptaron
+		players := map[uint]bool{}
+		if booking.UserID != nil {
+			players[*booking.UserID] = true
+		}
+		for _, participant := range booking.Participants {
+			if participant.UserID != nil {
+				players[*participant.UserID] = true
+			}
+		}
+		if !players[userID] {
+			return errors.New("only players of this booking can record the result")
+		}
+
+		result = models.MatchResult{
+			BookingID:  booking.ID,
+			RecordedBy: userID,
+			Sets:       req.Sets,
+			WinnerTeam: winnerTeam,
+			Status:     "reported",
+		}
+		seen := map[uint]bool{}
+		for team, ids := range [][]uint{req.Team1, req.Team2} {
+			for _, id := range ids {
+				if !players[id] {
+					return errors.New("player is not part of this booking")
+				}
+				if seen[id] {
+					return errors.New("player cannot be in both teams")
+				}
+				seen[id] = true
+				result.Players = append(result.Players, models.MatchResultPlayer{UserID: id, Team: team + 1})
+				if id == userID {
+					result.ReportedTeam = team + 1
+				}
+			}
+		}
+		// Quien informa tiene que haber jugado: la confirmación queda a cargo del equipo rival
+		if result.ReportedTeam == 0 {
+			return errors.New("reporter must be in one of the teams")
+		}
+
+		// Un resultado informado solo puede corregirlo el mismo equipo; uno en disputa puede informarlo cualquiera
+		var existing models.MatchResult
+		err := tx.Preload("Players").Where("booking_id = ?", booking.ID).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to fetch match result")
+		}
+		if err == nil {
+			if existing.Status == "confirmed" {
+				return errors.New("match result already recorded")
+			}
+			if existing.Status == "reported" && existing.RecordedBy != userID && resultTeam(&existing, userID) != existing.ReportedTeam {
+				return errors.New("result already reported by the opponent")
+			}
+			if err := tx.Where("match_result_id = ?", existing.ID).Delete(&models.MatchResultPlayer{}).Error; err != nil {
+				return errors.New("failed to record match result")
+			}
+			result.ID = existing.ID
+			result.CreatedAt = existing.CreatedAt
+		}
+
+		if err := tx.Save(&result).Error; err != nil {
+			return errors.New("failed to record match result")
+		}
+
+		message := fmt.Sprintf("The result of your match at %s on %s was reported: %s. Confirm it or dispute it.",
+			booking.Court.Name, booking.Date.Format("2006-01-02"), formatSets(req.Sets))
+		for _, player := range result.Players {
+			if player.Team == result.ReportedTeam {
+				continue
+			}
+			if err := notifyUser(tx, player.UserID, "match_result_reported", "Confirm your match result", message, "booking", &booking.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ConfirmMatchResult confirma el resultado informado por el equipo rival y actualiza el rating de los jugadores
+func (s *PlayerService) ConfirmMatchResult(bookingID uint, userID uint) (*models.MatchResult, error) {
+	return s.respondMatchResult(bookingID, userID, true)
+}
+
+// DisputeMatchResult rechaza el resultado informado por el equipo rival; el rating no cambia hasta que se
+// informe y confirme un resultado
+func (s *PlayerService) DisputeMatchResult(bookingID uint, userID uint) (*models.MatchResult, error) {
+	return s.respondMatchResult(bookingID, userID, false)
+}
+
+func (s *PlayerService) respondMatchResult(bookingID uint, userID uint, confirm bool) (*models.MatchResult, error) {
+	var result models.MatchResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Players").Where("booking_id = ?", bookingID).First(&result).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("match result not found")
+			}
+			return errors.New("failed to fetch match result")
+		}
+		team := resultTeam(&result, userID)
+		if team == 0 {
+			return errors.New("match result not found")
+		}
+		if result.Status != "reported" {
+			return errors.New("no result to confirm")
+		}
+		// Solo el equipo rival de quien informó puede confirmar o disputar el resultado
+		if result.ReportedTeam == 0 || team != 3-result.ReportedTeam {
+			return errors.New("the result must be confirmed by the opponent")
+		}
+
+		updates := map[string]interface{}{"status": "disputed"}
+		notificationType, title := "match_result_disputed", "Match result disputed"
+		if confirm {
+			now := time.Now()
+			updates = map[string]interface{}{"status": "confirmed", "confirmed_by": userID, "confirmed_at": now}
+			notificationType, title = "match_result_confirmed", "Match result confirmed"
+			result.ConfirmedBy = &userID
+			result.ConfirmedAt = &now
+		}
+		if err := tx.Model(&result).Omit(clause.Associations).Updates(updates).Error; err != nil {
+			return errors.New("failed to update match result")
+		}
+		result.Status = updates["status"].(string)
+		if confirm {
+			if err := applyMatchRatings(tx, &result); err != nil {
+				return err
+			}
+		}
+
+		message := fmt.Sprintf("The result you reported for booking %d was %s by your opponent.", result.BookingID, updates["status"])
+		return notifyUser(tx, result.RecordedBy, notificationType, title, message, "booking", &result.BookingID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// resultTeam devuelve el equipo del jugador en el resultado, o 0 si no jugó el partido
+func resultTeam(result *models.MatchResult, userID uint) int {
+	for _, player := range result.Players {
+		if player.UserID == userID {
+			return player.Team
+		}
+	}
+	return 0
+}
+
+// GetMatchResult obtiene el resultado registrado para una reserva visible para el usuario
+func (s *PlayerService) GetMatchResult(bookingID uint, userID uint) (*models.MatchResult, error) {
+	var booking models.Booking
+	if err := s.db.Where("id = ?", bookingID).Scopes(visibleBookings(userID)).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, errors.New("failed to fetch booking")
+	}
+
+	var result models.MatchResult
+	if err := s.db.Preload("Players").Where("booking_id = ?", booking.ID).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("match result not found")
+		}
+		return nil, errors.New("failed to fetch match result")
+	}
+	return &result, nil
+}
+
+// SuggestPartners sugiere compañeros de nivel similar (hasta un nivel de diferencia) que juegan del lado complementario
+func (s *PlayerService) SuggestPartners(userID uint, req *models.SuggestPartnersRequest) ([]*models.PlayerProfileResponse, error) {
+	profile, err := findOrCreatePlayerProfile(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	side := complementarySide(profile.PreferredSide)
+	if req.Side != nil {
+		if *req.Side != "drive" && *req.Side != "reves" {
+			return nil, errors.New("invalid preferred side")
+		}
+		side = *req.Side
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	query := s.db.Joins("JOIN users ON users.id = player_profiles.user_id AND users.is_active = ? AND users.deleted_at IS NULL", true).
+		Where("player_profiles.user_id <> ?", userID).
+		Where("player_profiles.rating BETWEEN ? AND ?", profile.Rating-ratingPerLevel, profile.Rating+ratingPerLevel)
+	if side != "" {
+		query = query.Where("player_profiles.preferred_side IN ?", []string{side, "both"})
+	}
+
+	var candidates []models.PlayerProfile
+	err = query.Preload("User").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ABS(player_profiles.rating - ?), player_profiles.matches_played DESC",
+			Vars:               []interface{}{profile.Rating},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch partner suggestions")
+	}
+
+	responses := make([]*models.PlayerProfileResponse, 0, len(candidates))
+	for i := range candidates {
+		responses = append(responses, toPlayerProfileResponse(&candidates[i]))
+	}
+	return responses, nil
+}
+
+// applyMatchRatings actualiza el rating de los jugadores con la fórmula de Elo por equipos: cada equipo juega con
+// el promedio del rating de sus jugadores y cada jugador suma o resta según su propio factor K
+func applyMatchRatings(tx *gorm.DB, result *models.MatchResult) error {
+	profiles := map[uint]*models.PlayerProfile{}
+	teamRatings := [2]float64{}
+	teamSizes := [2]int{}
+	// La sesión evita que el error de un perfil inexistente quede en la consulta que lo crea
+	locked := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Session(&gorm.Session{})
+	for _, player := range result.Players {
+		profile, err := findOrCreatePlayerProfile(locked, player.UserID)
+		if err != nil {
+			return err
+		}
+		profiles[player.UserID] = profile
+		teamRatings[player.Team-1] += profile.Rating
+		teamSizes[player.Team-1]++
+	}
+	for i := range teamRatings {
+		teamRatings[i] /= float64(teamSizes[i])
+	}
+
+	for _, player := range result.Players {
+		profile := profiles[player.UserID]
+		own, rival := teamRatings[player.Team-1], teamRatings[2-player.Team]
+		expected := 1 / (1 + math.Pow(10, (rival-own)/400))
+
+		score, reason, wins := 0.0, "match_lost", profile.Wins
+		if player.Team == result.WinnerTeam {
+			score, reason, wins = 1.0, "match_won", profile.Wins+1
+		}
+		kFactor := establishedKFactor
+		if profile.MatchesPlayed < provisionalMatches {
+			kFactor = provisionalKFactor
+		}
+		change := roundPrice(kFactor * (score - expected))
+		newRating := roundPrice(profile.Rating + change)
+
+		err := tx.Model(profile).Updates(map[string]interface{}{
+			"rating":         newRating,
+			"matches_played": profile.MatchesPlayed + 1,
+			"wins":           wins,
+		}).Error
+		if err != nil {
+			return errors.New("failed to update player rating")
+		}
+
+		history := models.RatingHistory{
+			UserID:        player.UserID,
+			MatchResultID: &result.ID,
+			BookingID:     &result.BookingID,
+			OldRating:     profile.Rating,
+			NewRating:     newRating,
+			Change:        change,
+			Reason:        reason,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return errors.New("failed to record rating history")
+		}
+	}
+	return nil
+}
+
+// matchWinner valida el marcador y devuelve el equipo que ganó más sets
+func matchWinner(sets []models.SetScore) (int, error) {
+	if len(sets) < 1 || len(sets) > 5 {
+		return 0, errors.New("invalid score")
+	}
+	won := [2]int{}
+	for _, set := range sets {
+		if set.Team1 < 0 || set.Team2 < 0 || set.Team1 == set.Team2 {
+			return 0, errors.New("invalid score")
+		}
+		if set.Team1 > set.Team2 {
+			won[0]++
+		} else {
+			won[1]++
+		}
+	}
+	if won[0] == won[1] {
+		return 0, errors.New("invalid score")
+	}
+	if won[0] > won[1] {
+		return 1, nil
+	}
+	return 2, nil
+}
+
+// findOrCreatePlayerProfile obtiene el perfil del jugador, creándolo con el nivel por defecto si no existe
+func findOrCreatePlayerProfile(db *gorm.DB, userID uint) (*models.PlayerProfile, error) {
+	var profile models.PlayerProfile
+	err := db.Preload("User").Where("user_id = ?", userID).First(&profile).Error
+	if err == nil {
+		return &profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to fetch player profile")
+	}
+
+	var user models.User
+	if err := db.Session(&gorm.Session{NewDB: true}).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, errors.New("failed to fetch user")
+	}
+
+	profile = models.PlayerProfile{
+		UserID:        userID,
+		DeclaredLevel: defaultPlayerLevel,
+		PreferredSide: "both",
+		DominantHand:  "right",
+		Rating:        ratingForLevel(defaultPlayerLevel),
+		User:          user,
+	}
+	if err := db.Session(&gorm.Session{NewDB: true}).Omit("User").Create(&profile).Error; err != nil {
+		return nil, errors.New("failed to create player profile")
+	}
+	return &profile, nil
+}
+
+// playerLevel devuelve el nivel equivalente al rating del jugador, si ya tiene perfil
+func playerLevel(db *gorm.DB, userID uint) (float64, bool) {
+	var profile models.PlayerProfile
+	if err := db.Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		return 0, false
+	}
+	return levelForRating(profile.Rating), true
+}
+
+func ratingForLevel(level float64) float64 {
+	return baseRating + (level-1)*ratingPerLevel
+}
+
+func levelForRating(rating float64) float64 {
+	level := math.Round((1+(rating-baseRating)/ratingPerLevel)*10) / 10
+	return math.Max(1, math.Min(7, level))
+}
+
+func complementarySide(side string) string {
+	switch side {
+	case "drive":
+		return "reves"
+	case "reves":
+		return "drive"
+	}
+	return ""
+}
+
+func toPlayerProfileResponse(profile *models.PlayerProfile) *models.PlayerProfileResponse {
+	return &models.PlayerProfileResponse{
+		UserID:        profile.UserID,
+		FirstName:     profile.User.FirstName,
+		LastName:      profile.User.LastName,
+		DeclaredLevel: profile.DeclaredLevel,
+		Level:         levelForRating(profile.Rating),
+		PreferredSide: profile.PreferredSide,
+		DominantHand:  profile.DominantHand,
+		Rating:        profile.Rating,
+		MatchesPlayed: profile.MatchesPlayed,
+		Wins:          profile.Wins,
+	}
+}
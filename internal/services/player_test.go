@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestMatchResultUpdatesRatingsOnlyOnceConfirmed(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	organizer := testutil.CreateUser(t, db, "organizer@test.com", "user")
+	rival := testutil.CreateUser(t, db, "rival@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	booking := testutil.CreateBooking(t, db, court.ID, organizer.ID, testutil.Day(-1), "18:00", "19:30", "completed", nil)
+	participant := models.BookingParticipant{BookingID: booking.ID, UserID: &rival.ID, Email: rival.Email, Status: "accepted", InvitedBy: organizer.ID}
+	if err := db.Create(&participant).Error; err != nil {
+		t.Fatalf("create participant: %v", err)
+	}
+	service := NewPlayerService(db)
+
+	req := &models.RecordMatchResultRequest{Team1: []uint{organizer.ID}, Team2: []uint{rival.ID}, Sets: []models.SetScore{{Team1: 6, Team2: 1}, {Team1: 6, Team2: 2}}}
+	if _, err := service.RecordMatchResult(booking.ID, organizer.ID, req); err != nil {
+		t.Fatalf("report result: %v", err)
+	}
+	assertRatingChanges(t, db, 0)
+
+	// Quien informó no puede confirmar su propio resultado
+	if _, err := service.ConfirmMatchResult(booking.ID, organizer.ID); err == nil || err.Error() != "the result must be confirmed by the opponent" {
+		t.Fatalf("expected the reporter not to confirm, got %v", err)
+	}
+
+	// El rival lo disputa: el rating no cambia y el resultado puede volver a informarse
+	if _, err := service.DisputeMatchResult(booking.ID, rival.ID); err != nil {
+		t.Fatalf("dispute result: %v", err)
+	}
+	assertRatingChanges(t, db, 0)
+	req.Sets = []models.SetScore{{Team1: 6, Team2: 1}, {Team1: 4, Team2: 6}, {Team1: 6, Team2: 4}}
+	if _, err := service.RecordMatchResult(booking.ID, organizer.ID, req); err != nil {
+		t.Fatalf("report result again: %v", err)
+	}
+
+	result, err := service.ConfirmMatchResult(booking.ID, rival.ID)
+	if err != nil {
+		t.Fatalf("confirm result: %v", err)
+	}
+	if result.Status != "confirmed" || len(result.Sets) != 3 {
+		t.Fatalf("expected the second report confirmed, got %s with %d sets", result.Status, len(result.Sets))
+	}
+	assertRatingChanges(t, db, 2)
+
+	if _, err := service.ConfirmMatchResult(booking.ID, rival.ID); err == nil || err.Error() != "no result to confirm" {
+		t.Fatalf("expected the result to be confirmed only once, got %v", err)
+	}
+	assertRatingChanges(t, db, 2)
+}
+
+func TestMatchResultRequiresTheReporterAndAnOpponent(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	organizer := testutil.CreateUser(t, db, "organizer@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	booking := testutil.CreateBooking(t, db, court.ID, organizer.ID, testutil.Day(-1), "18:00", "19:30", "completed", nil)
+	partner := testutil.CreateUser(t, db, "partner@test.com", "user")
+	rival1 := testutil.CreateUser(t, db, "rival1@test.com", "user")
+	rival2 := testutil.CreateUser(t, db, "rival2@test.com", "user")
+	for _, player := range []*models.User{&partner, &rival1, &rival2} {
+		participant := models.BookingParticipant{BookingID: booking.ID, UserID: &player.ID, Email: player.Email, Status: "accepted", InvitedBy: organizer.ID}
+		if err := db.Create(&participant).Error; err != nil {
+			t.Fatalf("create participant: %v", err)
+		}
+	}
+	service := NewPlayerService(db)
+	sets := []models.SetScore{{Team1: 6, Team2: 1}, {Team1: 6, Team2: 2}}
+
+	// El organizador no puede informar un partido entre otros jugadores para que lo confirme uno de ellos
+	outsider := &models.RecordMatchResultRequest{Team1: []uint{partner.ID}, Team2: []uint{rival1.ID}, Sets: sets}
+	if _, err := service.RecordMatchResult(booking.ID, organizer.ID, outsider); err == nil || err.Error() != "reporter must be in one of the teams" {
+		t.Fatalf("expected a reporter outside the teams to be rejected, got %v", err)
+	}
+
+	req := &models.RecordMatchResultRequest{Team1: []uint{organizer.ID, partner.ID}, Team2: []uint{rival1.ID, rival2.ID}, Sets: sets}
+	if _, err := service.RecordMatchResult(booking.ID, organizer.ID, req); err != nil {
+		t.Fatalf("report result: %v", err)
+	}
+	// El compañero de quien informó tampoco puede confirmarlo
+	if _, err := service.ConfirmMatchResult(booking.ID, partner.ID); err == nil || err.Error() != "the result must be confirmed by the opponent" {
+		t.Fatalf("expected the reporter's partner not to confirm, got %v", err)
+	}
+	assertRatingChanges(t, db, 0)
+	if _, err := service.ConfirmMatchResult(booking.ID, rival2.ID); err != nil {
+		t.Fatalf("confirm result: %v", err)
+	}
+	assertRatingChanges(t, db, 4)
+}
+
+// assertRatingChanges verifica la cantidad de cambios de rating registrados por partidos
+func assertRatingChanges(t *testing.T, db *gorm.DB, want int64) {
+	t.Helper()
+	var changes int64
+	db.Model(&models.RatingHistory{}).Where("match_result_id IS NOT NULL").Count(&changes)
+	if changes != want {
+		t.Fatalf("expected %d rating changes, got %d", want, changes)
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// loadPricingRules obtiene las reglas de precio activas de una cancha ordenadas por prioridad
+func loadPricingRules(db *gorm.DB, courtID uint) ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	if err := db.Where("court_id = ? AND is_active = ?", courtID, true).Order("priority DESC, id").Find(&rules).Error; err != nil {
+		return nil, errors.New("failed to fetch pricing rules")
+	}
+	return rules, nil
+}
+
+// calculatePrice calcula el precio de un turno expresado en minutos del día operativo de date.
+// El turno se divide en los tramos entre los límites de las reglas (sus franjas horarias y la medianoche),
+// dentro de los cuales las reglas que aplican no cambian; los tramos consecutivos con el mismo precio
+// se agrupan para el detalle.
+func calculatePrice(court *models.Court, rules []models.PricingRule, date time.Time, start, end int) *models.PriceBreakdown {
+	breakdown := &models.PriceBreakdown{
+		BasePricePerHour: court.PricePerHour,
+		Segments:         make([]models.PriceSegment, 0),
+	}
+
+	// Las reglas se evalúan en orden de prioridad
+	sorted := make([]models.PricingRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	var current *models.PriceSegment
+	var currentKey string
+	segmentStart := start
+	closeSegment := func(until int) {
+		if current == nil {
+			return
+		}
+		current.EndTime = formatClock(until)
+		current.Amount = roundPrice(current.PricePerHour * float64(until-segmentStart) / 60)
+		breakdown.Segments = append(breakdown.Segments, *current)
+		breakdown.Total += current.Amount
+	}
+
+	for _, minute := range priceBoundaries(sorted, start, end) {
+		moment := time.Date(date.Year(), date.Month(), date.Day(), 0, minute, 0, 0, time.Local)
+		rate, applied := hourlyRate(court, sorted, moment)
+
+		key := strings.Join(applied, "|")
+		if current != nil && current.PricePerHour == rate && currentKey == key {
+			continue
+		}
+
+		closeSegment(minute)
+		current = &models.PriceSegment{
+			StartTime:    formatClock(minute),
+			PricePerHour: rate,
+			Rules:        applied,
+		}
+		currentKey = key
+		segmentStart = minute
+	}
+	closeSegment(end)
+
+	breakdown.Total = roundPrice(breakdown.Total)
+	return breakdown
+}
+
+// priceBoundaries devuelve, ordenados, el inicio del turno y los minutos dentro de [start, end) en los que
+// puede cambiar el conjunto de reglas que aplican: el inicio y fin de sus franjas y la medianoche, donde
+// cambian el día de la semana y la fecha de vigencia
+func priceBoundaries(rules []models.PricingRule, start, end int) []int {
+	candidates := []int{minutesPerDay}
+	for _, rule := range rules {
+		for _, clock := range []*string{rule.StartTime, rule.EndTime} {
+			if clock == nil {
+				continue
+			}
+			if minute, err := parseClock(*clock); err == nil {
+				candidates = append(candidates, minute, minute+minutesPerDay)
+			}
+		}
+	}
+
+	boundaries := []int{start}
+	for _, minute := range candidates {
+		if minute > start && minute < end {
+			boundaries = append(boundaries, minute)
+		}
+	}
+	sort.Ints(boundaries)
+
+	unique := boundaries[:1]
+	for _, minute := range boundaries[1:] {
+		if minute != unique[len(unique)-1] {
+			unique = append(unique, minute)
+		}
+	}
+	return unique
+}
+
+// hourlyRate devuelve el precio por hora vigente en un momento dado y los nombres de las reglas aplicadas.
+// Las reglas deben llegar ordenadas por prioridad. Las reglas "fixed" reemplazan el precio base (gana la de
+// mayor prioridad); luego se aplican los ajustes porcentuales y los montos fijos en orden de prioridad.
+func hourlyRate(court *models.Court, rules []models.PricingRule, moment time.Time) (float64, []string) {
+	rate := court.PricePerHour
+	var applied []string
+
+	matching := make([]models.PricingRule, 0, len(rules))
+	for _, rule := range rules {
+		if ruleApplies(court, &rule, moment) {
+			matching = append(matching, rule)
+		}
+	}
+
+	for _, rule := range matching {
+		if rule.Adjustment == "fixed" {
+			rate = rule.Value
+			applied = append(applied, rule.Name)
+			break
+		}
+	}
+	for _, rule := range matching {
+		switch rule.Adjustment {
+		case "percent":
+			rate += rate * rule.Value / 100
+			applied = append(applied, rule.Name)
+		case "amount":
+			rate += rule.Value
+			applied = append(applied, rule.Name)
+		}
+	}
+
+	if rate < 0 {
+		rate = 0
+	}
+	return roundPrice(rate), applied
+}
+
+// ruleApplies indica si una regla de precio aplica en un momento dado
+func ruleApplies(court *models.Court, rule *models.PricingRule, moment time.Time) bool {
+	if rule.RequiresLighting && !court.HasLighting {
+		return false
+	}
+
+	day := moment.Format("2006-01-02")
+	if rule.StartDate != nil && day < rule.StartDate.Format("2006-01-02") {
+		return false
+	}
+	if rule.EndDate != nil && day > rule.EndDate.Format("2006-01-02") {
+		return false
+	}
+
+	if len(rule.DaysOfWeek) > 0 {
+		found := false
+		for _, d := range rule.DaysOfWeek {
+			if d == int(moment.Weekday()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if rule.StartTime != nil && rule.EndTime != nil {
+		from, err := parseClock(*rule.StartTime)
+		if err != nil {
+			return false
+		}
+		to, err := parseClock(*rule.EndTime)
+		if err != nil {
+			return false
+		}
+		minute := moment.Hour()*60 + moment.Minute()
+		if from < to {
+			return minute >= from && minute < to
+		}
+		// Franja que cruza la medianoche (ej: 19:00 a 02:00)
+		return minute >= from || minute < to
+	}
+
+	return true
+}
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+)
+
+// minuteByMinutePrice valoriza cada minuto del turno por separado; sirve de referencia para calculatePrice
+func minuteByMinutePrice(court *models.Court, rules []models.PricingRule, date time.Time, start, end int) *models.PriceBreakdown {
+	breakdown := &models.PriceBreakdown{BasePricePerHour: court.PricePerHour, Segments: make([]models.PriceSegment, 0)}
+	for minute := start; minute < end; minute++ {
+		moment := time.Date(date.Year(), date.Month(), date.Day(), 0, minute, 0, 0, time.Local)
+		rate, applied := hourlyRate(court, rules, moment)
+		last := len(breakdown.Segments) - 1
+		if last >= 0 && breakdown.Segments[last].PricePerHour == rate && strings.Join(breakdown.Segments[last].Rules, "|") == strings.Join(applied, "|") {
+			breakdown.Segments[last].EndTime = formatClock(minute + 1)
+			continue
+		}
+		breakdown.Segments = append(breakdown.Segments, models.PriceSegment{StartTime: formatClock(minute), EndTime: formatClock(minute + 1), PricePerHour: rate, Rules: applied})
+	}
+	for i := range breakdown.Segments {
+		segment := &breakdown.Segments[i]
+		from, _ := parseClock(segment.StartTime)
+		to, _ := parseClock(segment.EndTime)
+		if to <= from {
+			to += minutesPerDay
+		}
+		segment.Amount = roundPrice(segment.PricePerHour * float64(to-from) / 60)
+		breakdown.Total += segment.Amount
+	}
+	breakdown.Total = roundPrice(breakdown.Total)
+	return breakdown
+}
+
+func TestCalculatePriceMatchesMinuteByMinutePricing(t *testing.T) {
+	clock := func(value string) *string { return &value }
+	promoStart := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	promoEnd := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	court := &models.Court{PricePerHour: 12000, HasLighting: true}
+	rules := []models.PricingRule{
+		{Name: "nocturno", Adjustment: "percent", Value: 20, StartTime: clock("19:00"), EndTime: clock("02:00"), RequiresLighting: true, Priority: 1},
+		{Name: "fin de semana", Adjustment: "fixed", Value: 15000, DaysOfWeek: []int{0, 6}, Priority: 5},
+		{Name: "promo", Adjustment: "fixed", Value: 9000, StartDate: &promoStart, EndDate: &promoEnd, Priority: 10},
+		{Name: "hora pico", Adjustment: "amount", Value: 1500, StartTime: clock("10:15"), EndTime: clock("11:45"), Priority: 2},
+	}
+	// Las reglas llegan de la base ordenadas por prioridad
+	sorted := []models.PricingRule{rules[2], rules[1], rules[3], rules[0]}
+
+	for day := 0; day < 7; day++ {
+		date := time.Date(2026, 3, 1+day, 0, 0, 0, 0, time.UTC)
+		for start := 8 * 60; start <= 26*60; start += 15 {
+			for _, duration := range []int{60, 90, 120} {
+				got := calculatePrice(court, rules, date, start, start+duration)
+				want := minuteByMinutePrice(court, sorted, date, start, start+duration)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("%s %s+%d: got %+v, want %+v", date.Format("2006-01-02"), formatClock(start), duration, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend-padel-go/internal/models"
+)
+
+// ReconcilePendingPayments consulta al proveedor los pagos que siguen pendientes después de olderThan
+// (por ejemplo, porque se perdió el webhook) y les aplica el estado informado por el mismo camino que
+// HandleWebhook. También completa los reembolsos pendientes cuyo resultado no se pudo registrar.
+// Cada diferencia encontrada queda registrada para el reporte de administración.
+func (s *PaymentService) ReconcilePendingPayments(olderThan time.Duration) (*models.ReconciliationReport, error) {
+	var payments []models.Payment
+	if err := s.db.Where("status = ? AND created_at <= ?", "pending", time.Now().Add(-olderThan)).Find(&payments).Error; err != nil {
+		return nil, errors.New("failed to fetch pending payments")
+	}
+
+	report := &models.ReconciliationReport{
+		CheckedAt:  time.Now(),
+		Checked:    len(payments),
+		Mismatches: make([]models.PaymentReconciliation, 0),
+	}
+
+	for _, payment := range payments {
+		mismatch := s.reconcilePayment(&payment)
+		if mismatch == nil {
+			continue
+		}
+		if err := s.db.Create(mismatch).Error; err != nil {
+			return nil, errors.New("failed to store reconciliation result")
+		}
+		report.Mismatches = append(report.Mismatches, *mismatch)
+	}
+
+	// Reembolsos que quedaron pendientes porque no se pudo registrar la respuesta del proveedor
+	var refunds []models.Refund
+	if err := s.db.Where("status = ? AND created_at <= ?", "pending", time.Now().Add(-olderThan)).Find(&refunds).Error; err != nil {
+		return nil, errors.New("failed to fetch pending refunds")
+	}
+	report.Checked += len(refunds)
+
+	for _, refund := range refunds {
+		mismatch := s.reconcileRefund(&refund)
+		if err := s.db.Create(mismatch).Error; err != nil {
+			return nil, errors.New("failed to store reconciliation result")
+		}
+		report.Mismatches = append(report.Mismatches, *mismatch)
+	}
+
+	return report, nil
+}
+
+// reconcileRefund completa un reembolso pendiente que el proveedor ya ejecutó. Sin el ID del reembolso en el
+// proveedor no se sabe si se devolvió el dinero: el monto sigue reservado y queda informado para revisarlo.
+func (s *PaymentService) reconcileRefund(refund *models.Refund) *models.PaymentReconciliation {
+	result := &models.PaymentReconciliation{
+		PaymentID:        refund.PaymentID,
+		BookingID:        refund.BookingID,
+		LocalStatus:      "refund_pending",
+		GatewayPaymentID: refund.ProviderRefundID,
+	}
+
+	if refund.ProviderRefundID == "" {
+		result.Resolution = "failed"
+		result.Error = fmt.Sprintf("refund %d outcome unknown, check it with %s", refund.ID, s.gateway.Name())
+		return result
+	}
+
+	result.GatewayStatus = "refund_approved"
+	if err := s.completeRefund(refund, refund.ProviderRefundID); err != nil {
+		result.Resolution = "failed"
+		result.Error = err.Error()
+		return result
+	}
+	result.Resolution = "updated"
+	return result
+}
+
+// reconcilePayment compara un pago pendiente con el proveedor. Devuelve nil si no hay diferencias
+// (el proveedor no registra pagos para la preferencia o también lo informa pendiente).
+func (s *PaymentService) reconcilePayment(payment *models.Payment) *models.PaymentReconciliation {
+	result := &models.PaymentReconciliation{
+		PaymentID:   payment.ID,
+		BookingID:   payment.BookingID,
+		LocalStatus: payment.Status,
+	}
+
+	// El proveedor identifica el cobro por la referencia externa (nuestro ID de pago)
+	gatewayPayments, err := s.gateway.SearchPayments(payment.ID)
+	if err != nil {
+		result.Resolution = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	// Con varios intentos de pago sobre la misma preferencia se toma el más avanzado
+	var latest *GatewayPayment
+	for i := range gatewayPayments {
+		if payment.PreferenceID != "" && gatewayPayments[i].PreferenceID != "" && gatewayPayments[i].PreferenceID != payment.PreferenceID {
+			continue
+		}
+		if latest == nil || paymentStatusRank(s.mapMercadoPagoStatus(gatewayPayments[i].Status)) > paymentStatusRank(s.mapMercadoPagoStatus(latest.Status)) {
+			latest = &gatewayPayments[i]
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	status := s.mapMercadoPagoStatus(latest.Status)
+	if status == payment.Status {
+		return nil
+	}
+
+	result.GatewayStatus = status
+	result.GatewayPaymentID = latest.ID
+
+	applied, err := s.syncGatewayPayment(latest.ID)
+	switch {
+	case err != nil:
+		result.Resolution = "failed"
+		result.Error = err.Error()
+	case !applied:
+		result.Resolution = "ignored"
+	default:
+		result.Resolution = "updated"
+	}
+	return result
+}
+
+// GetReconciliationReport lista las diferencias registradas por la conciliación desde la fecha indicada
+func (s *PaymentService) GetReconciliationReport(since time.Time) ([]models.PaymentReconciliation, error) {
+	var mismatches []models.PaymentReconciliation
+	if err := s.db.Where("created_at >= ?", since).Order("created_at DESC").Find(&mismatches).Error; err != nil {
+		return nil, errors.New("failed to fetch reconciliation report")
+	}
+	return mismatches, nil
+}
+
+// StartReconciler ejecuta ReconcilePendingPayments periódicamente en segundo plano
+func (s *PaymentService) StartReconciler(interval, olderThan time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, err := s.ReconcilePendingPayments(olderThan)
+			if err != nil {
+				log.Printf("Payment reconciler: %v", err)
+				continue
+			}
+			if len(report.Mismatches) > 0 {
+				log.Printf("Payment reconciler: %d of %d pending payments differed from %s", len(report.Mismatches), report.Checked, s.gateway.Name())
+			}
+		}
+	}()
+}
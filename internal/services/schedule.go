@@ -0,0 +1,365 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultSlotDurations se usa para las canchas que no configuraron duraciones propias
+var defaultSlotDurations = []int{60, 90, 120}
+
+// slotConfig agrupa la configuración de turnos de una cancha
+type slotConfig struct {
+	Durations     []int // duraciones permitidas en minutos
+	StepMinutes   int   // intervalo entre horarios de inicio
+	BufferMinutes int   // margen libre entre reservas consecutivas
+}
+
+// courtSlotConfig resuelve la configuración de turnos de una cancha aplicando los valores por defecto
+func courtSlotConfig(court *models.Court) slotConfig {
+	cfg := config.Load()
+
+	slots := slotConfig{Durations: court.SlotDurations}
+	if court.SlotStepMinutes != nil {
+		slots.StepMinutes = *court.SlotStepMinutes
+	}
+	if court.BufferMinutes != nil {
+		slots.BufferMinutes = *court.BufferMinutes
+	}
+	// Las canchas de un club sin duraciones, intervalo o margen propios usan los del club; un margen propio en 0 no se hereda
+	if court.Club != nil {
+		if len(slots.Durations) == 0 {
+			slots.Durations = court.Club.SlotDurations
+		}
+		if court.SlotStepMinutes == nil {
+			slots.StepMinutes = court.Club.SlotStepMinutes
+		}
+		if court.BufferMinutes == nil {
+			slots.BufferMinutes = court.Club.BufferMinutes
+		}
+	}
+	if len(slots.Durations) == 0 {
+		slots.Durations = defaultSlotDurations
+	}
+	if slots.StepMinutes <= 0 {
+		slots.StepMinutes = cfg.Booking.SlotGranularityMinutes
+	}
+	if slots.StepMinutes <= 0 {
+		slots.StepMinutes = 30
+	}
+	return slots
+}
+
+// allowsDuration indica si la duración (en minutos) está permitida para la cancha
+func (c slotConfig) allowsDuration(duration int) bool {
+	for _, d := range c.Durations {
+		if d == duration {
+			return true
+		}
+	}
+	return false
+}
+
+// minutesPerDay se usa para representar horarios que cruzan la medianoche
+const minutesPerDay = 24 * 60
+
+// timeWindow es una franja de apertura en minutos desde la medianoche del día operativo.
+// Close supera minutesPerDay cuando la franja cierra después de la medianoche (ej: 18:00 a 02:00).
+type timeWindow struct {
+	Open  int
+	Close int
+}
+
+// newTimeWindow construye una franja a partir de horarios "15:04"; un cierre anterior o igual a la apertura cruza la medianoche
+func newTimeWindow(openTime, closeTime string) (timeWindow, error) {
+	open, err := parseClock(openTime)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	close, err := parseClock(closeTime)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	if close <= open {
+		close += minutesPerDay
+	}
+	return timeWindow{Open: open, Close: close}, nil
+}
+
+func (w timeWindow) overlaps(other timeWindow) bool {
+	return w.Open < other.Close && other.Open < w.Close
+}
+
+// daySchedule es el horario efectivo de una cancha para una fecha concreta
+type daySchedule struct {
+	IsClosed bool
+	Reason   string
+	Windows  []timeWindow
+}
+
+// resolveDaySchedule combina las franjas habituales del día de la semana con el horario especial de la fecha
+func resolveDaySchedule(db *gorm.DB, courtID uint, date time.Time) (*daySchedule, error) {
+	// Los horarios especiales (feriados, eventos) tienen prioridad sobre el horario habitual
+	specialHour, err := findSpecialHour(db, courtID, date)
+	if err != nil {
+		return nil, err
+	}
+	if specialHour != nil && specialHour.IsClosed {
+		return &daySchedule{IsClosed: true, Reason: specialHour.Reason}, nil
+	}
+
+	// Obtener las franjas de atención para el día de la semana
+	var businessHours []models.BusinessHour
+	if err := db.Where("court_id = ? AND day_of_week = ?", courtID, int(date.Weekday())).Order("open_time").Find(&businessHours).Error; err != nil {
+		return nil, errors.New("failed to fetch business hours")
+	}
+	if len(businessHours) == 0 {
+		businessHours, err = inheritedClubHours(db, courtID, date.Weekday())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schedule := &daySchedule{}
+	for _, bh := range businessHours {
+		window, err := newTimeWindow(bh.OpenTime, bh.CloseTime)
+		if err != nil {
+			return nil, errors.New("invalid business hours format")
+		}
+		schedule.Windows = append(schedule.Windows, window)
+	}
+
+	if specialHour != nil {
+		if err := applySpecialHour(schedule, specialHour); err != nil {
+			return nil, err
+		}
+		schedule.Reason = specialHour.Reason
+	}
+
+	if len(schedule.Windows) == 0 {
+		return &daySchedule{IsClosed: true, Reason: "no business hours for this day"}, nil
+	}
+	return schedule, nil
+}
+
+// inheritedClubHours devuelve las franjas del club para el día de la semana cuando la cancha pertenece a un
+// club y no tiene horario semanal propio; una cancha con horario propio cerrada ese día sigue cerrada
+func inheritedClubHours(db *gorm.DB, courtID uint, weekday time.Weekday) ([]models.BusinessHour, error) {
+	var ownHours int64
+	if err := db.Model(&models.BusinessHour{}).Where("court_id = ?", courtID).Count(&ownHours).Error; err != nil {
+		return nil, errors.New("failed to fetch business hours")
+	}
+	if ownHours > 0 {
+		return nil, nil
+	}
+
+	var court models.Court
+	if err := db.Select("id", "club_id").First(&court, courtID).Error; err != nil {
+		return nil, errors.New("failed to fetch court")
+	}
+	if court.ClubID == nil {
+		return nil, nil
+	}
+
+	var clubHours []models.ClubBusinessHour
+	if err := db.Where("club_id = ? AND day_of_week = ?", *court.ClubID, int(weekday)).Order("open_time").Find(&clubHours).Error; err != nil {
+		return nil, errors.New("failed to fetch business hours")
+	}
+
+	businessHours := make([]models.BusinessHour, 0, len(clubHours))
+	for _, bh := range clubHours {
+		businessHours = append(businessHours, models.BusinessHour{
+			CourtID:   courtID,
+			DayOfWeek: bh.DayOfWeek,
+			OpenTime:  bh.OpenTime,
+			CloseTime: bh.CloseTime,
+		})
+	}
+	return businessHours, nil
+}
+
+// findSpecialHour obtiene el horario especial de una cancha para una fecha, o nil si no existe
+func findSpecialHour(db *gorm.DB, courtID uint, date time.Time) (*models.SpecialHour, error) {
+	var specialHour models.SpecialHour
+	if err := db.Where("court_id = ? AND date = ?", courtID, date.Format("2006-01-02")).Order("id DESC").First(&specialHour).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New("failed to fetch special hours")
+	}
+	return &specialHour, nil
+}
+
+// applySpecialHour ajusta las franjas del día con el horario especial: con apertura y cierre definidos
+// reemplaza el día completo por esa franja; con solo uno de ellos adelanta/atrasa la primera apertura o el último cierre
+func applySpecialHour(schedule *daySchedule, specialHour *models.SpecialHour) error {
+	hasOpen := specialHour.OpenTime != nil && *specialHour.OpenTime != ""
+	hasClose := specialHour.CloseTime != nil && *specialHour.CloseTime != ""
+
+	if hasOpen && hasClose {
+		window, err := newTimeWindow(*specialHour.OpenTime, *specialHour.CloseTime)
+		if err != nil {
+			return errors.New("invalid special hours format")
+		}
+		schedule.Windows = []timeWindow{window}
+		return nil
+	}
+	if len(schedule.Windows) == 0 {
+		return nil
+	}
+	if hasOpen {
+		open, err := parseClock(*specialHour.OpenTime)
+		if err != nil {
+			return errors.New("invalid special hours format")
+		}
+		schedule.Windows[0].Open = open
+	}
+	if hasClose {
+		close, err := parseClock(*specialHour.CloseTime)
+		if err != nil {
+			return errors.New("invalid special hours format")
+		}
+		last := &schedule.Windows[len(schedule.Windows)-1]
+		if close <= last.Open {
+			close += minutesPerDay
+		}
+		last.Close = close
+	}
+	return nil
+}
+
+// toOperational convierte un rango "15:04" en minutos del día operativo. Un inicio posterior a la medianoche
+// que solo cae dentro de una franja nocturna se ubica en el día siguiente, y un fin anterior al inicio cruza la medianoche.
+func (d *daySchedule) toOperational(startTime, endTime string) (int, int, error) {
+	start, err := parseClock(startTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(endTime)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if d != nil && !d.startsWithin(start) && d.startsWithin(start+minutesPerDay) {
+		start += minutesPerDay
+		end += minutesPerDay
+	}
+	for end <= start {
+		end += minutesPerDay
+	}
+	return start, end, nil
+}
+
+func (d *daySchedule) startsWithin(minute int) bool {
+	for _, w := range d.Windows {
+		if minute >= w.Open && minute < w.Close {
+			return true
+		}
+	}
+	return false
+}
+
+// alignedStart indica si el inicio operativo coincide con el intervalo de la cancha, contado desde la apertura
+// de la franja que lo contiene (los mismos horarios que ofrece la disponibilidad)
+func (d *daySchedule) alignedStart(start, step int) bool {
+	for _, w := range d.Windows {
+		if start >= w.Open && start < w.Close {
+			return (start-w.Open)%step == 0
+		}
+	}
+	return false
+}
+
+// contains indica si el rango operativo [start, end] cae completamente dentro de alguna franja
+func (d *daySchedule) contains(start, end int) bool {
+	for _, w := range d.Windows {
+		if start >= w.Open && end <= w.Close {
+			return true
+		}
+	}
+	return false
+}
+
+// openingWindows expone las franjas del día en formato "15:04"
+func (d *daySchedule) openingWindows() []models.OpeningWindow {
+	windows := make([]models.OpeningWindow, 0, len(d.Windows))
+	for _, w := range d.Windows {
+		windows = append(windows, models.OpeningWindow{
+			OpenTime:  formatClock(w.Open),
+			CloseTime: formatClock(w.Close),
+		})
+	}
+	return windows
+}
+
+// validateWeeklyWindows verifica que las franjas de la semana no se superpongan, incluyendo la parte
+// de las franjas nocturnas que continúa en la madrugada del día siguiente
+func validateWeeklyWindows(businessHours []models.BusinessHourRequest) error {
+	windowsByDay := make(map[int][]timeWindow)
+	for _, bh := range businessHours {
+		window, err := newTimeWindow(bh.OpenTime, bh.CloseTime)
+		if err != nil {
+			return errors.New("invalid business hours format")
+		}
+		windowsByDay[bh.DayOfWeek] = append(windowsByDay[bh.DayOfWeek], window)
+	}
+
+	for day, windows := range windowsByDay {
+		for i := range windows {
+			for j := i + 1; j < len(windows); j++ {
+				if windows[i].overlaps(windows[j]) {
+					return fmt.Errorf("overlapping business hours for day %d", day)
+				}
+			}
+
+			// La madrugada de una franja nocturna no puede pisar las franjas del día siguiente
+			if windows[i].Close > minutesPerDay {
+				spill := timeWindow{Open: minutesPerDay, Close: windows[i].Close}
+				for _, next := range windowsByDay[(day+1)%7] {
+					shifted := timeWindow{Open: next.Open + minutesPerDay, Close: next.Close + minutesPerDay}
+					if spill.overlaps(shifted) {
+						return fmt.Errorf("overlapping business hours for day %d", (day+1)%7)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock convierte un horario "15:04" en minutos desde la medianoche
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatClock convierte minutos operativos en un horario "15:04", volviendo a 00:00 después de la medianoche
+func formatClock(minutes int) string {
+	minutes = minutes % minutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// rangesOverlap indica si dos rangos en minutos se superponen considerando un margen libre entre ellos
+func rangesOverlap(aStart, aEnd, bStart, bEnd, bufferMinutes int) bool {
+	return aStart < bEnd+bufferMinutes && bStart < aEnd+bufferMinutes
+}
+
+// overlapsWithBuffer indica si dos rangos horarios del mismo día operativo se superponen considerando un margen libre entre ellos
+func overlapsWithBuffer(schedule *daySchedule, startA, endA, startB, endB string, bufferMinutes int) bool {
+	aStart, aEnd, errA := schedule.toOperational(startA, endA)
+	bStart, bEnd, errB := schedule.toOperational(startB, endB)
+	if errA != nil || errB != nil {
+		// Ante horarios inválidos se asume superposición para no liberar turnos por error
+		return true
+	}
+	return rangesOverlap(aStart, aEnd, bStart, bEnd, bufferMinutes)
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+func TestCourtSlotConfigUsesConfiguredGranularity(t *testing.T) {
+	t.Setenv("BOOKING_SLOT_GRANULARITY", "15")
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	court := testutil.CreateCourt(t, db, owner.ID)
+
+	// Una cancha sin intervalo propio lo guarda vacío y usa el configurado
+	var stored models.Court
+	if err := db.First(&stored, court.ID).Error; err != nil {
+		t.Fatalf("fetch court: %v", err)
+	}
+	if stored.SlotStepMinutes != nil {
+		t.Fatalf("expected no stored slot step, got %d", *stored.SlotStepMinutes)
+	}
+	if step := courtSlotConfig(&stored).StepMinutes; step != 15 {
+		t.Fatalf("expected the configured granularity of 15 minutes, got %d", step)
+	}
+
+	step := 60
+	stored.SlotStepMinutes = &step
+	if step := courtSlotConfig(&stored).StepMinutes; step != 60 {
+		t.Fatalf("expected the court's own slot step of 60 minutes, got %d", step)
+	}
+}
+
+func TestClubCourtResolvesSlotStepAndBufferFromClub(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	courtService := NewCourtService(db)
+	clubService := NewClubService(db, courtService)
+
+	club, err := clubService.CreateClub(owner.ID, &models.CreateClubRequest{
+		Name:            "Club",
+		Address:         "Calle 1",
+		Latitude:        -34.6,
+		Longitude:       -58.4,
+		SlotStepMinutes: 45,
+		BufferMinutes:   10,
+		BusinessHours:   []models.BusinessHourRequest{{DayOfWeek: 1, OpenTime: "08:00", CloseTime: "22:00"}},
+	})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+	court, err := courtService.CreateCourt(owner.ID, &models.CreateCourtRequest{Name: "Cancha 1", PricePerHour: 10000, ClubID: &club.ID})
+	if err != nil {
+		t.Fatalf("create court: %v", err)
+	}
+
+	// La cancha no copia el intervalo ni el margen, así que los cambios del club se aplican sin tocarla
+	var stored models.Court
+	if err := db.Preload("Club").First(&stored, court.ID).Error; err != nil {
+		t.Fatalf("fetch court: %v", err)
+	}
+	if stored.SlotStepMinutes != nil || stored.BufferMinutes != nil {
+		t.Fatalf("expected no stored slot step or buffer, got %v and %v", stored.SlotStepMinutes, stored.BufferMinutes)
+	}
+	db.Model(&models.Club{}).Where("id = ?", club.ID).Updates(map[string]interface{}{"slot_step_minutes": 60, "buffer_minutes": 15})
+	if err := db.Preload("Club").First(&stored, court.ID).Error; err != nil {
+		t.Fatalf("refetch court: %v", err)
+	}
+	if slots := courtSlotConfig(&stored); slots.StepMinutes != 60 || slots.BufferMinutes != 15 {
+		t.Fatalf("expected the club's slot step of 60 and buffer of 15, got %d and %d", slots.StepMinutes, slots.BufferMinutes)
+	}
+
+	// Un margen propio en 0 pisa el del club y -1 lo vuelve a heredar
+	step, noBuffer := 30, 0
+	updated, err := courtService.UpdateCourt(court.ID, owner.ID, &models.UpdateCourtRequest{SlotStepMinutes: &step, BufferMinutes: &noBuffer})
+	if err != nil {
+		t.Fatalf("update court: %v", err)
+	}
+	if slots := courtSlotConfig(updated); slots.StepMinutes != 30 || slots.BufferMinutes != 0 {
+		t.Fatalf("expected the court's slot step of 30 and no buffer, got %d and %d", slots.StepMinutes, slots.BufferMinutes)
+	}
+	inherit := -1
+	updated, err = courtService.UpdateCourt(court.ID, owner.ID, &models.UpdateCourtRequest{SlotStepMinutes: &inherit, BufferMinutes: &inherit})
+	if err != nil {
+		t.Fatalf("update court: %v", err)
+	}
+	if updated.SlotStepMinutes != nil || updated.BufferMinutes != nil {
+		t.Fatalf("expected the overrides to be cleared, got %v and %v", updated.SlotStepMinutes, updated.BufferMinutes)
+	}
+	if slots := courtSlotConfig(updated); slots.StepMinutes != 60 || slots.BufferMinutes != 15 {
+		t.Fatalf("expected the club's slot step of 60 and buffer of 15 again, got %d and %d", slots.StepMinutes, slots.BufferMinutes)
+	}
+}
+
+func TestClubCourtResolvesLocationAndAmenitiesFromClub(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	courtService := NewCourtService(db)
+	clubService := NewClubService(db, courtService)
+
+	club, err := clubService.CreateClub(owner.ID, &models.CreateClubRequest{
+		Name:          "Club",
+		Address:       "Calle 1",
+		Latitude:      -34.6,
+		Longitude:     -58.4,
+		Amenities:     []string{"vestuarios", "bar"},
+		BusinessHours: []models.BusinessHourRequest{{DayOfWeek: 1, OpenTime: "08:00", CloseTime: "22:00"}},
+	})
+	if err != nil {
+		t.Fatalf("create club: %v", err)
+	}
+	court, err := courtService.CreateCourt(owner.ID, &models.CreateCourtRequest{
+		Name:         "Cancha 1",
+		PricePerHour: 10000,
+		Amenities:    []string{"bar", "techada"},
+		ClubID:       &club.ID,
+	})
+	if err != nil {
+		t.Fatalf("create court: %v", err)
+	}
+	if court.Address != "Calle 1" || !reflect.DeepEqual(court.Amenities, []string{"vestuarios", "bar", "techada"}) {
+		t.Fatalf("expected the club's address and merged amenities, got %q and %v", court.Address, court.Amenities)
+	}
+
+	// La dirección nueva del club llega a la cancha sin tocarla
+	address, latitude, longitude := "Calle 2", -31.4, -64.2
+	if _, err := clubService.UpdateClub(club.ID, owner.ID, &models.UpdateClubRequest{Address: &address, Latitude: &latitude, Longitude: &longitude}); err != nil {
+		t.Fatalf("update club: %v", err)
+	}
+	stored, err := courtService.GetCourtByID(court.ID)
+	if err != nil {
+		t.Fatalf("fetch court: %v", err)
+	}
+	if stored.Address != address || stored.Latitude != latitude || stored.Longitude != longitude {
+		t.Fatalf("expected the club's new location, got %q (%v, %v)", stored.Address, stored.Latitude, stored.Longitude)
+	}
+
+	booking := testutil.CreateBooking(t, db, court.ID, owner.ID, testutil.Day(3), "10:00", "11:00", "confirmed", nil)
+	response, err := NewBookingService(db, NewPaymentService(db, &fakeGateway{})).GetBookingByID(booking.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("fetch booking: %v", err)
+	}
+	if response.Court.Address != address {
+		t.Fatalf("expected the booking to show the club's address, got %q", response.Court.Address)
+	}
+}
+
+func TestOfferedStartTimesCanBeBooked(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	courtService := NewCourtService(db)
+	bookingService := NewBookingService(db, NewPaymentService(db, &fakeGateway{}))
+	date := testutil.Day(3)
+
+	// Un intervalo de 45 minutos desde las 08:00 y una franja de la tarde que reabre a las 16:30
+	db.Model(&court).Update("slot_step_minutes", 45)
+	db.Where("court_id = ? AND day_of_week = ?", court.ID, int(date.Weekday())).Delete(&models.BusinessHour{})
+	db.Create(&models.BusinessHour{CourtID: court.ID, DayOfWeek: int(date.Weekday()), OpenTime: "08:00", CloseTime: "12:00"})
+	db.Create(&models.BusinessHour{CourtID: court.ID, DayOfWeek: int(date.Weekday()), OpenTime: "16:30", CloseTime: "23:00"})
+
+	availability, err := courtService.GetAvailability(court.ID, date, 60)
+	if err != nil {
+		t.Fatalf("availability: %v", err)
+	}
+	offered := map[string]bool{}
+	for _, slot := range availability.Slots {
+		offered[slot.StartTime] = true
+	}
+	for _, start := range []string{"08:00", "08:45", "16:30", "17:15"} {
+		if !offered[start] {
+			t.Fatalf("expected %s to be offered, got %+v", start, offered)
+		}
+	}
+
+	stored, err := courtService.GetCourtByID(court.ID)
+	if err != nil {
+		t.Fatalf("fetch court: %v", err)
+	}
+	for _, slot := range availability.Slots {
+		if _, err := bookingService.validateBookingRules(db, stored, date, slot.StartTime, slot.EndTime, false); err != nil {
+			t.Fatalf("expected the offered slot %s-%s to be bookable: %v", slot.StartTime, slot.EndTime, err)
+		}
+	}
+	if _, err := bookingService.validateBookingRules(db, stored, date, "17:00", "18:00", false); err != ErrBookingGranularity {
+		t.Fatalf("expected a start off the afternoon step to be rejected, got %v", err)
+	}
+}
+
+func TestOvernightWindowCrossesMidnight(t *testing.T) {
+	morning, _ := newTimeWindow("08:00", "12:00")
+	night, _ := newTimeWindow("18:00", "02:00")
+	schedule := &daySchedule{Windows: []timeWindow{morning, night}}
+
+	tests := []struct {
+		name       string
+		start, end string
+		wantStart  int
+		wantEnd    int
+		contained  bool
+	}{
+		{"before midnight", "22:00", "23:30", 22 * 60, 23*60 + 30, true},
+		{"crossing midnight", "23:30", "01:00", 23*60 + 30, 25 * 60, true},
+		{"after midnight", "00:30", "02:00", 24*60 + 30, 26 * 60, true},
+		{"past the close", "01:30", "03:00", 25*60 + 30, 27 * 60, false},
+		{"morning window", "09:00", "10:30", 9 * 60, 10*60 + 30, true},
+		{"between windows", "17:00", "18:30", 17 * 60, 18*60 + 30, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := schedule.toOperational(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("toOperational: %v", err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Fatalf("expected %d-%d, got %d-%d", tt.wantStart, tt.wantEnd, start, end)
+			}
+			if got := schedule.contains(start, end); got != tt.contained {
+				t.Fatalf("expected contained=%v, got %v", tt.contained, got)
+			}
+		})
+	}
+}
+
+func TestSpecialHourOverridesSplitDay(t *testing.T) {
+	clock := func(value string) *string { return &value }
+	tests := []struct {
+		name     string
+		special  models.SpecialHour
+		closed   bool
+		expected []timeWindow
+	}{
+		{"closed", models.SpecialHour{IsClosed: true}, true, nil},
+		{"open and close", models.SpecialHour{OpenTime: clock("10:00"), CloseTime: clock("14:00")}, false, []timeWindow{{600, 840}}},
+		{"late open", models.SpecialHour{OpenTime: clock("09:30")}, false, []timeWindow{{570, 720}, {960, 1380}}},
+		{"early close", models.SpecialHour{CloseTime: clock("20:00")}, false, []timeWindow{{480, 720}, {960, 1200}}},
+		{"close after midnight", models.SpecialHour{CloseTime: clock("01:00")}, false, []timeWindow{{480, 720}, {960, 1500}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.NewDB(t)
+			owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+			court := testutil.CreateCourt(t, db, owner.ID)
+			date := testutil.Day(3)
+
+			// Día partido: 08:00 a 12:00 y 16:00 a 23:00
+			db.Where("court_id = ? AND day_of_week = ?", court.ID, int(date.Weekday())).Delete(&models.BusinessHour{})
+			db.Create(&models.BusinessHour{CourtID: court.ID, DayOfWeek: int(date.Weekday()), OpenTime: "08:00", CloseTime: "12:00"})
+			db.Create(&models.BusinessHour{CourtID: court.ID, DayOfWeek: int(date.Weekday()), OpenTime: "16:00", CloseTime: "23:00"})
+			special := tt.special
+			special.CourtID = court.ID
+			special.Date = date
+			special.Reason = "feriado"
+			if err := db.Omit("Court").Create(&special).Error; err != nil {
+				t.Fatalf("create special hour: %v", err)
+			}
+
+			schedule, err := resolveDaySchedule(db, court.ID, date)
+			if err != nil {
+				t.Fatalf("resolve schedule: %v", err)
+			}
+			if schedule.IsClosed != tt.closed || schedule.Reason != "feriado" {
+				t.Fatalf("expected closed=%v with the special reason, got %+v", tt.closed, schedule)
+			}
+			if !reflect.DeepEqual(schedule.Windows, tt.expected) {
+				t.Fatalf("expected windows %+v, got %+v", tt.expected, schedule.Windows)
+			}
+		})
+	}
+}
@@ -0,0 +1,970 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxTournamentDays limita cuántos días desde el inicio se buscan turnos libres para programar el cuadro
+	maxTournamentDays = 14
+	// tournamentSlotStep es el paso en minutos con que se prueban los horarios de inicio de los partidos
+	tournamentSlotStep = 30
+)
+
+// ErrTournamentUnschedulable indica que las canchas del torneo no tienen turnos libres suficientes para todos los partidos
+var ErrTournamentUnschedulable = errors.New("not enough court availability to schedule the tournament")
+
+type TournamentService struct {
+	db             *gorm.DB
+	bookingService *BookingService
+}
+
+func NewTournamentService(db *gorm.DB, bookingService *BookingService) *TournamentService {
+	return &TournamentService{db: db, bookingService: bookingService}
+}
+
+// CreateTournament crea un torneo en canchas del propietario; queda abierto a inscripciones hasta generar el cuadro
+func (s *TournamentService) CreateTournament(ownerID uint, req *models.CreateTournamentRequest) (*models.Tournament, error) {
+	if req.Format != "elimination" && req.Format != "groups" && req.Format != "americano" && req.Format != "mexicano" {
+		return nil, errors.New("invalid tournament format")
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format")
+	}
+	if startDate.Format("2006-01-02") < time.Now().Format("2006-01-02") {
+		return nil, errors.New("start date is in the past")
+	}
+
+	dayStart, err := parseClock(req.DayStartTime)
+	if err != nil {
+		return nil, errors.New("invalid daily time window")
+	}
+	dayEnd, err := parseClock(req.DayEndTime)
+	if err != nil || dayEnd <= dayStart {
+		return nil, errors.New("invalid daily time window")
+	}
+	matchMinutes := req.MatchMinutes
+	if matchMinutes == 0 {
+		matchMinutes = 90
+	}
+	if matchMinutes < 0 || matchMinutes > dayEnd-dayStart {
+		return nil, errors.New("invalid match duration")
+	}
+
+	groupCount := 1
+	if isIndividualFormat(req.Format) {
+		if req.MaxEntries < 4 || req.MaxEntries%4 != 0 {
+			return nil, errors.New("americano and mexicano need a multiple of 4 players")
+		}
+	} else if req.MaxEntries < 2 {
+		return nil, errors.New("tournament needs at least 2 pairs")
+	}
+	if req.Format == "groups" && req.GroupCount > 0 {
+		groupCount = req.GroupCount
+	}
+	if req.MaxEntries < groupCount*2 {
+		return nil, errors.New("each group needs at least 2 pairs")
+	}
+	if req.Rounds < 0 || (req.Format == "americano" && req.Rounds > req.MaxEntries-1) {
+		return nil, errors.New("invalid number of rounds")
+	}
+
+	courtIDs := uniqueIDs(req.CourtIDs)
+	if len(courtIDs) == 0 {
+		return nil, errors.New("at least one court is required")
+	}
+	var courts []models.Court
+	if err := s.db.Where("id IN ? AND owner_id = ? AND is_active = ?", courtIDs, ownerID, true).Preload("Club").Find(&courts).Error; err != nil {
+		return nil, errors.New("failed to fetch courts")
+	}
+	if len(courts) != len(courtIDs) {
+		return nil, errors.New("court not found")
+	}
+	for i := range courts {
+		if err := validateTournamentCourt(s.db, &courts[i], startDate, dayStart, dayEnd, matchMinutes); err != nil {
+			return nil, err
+		}
+	}
+
+	tournament := models.Tournament{
+		OwnerID:      ownerID,
+		Name:         req.Name,
+		Description:  req.Description,
+		Format:       req.Format,
+		Status:       "registration",
+		StartDate:    startDate,
+		DayStartTime: req.DayStartTime,
+		DayEndTime:   req.DayEndTime,
+		MatchMinutes: matchMinutes,
+		MaxEntries:   req.MaxEntries,
+		GroupCount:   groupCount,
+		Rounds:       req.Rounds,
+		Courts:       courts,
+	}
+	// Solo se registra la relación con las canchas existentes
+	if err := s.db.Omit("Courts.*").Create(&tournament).Error; err != nil {
+		return nil, errors.New("failed to create tournament")
+	}
+
+	return &tournament, nil
+}
+
+// validateTournamentCourt verifica que la cancha admita turnos de la duración de los partidos y que, el día de
+// inicio, al menos un partido entre en su horario dentro de la franja diaria del torneo
+func validateTournamentCourt(db *gorm.DB, court *models.Court, date time.Time, dayStart, dayEnd, matchMinutes int) error {
+	slots := courtSlotConfig(court)
+	if !slots.allowsDuration(matchMinutes) {
+		return fmt.Errorf("match duration not allowed for court %s", court.Name)
+	}
+
+	schedule, err := resolveDaySchedule(db, court.ID, date)
+	if err != nil {
+		return err
+	}
+	if !schedule.IsClosed {
+		// Se prueban los mismos horarios de inicio que usa la programación del cuadro
+		for minute := dayStart; minute+matchMinutes <= dayEnd; minute += tournamentSlotStep {
+			if schedule.contains(minute, minute+matchMinutes) && schedule.alignedStart(minute, slots.StepMinutes) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("court %s has no slot for the matches within the tournament hours on the start date", court.Name)
+}
+
+// GetTournaments lista los torneos que no fueron cancelados, con filtros por estado y formato
+func (s *TournamentService) GetTournaments(filters *models.GetTournamentsRequest) ([]models.Tournament, error) {
+	query := s.db.Model(&models.Tournament{}).Preload("Courts")
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	} else {
+		query = query.Where("status <> ?", "cancelled")
+	}
+	if filters.Format != nil {
+		query = query.Where("format = ?", *filters.Format)
+	}
+
+	var tournaments []models.Tournament
+	if err := query.Order("start_date").Find(&tournaments).Error; err != nil {
+		return nil, errors.New("failed to fetch tournaments")
+	}
+	return tournaments, nil
+}
+
+// GetOwnerTournaments lista los torneos del propietario
+func (s *TournamentService) GetOwnerTournaments(ownerID uint) ([]models.Tournament, error) {
+	var tournaments []models.Tournament
+	err := s.db.Where("owner_id = ?", ownerID).Preload("Courts").Preload("Pairs").Order("start_date DESC").Find(&tournaments).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch tournaments")
+	}
+	return tournaments, nil
+}
+
+// GetTournamentByID obtiene un torneo con sus canchas y parejas inscriptas
+func (s *TournamentService) GetTournamentByID(id uint) (*models.Tournament, error) {
+	var tournament models.Tournament
+	if err := s.db.Preload("Courts").Preload("Pairs").First(&tournament, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tournament not found")
+		}
+		return nil, errors.New("failed to fetch tournament")
+	}
+	return &tournament, nil
+}
+
+// RegisterPair inscribe al usuario con su compañero; en americano y mexicano la inscripción es individual
+func (s *TournamentService) RegisterPair(tournamentID uint, userID uint, req *models.RegisterTournamentRequest) (*models.TournamentPair, error) {
+	var pair models.TournamentPair
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Bloquear el torneo para no superar el cupo con inscripciones simultáneas
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, tournamentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tournament not found")
+			}
+			return errors.New("failed to fetch tournament")
+		}
+		if tournament.Status != "registration" {
+			return errors.New("tournament registration is closed")
+		}
+
+		individual := isIndividualFormat(tournament.Format)
+		if individual && req.PartnerID != nil {
+			return errors.New("this format registers individual players")
+		}
+		if !individual && (req.PartnerID == nil || *req.PartnerID == userID) {
+			return errors.New("a partner is required")
+		}
+
+		playerIDs := []uint{userID}
+		if req.PartnerID != nil {
+			playerIDs = append(playerIDs, *req.PartnerID)
+		}
+		var players []models.User
+		if err := tx.Where("id IN ? AND is_active = ?", playerIDs, true).Find(&players).Error; err != nil {
+			return errors.New("failed to fetch users")
+		}
+		if len(players) != len(playerIDs) {
+			return errors.New("partner not found")
+		}
+
+		var registered int64
+		if err := tx.Model(&models.TournamentPair{}).
+			Where("tournament_id = ? AND (player1_id IN ? OR player2_id IN ?)", tournament.ID, playerIDs, playerIDs).
+			Count(&registered).Error; err != nil {
+			return errors.New("failed to fetch tournament pairs")
+		}
+		if registered > 0 {
+			return errors.New("player already registered")
+		}
+
+		var entries int64
+		if err := tx.Model(&models.TournamentPair{}).Where("tournament_id = ?", tournament.ID).Count(&entries).Error; err != nil {
+			return errors.New("failed to fetch tournament pairs")
+		}
+		if int(entries) >= tournament.MaxEntries {
+			return errors.New("tournament is full")
+		}
+
+		names := make([]string, 0, len(players))
+		for _, id := range playerIDs {
+			for _, player := range players {
+				if player.ID == id {
+					names = append(names, strings.TrimSpace(player.FirstName+" "+player.LastName))
+				}
+			}
+		}
+		pair = models.TournamentPair{
+			TournamentID: tournament.ID,
+			Player1ID:    userID,
+			Player2ID:    req.PartnerID,
+			Name:         strings.Join(names, " / "),
+		}
+		if err := tx.Create(&pair).Error; err != nil {
+			return errors.New("failed to register in tournament")
+		}
+
+		if req.PartnerID != nil {
+			message := fmt.Sprintf("%s registered you as partner in the tournament %s.", names[0], tournament.Name)
+			return notifyUser(tx, *req.PartnerID, "tournament_registration", "You were registered in a tournament", message, "tournament", &tournament.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pair, nil
+}
+
+// WithdrawPair da de baja la inscripción del usuario (y de su compañero) mientras la inscripción siga abierta
+func (s *TournamentService) WithdrawPair(tournamentID uint, userID uint) error {
+	var tournament models.Tournament
+	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("tournament not found")
+		}
+		return errors.New("failed to fetch tournament")
+	}
+	if tournament.Status != "registration" {
+		return errors.New("tournament registration is closed")
+	}
+
+	result := s.db.Where("tournament_id = ? AND (player1_id = ? OR player2_id = ?)", tournament.ID, userID, userID).Delete(&models.TournamentPair{})
+	if result.Error != nil {
+		return errors.New("failed to withdraw from tournament")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("registration not found")
+	}
+	return nil
+}
+
+// GenerateTournament cierra la inscripción, arma el cuadro según el formato y reserva un turno para cada partido.
+// Si las canchas no tienen lugar para todos los partidos no se reserva ni se genera nada.
+func (s *TournamentService) GenerateTournament(tournamentID uint, ownerID uint) (*models.TournamentBracketResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", tournamentID, ownerID).
+			Preload("Courts").Preload("Pairs").
+			First(&tournament).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tournament not found")
+			}
+			return errors.New("failed to fetch tournament")
+		}
+		if tournament.Status != "registration" {
+			return errors.New("tournament already generated")
+		}
+
+		pairs := tournament.Pairs
+		switch {
+		case isIndividualFormat(tournament.Format) && (len(pairs) < 4 || len(pairs)%4 != 0):
+			return errors.New("americano and mexicano need a multiple of 4 players")
+		case len(pairs) < 2:
+			return errors.New("not enough pairs registered")
+		case tournament.Format == "groups" && len(pairs) < tournament.GroupCount*2:
+			return errors.New("each group needs at least 2 pairs")
+		}
+
+		if err := seedPairs(tx, pairs); err != nil {
+			return err
+		}
+
+		var rounds [][]*models.TournamentMatch
+		switch tournament.Format {
+		case "elimination":
+			rounds = eliminationRounds(pairs)
+		case "groups":
+			rounds = groupRounds(pairs, tournament.GroupCount)
+		default:
+			rounds = rotatingPartnerRounds(pairs, tournament.Format, tournament.Rounds)
+		}
+
+		for i := range pairs {
+			if err := tx.Model(&pairs[i]).Updates(map[string]interface{}{"seed": pairs[i].Seed, "group_number": pairs[i].GroupNumber}).Error; err != nil {
+				return errors.New("failed to update tournament pairs")
+			}
+		}
+
+		if err := s.scheduleTournament(tx, &tournament, rounds); err != nil {
+			return err
+		}
+
+		// Se crean de la final hacia atrás para que cada partido conozca el partido al que avanza su ganador
+		for r := len(rounds) - 1; r >= 0; r-- {
+			for _, match := range rounds[r] {
+				match.TournamentID = tournament.ID
+				if tournament.Format == "elimination" && r < len(rounds)-1 {
+					next := rounds[r+1][match.Position/2]
+					match.NextMatchID = &next.ID
+					match.NextSlot = match.Position%2 + 1
+				}
+				if err := tx.Create(match).Error; err != nil {
+					return errors.New("failed to create tournament matches")
+				}
+			}
+		}
+
+		if err := tx.Model(&tournament).Update("status", "scheduled").Error; err != nil {
+			return errors.New("failed to update tournament")
+		}
+
+		message := fmt.Sprintf("The schedule of the tournament %s is ready.", tournament.Name)
+		for _, playerID := range tournamentPlayerIDs(pairs) {
+			if err := notifyUser(tx, playerID, "tournament_scheduled", "Tournament schedule ready", message, "tournament", &tournament.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetBracket(tournamentID)
+}
+
+// scheduleTournament reserva un turno para cada partido, ronda por ronda: los partidos de una ronda se reparten
+// entre las canchas del torneo dentro de la franja diaria y la ronda siguiente empieza cuando termina la anterior
+func (s *TournamentService) scheduleTournament(tx *gorm.DB, tournament *models.Tournament, rounds [][]*models.TournamentMatch) error {
+	dayStart, _ := parseClock(tournament.DayStartTime)
+	dayEnd, _ := parseClock(tournament.DayEndTime)
+	lastDate := tournament.StartDate.AddDate(0, 0, maxTournamentDays)
+
+	date, minute := tournament.StartDate, dayStart
+	for r, round := range rounds {
+		var pending []*models.TournamentMatch
+		for _, match := range round {
+			if match.Status != "bye" {
+				pending = append(pending, match)
+			}
+		}
+
+		roundEndDate, roundEnd := date, minute
+		for len(pending) > 0 {
+			if minute+tournament.MatchMinutes > dayEnd {
+				date, minute = date.AddDate(0, 0, 1), dayStart
+			}
+			if date.After(lastDate) {
+				return ErrTournamentUnschedulable
+			}
+
+			startTime, endTime := formatClock(minute), formatClock(minute+tournament.MatchMinutes)
+			notes := fmt.Sprintf("%s - round %d", tournament.Name, r+1)
+			for _, court := range tournament.Courts {
+				if len(pending) == 0 {
+					break
+				}
+				booking, err := s.bookingService.reserveEventSlot(tx, tournament.OwnerID, court.ID, date, startTime, endTime, "tournament", notes)
+				if err != nil {
+					// Un turno ocupado o fuera de horario no es un error: se prueba la cancha siguiente o el horario siguiente
+					if isSeriesConflict(err) || errors.Is(err, ErrBookingGranularity) {
+						continue
+					}
+					return err
+				}
+
+				match := pending[0]
+				pending = pending[1:]
+				matchDate := date
+				match.CourtID = &booking.CourtID
+				match.BookingID = &booking.ID
+				match.Date = &matchDate
+				match.StartTime = startTime
+				match.EndTime = endTime
+
+				end := minute + tournament.MatchMinutes
+				if date.After(roundEndDate) || (date.Equal(roundEndDate) && end > roundEnd) {
+					roundEndDate, roundEnd = date, end
+				}
+			}
+			minute += tournamentSlotStep
+		}
+
+		date, minute = roundEndDate, roundEnd
+	}
+	return nil
+}
+
+// RecordResult carga el resultado de un partido. En eliminación el ganador pasa al partido siguiente y en
+// mexicano, al terminar una ronda, se arman los equipos de la próxima según la tabla.
+func (s *TournamentService) RecordResult(tournamentID uint, matchID uint, ownerID uint, req *models.RecordTournamentResultRequest) (*models.TournamentMatch, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", tournamentID, ownerID).First(&tournament).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tournament not found")
+			}
+			return errors.New("failed to fetch tournament")
+		}
+		if tournament.Status != "scheduled" && tournament.Status != "in_progress" {
+			return errors.New("tournament is not in progress")
+		}
+
+		var match models.TournamentMatch
+		if err := tx.Where("id = ? AND tournament_id = ?", matchID, tournament.ID).First(&match).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("match not found")
+			}
+			return errors.New("failed to fetch match")
+		}
+		if match.Status != "scheduled" {
+			return errors.New("match result already recorded")
+		}
+		if match.Pair1ID == nil || match.Pair2ID == nil {
+			return errors.New("match teams are not defined yet")
+		}
+
+		if isIndividualFormat(tournament.Format) {
+			// Se suman los games de cada equipo; el empate es válido
+			if len(req.Sets) == 0 {
+				return errors.New("invalid score")
+			}
+			for _, set := range req.Sets {
+				if set.Team1 < 0 || set.Team2 < 0 {
+					return errors.New("invalid score")
+				}
+				match.Score1 += set.Team1
+				match.Score2 += set.Team2
+			}
+			if match.Score1 > match.Score2 {
+				match.WinnerPairID = match.Pair1ID
+			} else if match.Score2 > match.Score1 {
+				match.WinnerPairID = match.Pair2ID
+			}
+		} else {
+			winnerTeam, err := matchWinner(req.Sets)
+			if err != nil {
+				return err
+			}
+			for _, set := range req.Sets {
+				if set.Team1 > set.Team2 {
+					match.Score1++
+				} else {
+					match.Score2++
+				}
+			}
+			match.WinnerPairID = match.Pair1ID
+			if winnerTeam == 2 {
+				match.WinnerPairID = match.Pair2ID
+			}
+		}
+		match.Sets = req.Sets
+		match.Status = "completed"
+		if err := tx.Save(&match).Error; err != nil {
+			return errors.New("failed to record match result")
+		}
+
+		if match.NextMatchID != nil {
+			column := fmt.Sprintf("pair%d_id", match.NextSlot)
+			if err := tx.Model(&models.TournamentMatch{}).Where("id = ?", *match.NextMatchID).Update(column, *match.WinnerPairID).Error; err != nil {
+				return errors.New("failed to advance winner")
+			}
+		}
+
+		var remainingInRound int64
+		if err := tx.Model(&models.TournamentMatch{}).Where("tournament_id = ? AND round = ? AND status = ?", tournament.ID, match.Round, "scheduled").Count(&remainingInRound).Error; err != nil {
+			return errors.New("failed to fetch tournament matches")
+		}
+		if tournament.Format == "mexicano" && remainingInRound == 0 {
+			if err := fillMexicanoRound(tx, &tournament, match.Round+1); err != nil {
+				return err
+			}
+		}
+
+		var remaining int64
+		if err := tx.Model(&models.TournamentMatch{}).Where("tournament_id = ? AND status = ?", tournament.ID, "scheduled").Count(&remaining).Error; err != nil {
+			return errors.New("failed to fetch tournament matches")
+		}
+		status := "in_progress"
+		if remaining == 0 {
+			status = "finished"
+		}
+		if err := tx.Model(&tournament).Update("status", status).Error; err != nil {
+			return errors.New("failed to update tournament")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var match models.TournamentMatch
+	if err := s.db.Scopes(preloadTournamentMatch).First(&match, matchID).Error; err != nil {
+		return nil, errors.New("failed to load match")
+	}
+	return &match, nil
+}
+
+// CancelTournament cancela el torneo y libera los turnos reservados para los partidos que no se jugaron
+func (s *TournamentService) CancelTournament(tournamentID uint, ownerID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND owner_id = ?", tournamentID, ownerID).
+			Preload("Pairs").
+			First(&tournament).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("tournament not found")
+			}
+			return errors.New("failed to fetch tournament")
+		}
+		if tournament.Status == "finished" || tournament.Status == "cancelled" {
+			return errors.New("tournament cannot be cancelled")
+		}
+
+		var matches []models.TournamentMatch
+		if err := tx.Where("tournament_id = ? AND status = ?", tournament.ID, "scheduled").Find(&matches).Error; err != nil {
+			return errors.New("failed to fetch matches")
+		}
+		for _, match := range matches {
+			if match.BookingID == nil {
+				continue
+			}
+			var booking models.Booking
+			if err := tx.First(&booking, *match.BookingID).Error; err != nil {
+				continue
+			}
+			if booking.Status == "confirmed" {
+				if err := transitionBooking(tx, &booking, "cancelled", &ownerID, "tournament cancelled"); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Model(&models.TournamentMatch{}).Where("tournament_id = ? AND status = ?", tournament.ID, "scheduled").Update("status", "cancelled").Error; err != nil {
+			return errors.New("failed to cancel matches")
+		}
+		if err := tx.Model(&tournament).Update("status", "cancelled").Error; err != nil {
+			return errors.New("failed to cancel tournament")
+		}
+
+		message := fmt.Sprintf("The tournament %s was cancelled.", tournament.Name)
+		for _, playerID := range tournamentPlayerIDs(tournament.Pairs) {
+			if err := notifyUser(tx, playerID, "tournament_cancelled", "Tournament cancelled", message, "tournament", &tournament.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBracket devuelve los partidos del torneo agrupados por ronda, con cancha, horario y resultado
+func (s *TournamentService) GetBracket(tournamentID uint) (*models.TournamentBracketResponse, error) {
+	tournament, err := s.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []models.TournamentMatch
+	err = s.db.Where("tournament_id = ?", tournament.ID).Scopes(preloadTournamentMatch).
+		Order("round, position").
+		Find(&matches).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch matches")
+	}
+
+	response := &models.TournamentBracketResponse{
+		TournamentID: tournament.ID,
+		Format:       tournament.Format,
+		Status:       tournament.Status,
+		Rounds:       []models.TournamentRound{},
+	}
+	for _, match := range matches {
+		if len(response.Rounds) == 0 || response.Rounds[len(response.Rounds)-1].Round != match.Round {
+			response.Rounds = append(response.Rounds, models.TournamentRound{Round: match.Round})
+		}
+		last := &response.Rounds[len(response.Rounds)-1]
+		last.Matches = append(last.Matches, match)
+	}
+	return response, nil
+}
+
+// GetStandings calcula la tabla del torneo con los partidos jugados
+func (s *TournamentService) GetStandings(tournamentID uint) (*models.TournamentStandingsResponse, error) {
+	tournament, err := s.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	standings, err := tournamentStandings(s.db, tournament)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TournamentStandingsResponse{
+		TournamentID: tournament.ID,
+		Format:       tournament.Format,
+		Standings:    standings,
+	}, nil
+}
+
+// tournamentStandings ordena las parejas (o jugadores) por puntos; en grupos, primero por grupo.
+// En americano y mexicano cada jugador suma los games de su equipo.
+func tournamentStandings(db *gorm.DB, tournament *models.Tournament) ([]models.TournamentStanding, error) {
+	var matches []models.TournamentMatch
+	if err := db.Where("tournament_id = ? AND status = ?", tournament.ID, "completed").Find(&matches).Error; err != nil {
+		return nil, errors.New("failed to fetch matches")
+	}
+
+	standings := make([]models.TournamentStanding, len(tournament.Pairs))
+	index := make(map[uint]*models.TournamentStanding, len(tournament.Pairs))
+	seeds := make(map[uint]int, len(tournament.Pairs))
+	for i, pair := range tournament.Pairs {
+		standings[i] = models.TournamentStanding{PairID: pair.ID, Name: pair.Name, GroupNumber: pair.GroupNumber}
+		index[pair.ID] = &standings[i]
+		seeds[pair.ID] = pair.Seed
+	}
+
+	individual := isIndividualFormat(tournament.Format)
+	for _, match := range matches {
+		games := [2]int{}
+		for _, set := range match.Sets {
+			games[0] += set.Team1
+			games[1] += set.Team2
+		}
+		teams := [2][]*uint{{match.Pair1ID, match.Partner1ID}, {match.Pair2ID, match.Partner2ID}}
+		scores := [2]int{match.Score1, match.Score2}
+		for t, team := range teams {
+			for _, id := range team {
+				if id == nil || index[*id] == nil {
+					continue
+				}
+				row := index[*id]
+				row.Played++
+				row.GamesFor += games[t]
+				row.GamesAgainst += games[1-t]
+				if !individual {
+					row.SetsFor += scores[t]
+					row.SetsAgainst += scores[1-t]
+				}
+				if scores[t] > scores[1-t] {
+					row.Won++
+				} else if scores[t] < scores[1-t] {
+					row.Lost++
+				}
+				if individual {
+					row.Points += games[t]
+				} else if scores[t] > scores[1-t] {
+					row.Points++
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.GroupNumber != b.GroupNumber {
+			return a.GroupNumber < b.GroupNumber
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.SetsFor-a.SetsAgainst != b.SetsFor-b.SetsAgainst {
+			return a.SetsFor-a.SetsAgainst > b.SetsFor-b.SetsAgainst
+		}
+		if a.GamesFor-a.GamesAgainst != b.GamesFor-b.GamesAgainst {
+			return a.GamesFor-a.GamesAgainst > b.GamesFor-b.GamesAgainst
+		}
+		return seeds[a.PairID] < seeds[b.PairID]
+	})
+	return standings, nil
+}
+
+// fillMexicanoRound arma los equipos de una ronda de mexicano según la tabla: en cada cancha juegan cuatro
+// jugadores consecutivos de la tabla, el 1° con el 4° contra el 2° con el 3°
+func fillMexicanoRound(tx *gorm.DB, tournament *models.Tournament, round int) error {
+	var matches []models.TournamentMatch
+	if err := tx.Where("tournament_id = ? AND round = ?", tournament.ID, round).Order("position").Find(&matches).Error; err != nil {
+		return errors.New("failed to fetch matches")
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if err := tx.Where("tournament_id = ?", tournament.ID).Find(&tournament.Pairs).Error; err != nil {
+		return errors.New("failed to fetch tournament pairs")
+	}
+	standings, err := tournamentStandings(tx, tournament)
+	if err != nil {
+		return err
+	}
+
+	for i := range matches {
+		if 4*i+3 >= len(standings) {
+			break
+		}
+		players := standings[4*i : 4*i+4]
+		err := tx.Model(&matches[i]).Updates(map[string]interface{}{
+			"pair1_id":    players[0].PairID,
+			"partner1_id": players[3].PairID,
+			"pair2_id":    players[1].PairID,
+			"partner2_id": players[2].PairID,
+		}).Error
+		if err != nil {
+			return errors.New("failed to update matches")
+		}
+	}
+	return nil
+}
+
+// seedPairs ordena las parejas por la suma del rating de sus jugadores y les asigna el número de cabeza de serie
+func seedPairs(tx *gorm.DB, pairs []models.TournamentPair) error {
+	var profiles []models.PlayerProfile
+	if err := tx.Where("user_id IN ?", tournamentPlayerIDs(pairs)).Find(&profiles).Error; err != nil {
+		return errors.New("failed to fetch player profiles")
+	}
+	ratings := make(map[uint]float64, len(profiles))
+	for _, profile := range profiles {
+		ratings[profile.UserID] = profile.Rating
+	}
+	rating := func(userID uint) float64 {
+		if value, ok := ratings[userID]; ok {
+			return value
+		}
+		return ratingForLevel(defaultPlayerLevel)
+	}
+	pairRating := func(pair *models.TournamentPair) float64 {
+		total := rating(pair.Player1ID)
+		if pair.Player2ID != nil {
+			total += rating(*pair.Player2ID)
+		}
+		return total
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairRating(&pairs[i]) > pairRating(&pairs[j])
+	})
+	for i := range pairs {
+		pairs[i].Seed = i + 1
+	}
+	return nil
+}
+
+// eliminationRounds arma el cuadro de eliminación directa con las cabezas de serie en los extremos.
+// Si la cantidad de parejas no es potencia de 2, las mejores pasan la primera ronda sin jugar (bye).
+func eliminationRounds(pairs []models.TournamentPair) [][]*models.TournamentMatch {
+	size := 2
+	for size < len(pairs) {
+		size *= 2
+	}
+
+	var rounds [][]*models.TournamentMatch
+	for matches, round := size/2, 1; matches >= 1; matches, round = matches/2, round+1 {
+		current := make([]*models.TournamentMatch, matches)
+		for i := range current {
+			current[i] = &models.TournamentMatch{Round: round, Position: i, Status: "scheduled"}
+		}
+		rounds = append(rounds, current)
+	}
+
+	order := bracketOrder(size)
+	for i, match := range rounds[0] {
+		seed1, seed2 := order[2*i], order[2*i+1]
+		match.Pair1ID = &pairs[seed1-1].ID
+		if seed2 <= len(pairs) {
+			match.Pair2ID = &pairs[seed2-1].ID
+			continue
+		}
+
+		match.Status = "bye"
+		match.WinnerPairID = match.Pair1ID
+		if len(rounds) > 1 {
+			next := rounds[1][i/2]
+			if i%2 == 0 {
+				next.Pair1ID = match.Pair1ID
+			} else {
+				next.Pair2ID = match.Pair1ID
+			}
+		}
+	}
+	return rounds
+}
+
+// bracketOrder devuelve el orden de las cabezas de serie en el cuadro para que la 1 y la 2 solo se crucen en la final
+func bracketOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		total := len(order)*2 + 1
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, total-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// groupRounds reparte las parejas en grupos en serpentina y arma un todos contra todos en cada grupo.
+// Los grupos juegan su ronda en paralelo.
+func groupRounds(pairs []models.TournamentPair, groupCount int) [][]*models.TournamentMatch {
+	groups := make([][]models.TournamentPair, groupCount)
+	for i := range pairs {
+		row, col := i/groupCount, i%groupCount
+		if row%2 == 1 {
+			col = groupCount - 1 - col
+		}
+		pairs[i].GroupNumber = col + 1
+		groups[col] = append(groups[col], pairs[i])
+	}
+
+	var rounds [][]*models.TournamentMatch
+	for g, group := range groups {
+		for r, round := range roundRobin(len(group)) {
+			if r == len(rounds) {
+				rounds = append(rounds, nil)
+			}
+			for _, game := range round {
+				rounds[r] = append(rounds[r], &models.TournamentMatch{
+					Round:       r + 1,
+					Position:    len(rounds[r]),
+					GroupNumber: g + 1,
+					Pair1ID:     &group[game[0]].ID,
+					Pair2ID:     &group[game[1]].ID,
+					Status:      "scheduled",
+				})
+			}
+		}
+	}
+	return rounds
+}
+
+// rotatingPartnerRounds arma las rondas de americano y mexicano. En americano cada jugador juega con un
+// compañero distinto en cada ronda; en mexicano la primera ronda se arma por ranking y las siguientes se
+// completan según la tabla al terminar la ronda anterior.
+func rotatingPartnerRounds(players []models.TournamentPair, format string, roundCount int) [][]*models.TournamentMatch {
+	if roundCount <= 0 {
+		roundCount = len(players) - 1
+	}
+
+	partners := roundRobin(len(players))
+	rounds := make([][]*models.TournamentMatch, roundCount)
+	for r := range rounds {
+		for k := 0; k < len(players)/4; k++ {
+			match := &models.TournamentMatch{Round: r + 1, Position: k, Status: "scheduled"}
+			switch {
+			case format == "americano":
+				team1, team2 := partners[r][2*k], partners[r][2*k+1]
+				match.Pair1ID, match.Partner1ID = &players[team1[0]].ID, &players[team1[1]].ID
+				match.Pair2ID, match.Partner2ID = &players[team2[0]].ID, &players[team2[1]].ID
+			case r == 0:
+				group := players[4*k : 4*k+4]
+				match.Pair1ID, match.Partner1ID = &group[0].ID, &group[3].ID
+				match.Pair2ID, match.Partner2ID = &group[1].ID, &group[2].ID
+			}
+			rounds[r] = append(rounds[r], match)
+		}
+	}
+	return rounds
+}
+
+// roundRobin genera las rondas de un todos contra todos con el método del círculo; con cantidad impar,
+// en cada ronda un participante queda libre
+func roundRobin(n int) [][][2]int {
+	slots := make([]int, n)
+	for i := range slots {
+		slots[i] = i
+	}
+	if n%2 == 1 {
+		slots = append(slots, -1)
+	}
+
+	size := len(slots)
+	rounds := make([][][2]int, 0, size-1)
+	for r := 0; r < size-1; r++ {
+		var round [][2]int
+		for i := 0; i < size/2; i++ {
+			a, b := slots[i], slots[size-1-i]
+			if a >= 0 && b >= 0 {
+				round = append(round, [2]int{a, b})
+			}
+		}
+		rounds = append(rounds, round)
+
+		// El primero queda fijo y el resto rota una posición
+		last := slots[size-1]
+		copy(slots[2:], slots[1:size-1])
+		slots[1] = last
+	}
+	return rounds
+}
+
+func isIndividualFormat(format string) bool {
+	return format == "americano" || format == "mexicano"
+}
+
+func tournamentPlayerIDs(pairs []models.TournamentPair) []uint {
+	ids := make([]uint, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		ids = append(ids, pair.Player1ID)
+		if pair.Player2ID != nil {
+			ids = append(ids, *pair.Player2ID)
+		}
+	}
+	return ids
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func preloadTournamentMatch(db *gorm.DB) *gorm.DB {
+	return db.Preload("Pair1").Preload("Pair2").Preload("Partner1").Preload("Partner2")
+}
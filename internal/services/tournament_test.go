@@ -0,0 +1,41 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+func TestCreateTournamentValidatesMatchDurationAgainstCourts(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	service := NewTournamentService(db, NewBookingService(db, NewPaymentService(db, &fakeGateway{})))
+
+	req := func(matchMinutes int, dayStart, dayEnd string) *models.CreateTournamentRequest {
+		return &models.CreateTournamentRequest{
+			Name:         "Torneo",
+			Format:       "elimination",
+			CourtIDs:     []uint{court.ID},
+			StartDate:    testutil.Day(7).Format("2006-01-02"),
+			DayStartTime: dayStart,
+			DayEndTime:   dayEnd,
+			MatchMinutes: matchMinutes,
+			MaxEntries:   8,
+		}
+	}
+
+	// La cancha solo admite turnos de 60, 90 y 120 minutos
+	if _, err := service.CreateTournament(owner.ID, req(75, "09:00", "22:00")); err == nil || !strings.Contains(err.Error(), "match duration not allowed") {
+		t.Fatalf("expected a 75-minute match to be rejected, got %v", err)
+	}
+	// La cancha abre a las 08:00: ningún partido de 90 minutos entra entre las 06:00 y las 09:00
+	if _, err := service.CreateTournament(owner.ID, req(90, "06:00", "09:00")); err == nil || !strings.Contains(err.Error(), "has no slot") {
+		t.Fatalf("expected the window outside the court hours to be rejected, got %v", err)
+	}
+	if _, err := service.CreateTournament(owner.ID, req(90, "07:00", "10:00")); err != nil {
+		t.Fatalf("expected a match from 08:00 to fit: %v", err)
+	}
+}
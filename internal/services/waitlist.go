@@ -0,0 +1,286 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type WaitlistService struct {
+	db *gorm.DB
+}
+
+func NewWaitlistService(db *gorm.DB) *WaitlistService {
+	return &WaitlistService{db: db}
+}
+
+// JoinWaitlist anota al usuario para un rango horario de una cancha en una fecha
+func (s *WaitlistService) JoinWaitlist(userID uint, req *models.JoinWaitlistRequest) (*models.WaitlistEntry, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format")
+	}
+	if req.Date < time.Now().Format("2006-01-02") {
+		return nil, ErrBookingInPast
+	}
+
+	var court models.Court
+	if err := s.db.Where("id = ? AND is_active = ?", req.CourtID, true).First(&court).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("court not found")
+		}
+		return nil, errors.New("failed to fetch court")
+	}
+
+	schedule, err := resolveDaySchedule(s.db, court.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.IsClosed {
+		return nil, ErrCourtClosed
+	}
+	if _, _, err := schedule.toOperational(req.StartTime, req.EndTime); err != nil {
+		return nil, ErrInvalidBookingTime
+	}
+
+	// Un usuario no puede anotarse dos veces al mismo rango
+	var count int64
+	s.db.Model(&models.WaitlistEntry{}).
+		Where("user_id = ? AND court_id = ? AND date = ? AND start_time = ? AND end_time = ? AND status IN ?", userID, court.ID, req.Date, req.StartTime, req.EndTime, []string{"waiting", "notified"}).
+		Count(&count)
+	if count > 0 {
+		return nil, errors.New("already on the waitlist")
+	}
+
+	entry := models.WaitlistEntry{
+		UserID:    userID,
+		CourtID:   court.ID,
+		Date:      date,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Status:    "waiting",
+	}
+	if err := s.db.Create(&entry).Error; err != nil {
+		return nil, errors.New("failed to join waitlist")
+	}
+
+	entry.Court = court
+	return &entry, nil
+}
+
+func (s *WaitlistService) GetUserWaitlist(userID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := s.db.Where("user_id = ? AND date >= ?", userID, time.Now().Format("2006-01-02")).
+		Preload("Court").
+		Order("date, start_time").
+		Find(&entries).Error
+	if err != nil {
+		return nil, errors.New("failed to fetch waitlist")
+	}
+	return entries, nil
+}
+
+// LeaveWaitlist da de baja la inscripción; si tenía un turno ofrecido, éste vuelve a estar disponible para los demás
+func (s *WaitlistService) LeaveWaitlist(id uint, userID uint) error {
+	var entry models.WaitlistEntry
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("waitlist entry not found")
+		}
+		return errors.New("failed to fetch waitlist entry")
+	}
+
+	if entry.Status != "waiting" && entry.Status != "notified" {
+		return errors.New("waitlist entry is no longer active")
+	}
+
+	if err := s.db.Model(&entry).Update("status", "cancelled").Error; err != nil {
+		return errors.New("failed to leave waitlist")
+	}
+	return nil
+}
+
+// offerReleasedSlot ofrece el turno de una reserva cancelada o vencida a los primeros usuarios de la lista de espera
+// cuyo rango lo contiene. Los notificados tienen un plazo exclusivo para reservarlo antes de que vuelva a estar
+// disponible para todos; el primero que reserva se queda con el turno.
+func offerReleasedSlot(tx *gorm.DB, booking *models.Booking) error {
+	schedule, err := resolveDaySchedule(tx, booking.CourtID, booking.Date)
+	if err != nil {
+		return err
+	}
+	if schedule.IsClosed {
+		return nil
+	}
+
+	// Un turno que ya comenzó no tiene sentido ofrecerlo
+	startsAt, err := bookingStartsAt(schedule, booking)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !startsAt.After(now) {
+		return nil
+	}
+	start, end, err := schedule.toOperational(booking.StartTime, booking.EndTime)
+	if err != nil {
+		return err
+	}
+
+	var entries []models.WaitlistEntry
+	if err := tx.Where("court_id = ? AND date = ? AND status = ?", booking.CourtID, booking.Date.Format("2006-01-02"), "waiting").
+		Order("created_at, id").
+		Find(&entries).Error; err != nil {
+		return errors.New("failed to fetch waitlist")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var court models.Court
+	if err := tx.Select("id", "name").First(&court, booking.CourtID).Error; err != nil {
+		return errors.New("failed to fetch court")
+	}
+
+	// El plazo exclusivo nunca se extiende más allá del inicio del turno
+	cfg := config.Load()
+	claimExpiresAt := now.Add(time.Duration(cfg.Booking.WaitlistClaimMinutes) * time.Minute)
+	if claimExpiresAt.After(startsAt) {
+		claimExpiresAt = startsAt
+	}
+
+	notified := 0
+	for _, entry := range entries {
+		if notified >= cfg.Booking.WaitlistNotifyCount {
+			break
+		}
+		// Quien liberó el turno no lo recibe de vuelta
+		if booking.UserID != nil && entry.UserID == *booking.UserID {
+			continue
+		}
+		wantedStart, wantedEnd, err := schedule.toOperational(entry.StartTime, entry.EndTime)
+		if err != nil || start < wantedStart || end > wantedEnd {
+			continue
+		}
+
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, "waiting").
+			Updates(map[string]interface{}{
+				"status":              "notified",
+				"released_booking_id": booking.ID,
+				"claim_start_time":    booking.StartTime,
+				"claim_end_time":      booking.EndTime,
+				"claim_expires_at":    claimExpiresAt,
+				"notified_at":         now,
+			})
+		if result.Error != nil {
+			return errors.New("failed to update waitlist entry")
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf("%s is available on %s from %s to %s. Book it before %s to keep it.",
+			court.Name, booking.Date.Format("2006-01-02"), booking.StartTime, booking.EndTime, claimExpiresAt.Format("15:04"))
+		if err := notifyUser(tx, entry.UserID, "waitlist_slot_available", "A slot you are waiting for is available", message, "waitlist", &entry.ID); err != nil {
+			return err
+		}
+		notified++
+	}
+
+	return nil
+}
+
+// waitlistClaimBlocks indica si el turno está reservado para usuarios notificados de la lista de espera.
+// claimantID es el usuario que reserva online; las reservas cargadas por el club (nil) no quedan bloqueadas.
+func waitlistClaimBlocks(tx *gorm.DB, court *models.Court, date time.Time, schedule *daySchedule, startTime, endTime string, claimantID *uint) (bool, error) {
+	if claimantID == nil {
+		return false, nil
+	}
+
+	claims, err := activeWaitlistClaims(tx, court.ID, date)
+	if err != nil {
+		return false, err
+	}
+
+	// Los notificados para un mismo turno liberado compiten entre sí, pero el turno no bloquea a ninguno de ellos
+	ownClaims := make(map[uint]bool)
+	for _, claim := range claims {
+		if claim.UserID == *claimantID && claim.ReleasedBookingID != nil {
+			ownClaims[*claim.ReleasedBookingID] = true
+		}
+	}
+
+	bufferMinutes := courtSlotConfig(court).BufferMinutes
+	for _, claim := range claims {
+		if claim.ReleasedBookingID != nil && ownClaims[*claim.ReleasedBookingID] {
+			continue
+		}
+		if overlapsWithBuffer(schedule, startTime, endTime, claim.ClaimStartTime, claim.ClaimEndTime, bufferMinutes) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// activeWaitlistClaims devuelve los turnos de la fecha que están ofrecidos a la lista de espera y cuyo plazo sigue vigente
+func activeWaitlistClaims(db *gorm.DB, courtID uint, date time.Time) ([]models.WaitlistEntry, error) {
+	var claims []models.WaitlistEntry
+	if err := db.Where("court_id = ? AND date = ? AND status = ? AND claim_expires_at > ?", courtID, date.Format("2006-01-02"), "notified", time.Now()).
+		Find(&claims).Error; err != nil {
+		return nil, errors.New("failed to check availability")
+	}
+	return claims, nil
+}
+
+// claimWaitlistSlot marca como tomado el turno ofrecido al usuario que acaba de reservarlo.
+// Los demás notificados para el mismo turno vuelven a la lista conservando su lugar.
+func claimWaitlistSlot(tx *gorm.DB, userID uint, booking *models.Booking) error {
+	var claims []models.WaitlistEntry
+	if err := tx.Where("user_id = ? AND court_id = ? AND date = ? AND status = ? AND claim_expires_at > ?", userID, booking.CourtID, booking.Date.Format("2006-01-02"), "notified", time.Now()).
+		Find(&claims).Error; err != nil {
+		return errors.New("failed to fetch waitlist")
+	}
+
+	for _, claim := range claims {
+		if !overlapsWithBuffer(nil, booking.StartTime, booking.EndTime, claim.ClaimStartTime, claim.ClaimEndTime, 0) {
+			continue
+		}
+
+		if err := tx.Model(&models.WaitlistEntry{}).Where("id = ?", claim.ID).
+			Updates(map[string]interface{}{"status": "claimed", "booking_id": booking.ID}).Error; err != nil {
+			return errors.New("failed to update waitlist entry")
+		}
+
+		if claim.ReleasedBookingID != nil {
+			if err := tx.Model(&models.WaitlistEntry{}).
+				Where("released_booking_id = ? AND status = ? AND id <> ?", *claim.ReleasedBookingID, "notified", claim.ID).
+				Updates(map[string]interface{}{
+					"status":              "waiting",
+					"released_booking_id": nil,
+					"claim_start_time":    "",
+					"claim_end_time":      "",
+					"claim_expires_at":    nil,
+				}).Error; err != nil {
+				return errors.New("failed to update waitlist entry")
+			}
+		}
+	}
+	return nil
+}
+
+// ExpireWaitlistClaims da por vencidos los turnos ofrecidos que nadie tomó a tiempo; el turno ya volvió a estar
+// disponible para todos y los notificados pierden su lugar en la lista
+func (s *BookingService) ExpireWaitlistClaims() (int64, error) {
+	result := s.db.Model(&models.WaitlistEntry{}).
+		Where("status = ? AND claim_expires_at <= ?", "notified", time.Now()).
+		Update("status", "expired")
+	if result.Error != nil {
+		return 0, errors.New("failed to expire waitlist claims")
+	}
+	return result.RowsAffected, nil
+}
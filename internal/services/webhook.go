@@ -0,0 +1,144 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// webhookProcessingTimeout es el tiempo tras el cual un evento que quedó en processing (por ejemplo, por una
+// caída del servidor) puede volver a tomarse cuando el proveedor reintenta
+const webhookProcessingTimeout = 10 * time.Minute
+
+// VerifyWebhookSignature valida el header x-signature ("ts=...,v1=...") que MercadoPago firma con
+// HMAC-SHA256 sobre el manifiesto "id:<data.id>;request-id:<x-request-id>;ts:<ts>;". Las firmas cuyo ts
+// se aleja más de la tolerancia configurada se rechazan para que una notificación capturada no pueda reenviarse.
+func (s *PaymentService) VerifyWebhookSignature(signature, requestID, dataID string) error {
+	cfg := config.Load()
+	if cfg.MercadoPago.WebhookSecret == "" {
+		return errors.New("webhook secret not configured")
+	}
+
+	var ts, v1 string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "ts":
+			ts = value
+		case "v1":
+			v1 = value
+		}
+	}
+	if ts == "" || v1 == "" {
+		return errors.New("invalid webhook signature")
+	}
+
+	// Los ids alfanuméricos se firman en minúsculas
+	manifest := fmt.Sprintf("id:%s;", strings.ToLower(dataID))
+	if requestID != "" {
+		manifest += fmt.Sprintf("request-id:%s;", requestID)
+	}
+	manifest += fmt.Sprintf("ts:%s;", ts)
+
+	mac := hmac.New(sha256.New, []byte(cfg.MercadoPago.WebhookSecret))
+	mac.Write([]byte(manifest))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(v1)) {
+		return errors.New("invalid webhook signature")
+	}
+
+	signedAt, err := parseWebhookTimestamp(ts)
+	if err != nil {
+		return errors.New("invalid webhook signature")
+	}
+	age := time.Since(signedAt)
+	if age < 0 {
+		age = -age
+	}
+	if age > time.Duration(cfg.MercadoPago.WebhookToleranceSec)*time.Second {
+		return errors.New("expired webhook signature")
+	}
+	return nil
+}
+
+// parseWebhookTimestamp interpreta el ts de la firma, que el proveedor envía en segundos o en milisegundos
+func parseWebhookTimestamp(ts string) (time.Time, error) {
+	value, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if value >= 1e12 {
+		return time.UnixMilli(value), nil
+	}
+	return time.Unix(value, 0), nil
+}
+
+// ReceiveWebhook registra una notificación del proveedor y la procesa una única vez. Los reenvíos de un
+// evento ya procesado o en proceso se descartan; los eventos que fallaron se reprocesan cuando el proveedor reintenta.
+func (s *PaymentService) ReceiveWebhook(eventID, action, resourceID, payload string) error {
+	if resourceID == "" {
+		return errors.New("invalid payment ID in webhook data")
+	}
+	if eventID == "" {
+		eventID = action + ":" + resourceID
+	}
+
+	event := models.WebhookEvent{
+		Provider:   s.gateway.Name(),
+		EventID:    eventID,
+		Action:     action,
+		ResourceID: resourceID,
+		Payload:    payload,
+		Status:     "received",
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&event)
+	if result.Error != nil {
+		return errors.New("failed to store webhook event")
+	}
+
+	if result.RowsAffected == 0 {
+		if err := s.db.Where("provider = ? AND event_id = ?", event.Provider, eventID).First(&event).Error; err != nil {
+			return errors.New("failed to fetch webhook event")
+		}
+	}
+
+	// Se toma el evento con una actualización condicional: entre duplicados concurrentes solo uno lo procesa
+	claim := s.db.Model(&models.WebhookEvent{}).
+		Where("id = ? AND (status IN ? OR (status = ? AND updated_at < ?))", event.ID, []string{"received", "failed"}, "processing", time.Now().Add(-webhookProcessingTimeout)).
+		Update("status", "processing")
+	if claim.Error != nil {
+		return errors.New("failed to claim webhook event")
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	applied, err := s.syncGatewayPayment(resourceID)
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": "processed", "error": "", "processed_at": &now}
+	if err != nil {
+		updates = map[string]interface{}{"status": "failed", "error": err.Error()}
+	} else if !applied {
+		updates["status"] = "ignored"
+	}
+	if updateErr := s.db.Model(&event).Updates(updates).Error; updateErr != nil && err == nil {
+		return errors.New("failed to update webhook event")
+	}
+
+	return err
+}
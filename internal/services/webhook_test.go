@@ -0,0 +1,163 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/testutil"
+)
+
+// countingGateway cuenta las consultas de pagos al proveedor para saber cuántas veces se procesó una notificación
+type countingGateway struct {
+	*SandboxGateway
+	gets int
+}
+
+func (g *countingGateway) GetPayment(providerPaymentID string) (*GatewayPayment, error) {
+	g.gets++
+	return g.SandboxGateway.GetPayment(providerPaymentID)
+}
+
+// signWebhook arma el header x-signature como lo firma el proveedor
+func signWebhook(secret, dataID, requestID, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("id:%s;request-id:%s;ts:%s;", dataID, requestID, ts)))
+	return fmt.Sprintf("ts=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "secret")
+	t.Setenv("MERCADOPAGO_WEBHOOK_TOLERANCE", "300")
+	service := NewPaymentService(nil, &fakeGateway{})
+	now := time.Now()
+	seconds := func(at time.Time) string { return strconv.FormatInt(at.Unix(), 10) }
+
+	tests := []struct {
+		name      string
+		signature string
+		dataID    string
+		wantErr   string
+	}{
+		{"valid", signWebhook("secret", "123", "req-1", seconds(now)), "123", ""},
+		{"valid in milliseconds", signWebhook("secret", "123", "req-1", strconv.FormatInt(now.UnixMilli(), 10)), "123", ""},
+		{"other resource", signWebhook("secret", "123", "req-1", seconds(now)), "456", "invalid webhook signature"},
+		{"other secret", signWebhook("other", "123", "req-1", seconds(now)), "123", "invalid webhook signature"},
+		{"missing parts", "ts=" + seconds(now), "123", "invalid webhook signature"},
+		{"replayed", signWebhook("secret", "123", "req-1", seconds(now.Add(-10*time.Minute))), "123", "expired webhook signature"},
+		{"from the future", signWebhook("secret", "123", "req-1", seconds(now.Add(10*time.Minute))), "123", "expired webhook signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.VerifyWebhookSignature(tt.signature, "req-1", tt.dataID)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("expected a valid signature, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "")
+	if err := service.VerifyWebhookSignature(signWebhook("secret", "123", "req-1", seconds(now)), "req-1", "123"); err == nil || err.Error() != "webhook secret not configured" {
+		t.Fatalf("expected the missing secret to be reported, got %v", err)
+	}
+}
+
+func TestReceiveWebhookProcessesEachEventOnce(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	hold := time.Now().Add(15 * time.Minute)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, testutil.Day(3), "18:00", "19:30", "pending", &hold)
+
+	gateway := &countingGateway{SandboxGateway: NewSandboxGateway("http://localhost:8080")}
+	service := NewPaymentService(db, gateway)
+	payment := newSandboxPayment(t, db, gateway.SandboxGateway, &booking, booking.TotalPrice)
+	gatewayPayment, err := gateway.SimulatePayment(payment.PreferenceID, "approved")
+	if err != nil {
+		t.Fatalf("simulate payment: %v", err)
+	}
+
+	// Un reenvío del evento ya procesado no vuelve a consultar al proveedor
+	for i := 0; i < 2; i++ {
+		if err := service.ReceiveWebhook("evt-1", "payment.updated", gatewayPayment.ID, "{}"); err != nil {
+			t.Fatalf("receive webhook: %v", err)
+		}
+	}
+	if gateway.gets != 1 {
+		t.Fatalf("expected the event to be processed once, got %d", gateway.gets)
+	}
+	db.First(&booking, booking.ID)
+	if booking.Status != "confirmed" {
+		t.Fatalf("expected the booking confirmed, got %s", booking.Status)
+	}
+
+	// Un evento que otro proceso está procesando se descarta; uno que falló o quedó colgado se reprocesa
+	stale := time.Now().Add(-2 * webhookProcessingTimeout)
+	events := []struct {
+		eventID   string
+		status    string
+		updatedAt time.Time
+		processed bool
+	}{
+		{"evt-processing", "processing", time.Now(), false},
+		{"evt-failed", "failed", time.Now(), true},
+		{"evt-stuck", "processing", stale, true},
+	}
+	for _, tt := range events {
+		event := models.WebhookEvent{Provider: "sandbox", EventID: tt.eventID, ResourceID: gatewayPayment.ID, Status: tt.status}
+		db.Create(&event)
+		db.Model(&event).UpdateColumn("updated_at", tt.updatedAt)
+
+		gets := gateway.gets
+		if err := service.ReceiveWebhook(tt.eventID, "payment.updated", gatewayPayment.ID, "{}"); err != nil {
+			t.Fatalf("%s: receive webhook: %v", tt.eventID, err)
+		}
+		if processed := gateway.gets > gets; processed != tt.processed {
+			t.Fatalf("%s: expected processed=%v, got %v", tt.eventID, tt.processed, processed)
+		}
+	}
+}
+
+func TestSyncGatewayPaymentKeepsRefundsAndNeverRegresses(t *testing.T) {
+	db := testutil.NewDB(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	player := testutil.CreateUser(t, db, "player@test.com", "user")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	hold := time.Now().Add(15 * time.Minute)
+	booking := testutil.CreateBooking(t, db, court.ID, player.ID, testutil.Day(3), "18:00", "19:30", "pending", &hold)
+
+	gateway := NewSandboxGateway("http://localhost:8080")
+	service := NewPaymentService(db, gateway)
+	payment := newSandboxPayment(t, db, gateway, &booking, booking.TotalPrice)
+	approved, _ := gateway.SimulatePayment(payment.PreferenceID, "approved")
+	if _, err := service.syncGatewayPayment(approved.ID); err != nil {
+		t.Fatalf("sync payment: %v", err)
+	}
+	db.First(&payment, "id = ?", payment.ID)
+	if _, err := service.refund(&payment, 3000, "partial refund", nil); err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	// Un reenvío de la aprobación no pisa el reembolso registrado mientras tanto
+	if _, err := service.syncGatewayPayment(approved.ID); err != nil {
+		t.Fatalf("resync payment: %v", err)
+	}
+	pending, _ := gateway.SimulatePayment(payment.PreferenceID, "pending")
+	if applied, err := service.syncGatewayPayment(pending.ID); err != nil || applied {
+		t.Fatalf("expected the pending notification to be ignored, got applied=%v err=%v", applied, err)
+	}
+
+	db.First(&payment, "id = ?", payment.ID)
+	if payment.Status != "approved" || payment.RefundedAmount != 3000 || payment.MercadoPagoID != approved.ID {
+		t.Fatalf("expected the approved payment with 3000 refunded, got %s with %.2f (%s)", payment.Status, payment.RefundedAmount, payment.MercadoPagoID)
+	}
+}
@@ -0,0 +1,78 @@
+// Package testutil reúne utilidades compartidas por los tests de integración
+package testutil
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/database"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const driverName = "sqlite3_padel"
+
+var registerOnce sync.Once
+
+// dateConn guarda las fechas sin hora como "2006-01-02", igual que las columnas DATE de MySQL, para que
+// las consultas que comparan la fecha con un string (date = ?) encuentren las filas
+type dateConn struct {
+	*sqlite3.SQLiteConn
+}
+
+func (dateConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		nv.Value = t.Format("2006-01-02")
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type dateDriver struct {
+	sqlite3.SQLiteDriver
+}
+
+func (d *dateDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return dateConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+// NewDB crea una base SQLite migrada en un directorio temporal del test. Las transacciones toman el
+// bloqueo de escritura al comenzar, por lo que se serializan como con el bloqueo de fila de MySQL.
+func NewDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	registerOnce.Do(func() {
+		sql.Register(driverName, &dateDriver{})
+	})
+
+	dsn := filepath.Join(t.TempDir(), "padel.db") + "?_txlock=immediate&_busy_timeout=10000&_journal_mode=WAL&_foreign_keys=0"
+	db, err := gorm.Open(sqlite.Dialector{DriverName: driverName, DSN: dsn}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return db
+}
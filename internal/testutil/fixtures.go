@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateUser crea un usuario con el rol indicado (user, owner o admin)
+func CreateUser(t *testing.T, db *gorm.DB, email, role string) models.User {
+	t.Helper()
+	user := models.User{Email: email, Password: "x", FirstName: "Test", LastName: "User", Phone: "1100000000", Role: role}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user %s: %v", email, err)
+	}
+	return user
+}
+
+// CreateCourt crea una cancha del propietario abierta todos los días de 08:00 a 23:00
+func CreateCourt(t *testing.T, db *gorm.DB, ownerID uint) models.Court {
+	t.Helper()
+	court := models.Court{
+		Name:         "Cancha 1",
+		Address:      "Av. Siempre Viva 742",
+		Latitude:     -34.6,
+		Longitude:    -58.4,
+		PricePerHour: 12000,
+		OwnerID:      ownerID,
+		IsActive:     true,
+	}
+	if err := db.Omit("Amenities", "Rules").Create(&court).Error; err != nil {
+		t.Fatalf("create court: %v", err)
+	}
+	for day := 0; day < 7; day++ {
+		hour := models.BusinessHour{CourtID: court.ID, DayOfWeek: day, OpenTime: "08:00", CloseTime: "23:00"}
+		if err := db.Omit("Court").Create(&hour).Error; err != nil {
+			t.Fatalf("create business hour: %v", err)
+		}
+	}
+	return court
+}
+
+// CreateBooking crea una reserva online en el estado indicado. Las pendientes reciben el bloqueo holdExpiresAt.
+func CreateBooking(t *testing.T, db *gorm.DB, courtID, userID uint, date time.Time, startTime, endTime, status string, holdExpiresAt *time.Time) models.Booking {
+	t.Helper()
+	booking := models.Booking{
+		CourtID:       courtID,
+		UserID:        &userID,
+		Date:          date,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Status:        status,
+		Source:        "online",
+		TotalPrice:    12000,
+		HoldExpiresAt: holdExpiresAt,
+	}
+	if err := db.Omit("Court", "User").Create(&booking).Error; err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+	return booking
+}
+
+// Day devuelve la fecha (sin hora) dentro de days días
+func Day(days int) time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, time.UTC)
+}
@@ -1,159 +1,346 @@
-package main
-
-import (
-	"backend-padel-go/internal/config"
-	"backend-padel-go/internal/database"
-	"backend-padel-go/internal/handlers"
-	"backend-padel-go/internal/middleware"
-	"backend-padel-go/internal/models"
-	"backend-padel-go/internal/services"
-	"log"
-	"os"
-
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	_ "backend-padel-go/docs" // Importar documentación Swagger
-	ginSwagger "github.com/swaggo/gin-swagger"
-	"github.com/swaggo/gin-swagger/swaggerFiles"
-)
-
-// @title Padel Courts API
-// @version 1.0
-// @description API para gestión de canchas de padel
-// @host localhost:8080
-// @BasePath /api/v1
-// @securityDefinitions.apikey BearerAuth
-// @in header
-// @name Authorization
-func main() {
-	// Cargar variables de entorno
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
-	}
-
-	// Configurar base de datos
-	db, err := database.Connect()
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
-	}
-
-	// Migrar esquemas
-	if err := database.Migrate(db); err != nil {
-		log.Fatal("Failed to migrate database:", err)
-	}
-
-	// Configurar Gin
-	if os.Getenv("GIN_MODE") == "release" {
-		gin.SetMode(gin.ReleaseMode)
-	}
-
-	r := gin.New()
-
-	// Middleware global
-	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
-	r.Use(middleware.CORS())
-
-	// Inicializar servicios
-	authService := services.NewAuthService(db)
-	courtService := services.NewCourtService(db)
-	bookingService := services.NewBookingService(db)
-	paymentService := services.NewPaymentService(db)
-	reviewService := services.NewReviewService(db)
-
-	// Inicializar handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	courtHandler := handlers.NewCourtHandler(courtService)
-	bookingHandler := handlers.NewBookingHandler(bookingService)
-	paymentHandler := handlers.NewPaymentHandler(paymentService)
-	reviewHandler := handlers.NewReviewHandler(reviewService)
-
-	// Configurar Swagger
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Configurar rutas
-	setupRoutes(r, authHandler, courtHandler, bookingHandler, paymentHandler, reviewHandler)
-
-	// Iniciar servidor
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
-	}
-}
-
-func setupRoutes(r *gin.Engine, authHandler *handlers.AuthHandler, courtHandler *handlers.CourtHandler, bookingHandler *handlers.BookingHandler, paymentHandler *handlers.PaymentHandler, reviewHandler *handlers.ReviewHandler) {
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "OK"})
-	})
-
-	// Grupo de rutas API
-	v1 := r.Group("/api/v1")
-	{
-		// Rutas de autenticación
-		auth := v1.Group("/auth")
-		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-		}
-
-		// Rutas públicas de canchas
-		courts := v1.Group("/courts")
-		{
-			courts.GET("", courtHandler.GetAllCourts)
-			courts.GET("/:id", courtHandler.GetCourtByID)
-			courts.GET("/nearby", courtHandler.GetNearbyCourts)
-			courts.GET("/search", courtHandler.SearchCourts)
-			courts.GET("/:id/availability", courtHandler.GetAvailability)
-			courts.GET("/:id/reviews", reviewHandler.GetCourtReviews)
-		}
-
-		// Rutas protegidas
-		protected := v1.Group("")
-		protected.Use(middleware.AuthRequired())
-		{
-			// Gestión de canchas (propietarios)
-			owner := protected.Group("/owner")
-			{
-				owner.POST("/courts", courtHandler.CreateCourt)
-				owner.GET("/courts", courtHandler.GetOwnerCourts)
-				owner.PUT("/courts/:id", courtHandler.UpdateCourt)
-				owner.DELETE("/courts/:id", courtHandler.DeleteCourt)
-				owner.GET("/courts/:id/statistics", courtHandler.GetCourtStatistics)
-				owner.POST("/courts/:id/special-hours", courtHandler.CreateSpecialHours)
-				owner.GET("/courts/:id/special-hours", courtHandler.GetSpecialHours)
-			}
-
-			// Reservas
-			bookings := protected.Group("/bookings")
-			{
-				bookings.POST("", bookingHandler.CreateBooking)
-				bookings.GET("", bookingHandler.GetUserBookings)
-				bookings.GET("/:id", bookingHandler.GetBookingByID)
-				bookings.PUT("/:id/cancel", bookingHandler.CancelBooking)
-			}
-
-			// Reseñas
-			reviews := protected.Group("/reviews")
-			{
-				reviews.POST("/courts/:id", reviewHandler.CreateReview)
-				reviews.PUT("/:id", reviewHandler.UpdateReview)
-				reviews.DELETE("/:id", reviewHandler.DeleteReview)
-			}
-
-			// Pagos
-			payments := protected.Group("/payments")
-			{
-				payments.POST("/preference", paymentHandler.CreatePreference)
-				payments.GET("/:id/status", paymentHandler.GetPaymentStatus)
-				payments.POST("/webhook", paymentHandler.HandleWebhook)
-			}
-		}
-	}
-}
+package main
+
+import (
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/database"
+	"backend-padel-go/internal/handlers"
+	"backend-padel-go/internal/middleware"
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	_ "backend-padel-go/docs" // Importar documentación Swagger
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/gin-swagger/swaggerFiles"
+)
+
+// @title Padel Courts API
+// @version 1.0
+// @description API para gestión de canchas de padel
+// @host localhost:8080
+// @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+func main() {
+	// Cargar variables de entorno
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	// Configurar base de datos
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Migrar esquemas
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Configurar Gin
+	if os.Getenv("GIN_MODE") == "release" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := gin.New()
+
+	// Middleware global
+	r.Use(gin.Logger())
+	r.Use(gin.Recovery())
+	r.Use(middleware.CORS())
+
+	cfg := config.Load()
+
+	// Inicializar servicios
+	authService := services.NewAuthService(db)
+	courtService := services.NewCourtService(db)
+	clubService := services.NewClubService(db, courtService)
+	paymentService := services.NewPaymentService(db, services.NewPaymentGateway(cfg))
+	bookingService := services.NewBookingService(db, paymentService)
+	reviewService := services.NewReviewService(db)
+	waitlistService := services.NewWaitlistService(db)
+	notificationService := services.NewNotificationService(db)
+	playerService := services.NewPlayerService(db)
+	tournamentService := services.NewTournamentService(db, bookingService)
+	leagueService := services.NewLeagueService(db, bookingService)
+	classService := services.NewClassService(db, bookingService)
+
+	// Liberar turnos de reservas pendientes cuyo bloqueo venció
+	bookingService.StartHoldExpirer(time.Duration(cfg.Booking.HoldExpirerIntervalSec) * time.Second)
+
+	// Marcar como completadas las reservas confirmadas cuyo turno ya terminó
+	bookingService.StartCompletionScheduler(time.Duration(cfg.Booking.CompletionIntervalSec) * time.Second)
+
+	// Conciliar con el proveedor los pagos pendientes cuyo webhook no llegó
+	paymentService.StartReconciler(time.Duration(cfg.Payment.ReconcileIntervalSec)*time.Second, time.Duration(cfg.Payment.ReconcileAfterMinutes)*time.Minute)
+
+	// Inicializar handlers
+	authHandler := handlers.NewAuthHandler(authService)
+	courtHandler := handlers.NewCourtHandler(courtService)
+	clubHandler := handlers.NewClubHandler(clubService)
+	bookingHandler := handlers.NewBookingHandler(bookingService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	playerHandler := handlers.NewPlayerHandler(playerService)
+	tournamentHandler := handlers.NewTournamentHandler(tournamentService)
+	leagueHandler := handlers.NewLeagueHandler(leagueService)
+	classHandler := handlers.NewClassHandler(classService)
+
+	// Configurar Swagger
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Configurar rutas
+	setupRoutes(r, authHandler, courtHandler, bookingHandler, paymentHandler, reviewHandler, waitlistHandler, notificationHandler, playerHandler, tournamentHandler, leagueHandler, classHandler, clubHandler)
+
+	// Iniciar servidor
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	log.Printf("Server starting on port %s", port)
+	if err := r.Run(":" + port); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
+
+func setupRoutes(r *gin.Engine, authHandler *handlers.AuthHandler, courtHandler *handlers.CourtHandler, bookingHandler *handlers.BookingHandler, paymentHandler *handlers.PaymentHandler, reviewHandler *handlers.ReviewHandler, waitlistHandler *handlers.WaitlistHandler, notificationHandler *handlers.NotificationHandler, playerHandler *handlers.PlayerHandler, tournamentHandler *handlers.TournamentHandler, leagueHandler *handlers.LeagueHandler, classHandler *handlers.ClassHandler, clubHandler *handlers.ClubHandler) {
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "OK"})
+	})
+
+	// Grupo de rutas API
+	v1 := r.Group("/api/v1")
+	{
+		// Rutas de autenticación
+		auth := v1.Group("/auth")
+		{
+			auth.POST("/register", authHandler.Register)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.RefreshToken)
+		}
+
+		// Rutas públicas de canchas
+		courts := v1.Group("/courts")
+		{
+			courts.GET("", courtHandler.GetAllCourts)
+			courts.GET("/:id", courtHandler.GetCourtByID)
+			courts.GET("/nearby", courtHandler.GetNearbyCourts)
+			courts.GET("/search", courtHandler.SearchCourts)
+			courts.GET("/:id/availability", courtHandler.GetAvailability)
+			courts.GET("/:id/business-hours", courtHandler.GetBusinessHours)
+			courts.GET("/:id/reviews", reviewHandler.GetCourtReviews)
+		}
+
+		// Rutas públicas de clubes
+		clubs := v1.Group("/clubs")
+		{
+			clubs.GET("", clubHandler.SearchClubs)
+			clubs.GET("/:id", clubHandler.GetClub)
+			clubs.GET("/:id/availability", clubHandler.GetClubAvailability)
+		}
+
+		// Rutas públicas de torneos
+		tournaments := v1.Group("/tournaments")
+		{
+			tournaments.GET("", tournamentHandler.GetTournaments)
+			tournaments.GET("/:id", tournamentHandler.GetTournamentByID)
+			tournaments.GET("/:id/bracket", tournamentHandler.GetTournamentBracket)
+			tournaments.GET("/:id/standings", tournamentHandler.GetTournamentStandings)
+		}
+
+		// Rutas públicas de ligas
+		leagues := v1.Group("/leagues")
+		{
+			leagues.GET("", leagueHandler.GetLeagues)
+			leagues.GET("/:id", leagueHandler.GetLeagueByID)
+			leagues.GET("/:id/fixtures", leagueHandler.GetLeagueFixtures)
+			leagues.GET("/:id/standings", leagueHandler.GetLeagueStandings)
+		}
+
+		// Rutas públicas de clases
+		classes := v1.Group("/classes")
+		{
+			classes.GET("", classHandler.GetClasses)
+			classes.GET("/:id/sessions", classHandler.GetClassSessions)
+		}
+
+		// Notificaciones del proveedor de pagos (verificadas con la firma x-signature)
+		v1.POST("/payments/webhook", paymentHandler.HandleWebhook)
+
+		// Simulación de pagos del proveedor sandbox (desarrollo local), solo con PAYMENT_PROVIDER=sandbox
+		if paymentHandler.SandboxEnabled() {
+			v1.POST("/payments/sandbox/:preferenceId", paymentHandler.SimulateSandboxPayment)
+		}
+
+		// Rutas protegidas
+		protected := v1.Group("")
+		protected.Use(middleware.AuthRequired())
+		{
+			// Gestión de canchas (propietarios)
+			owner := protected.Group("/owner")
+			{
+				owner.POST("/clubs", clubHandler.CreateClub)
+				owner.GET("/clubs", clubHandler.GetOwnerClubs)
+				owner.PUT("/clubs/:id", clubHandler.UpdateClub)
+				owner.PUT("/clubs/:id/business-hours", clubHandler.ReplaceClubBusinessHours)
+				owner.POST("/courts", courtHandler.CreateCourt)
+				owner.GET("/courts", courtHandler.GetOwnerCourts)
+				owner.PUT("/courts/:id", courtHandler.UpdateCourt)
+				owner.DELETE("/courts/:id", courtHandler.DeleteCourt)
+				owner.GET("/courts/:id/statistics", courtHandler.GetCourtStatistics)
+				owner.POST("/courts/:id/special-hours", courtHandler.CreateSpecialHours)
+				owner.GET("/courts/:id/special-hours", courtHandler.GetSpecialHours)
+				owner.PUT("/courts/:id/business-hours", courtHandler.ReplaceBusinessHours)
+				owner.POST("/courts/:id/pricing-rules", courtHandler.CreatePricingRule)
+				owner.GET("/courts/:id/pricing-rules", courtHandler.GetPricingRules)
+				owner.DELETE("/courts/:id/pricing-rules/:ruleId", courtHandler.DeletePricingRule)
+				owner.GET("/courts/:id/schedule", bookingHandler.GetCourtDaySchedule)
+				owner.GET("/bookings", bookingHandler.GetOwnerBookings)
+				owner.POST("/bookings", bookingHandler.OwnerCreateBooking)
+				owner.POST("/bookings/:id/payments", bookingHandler.OwnerRecordPayment)
+				owner.PUT("/bookings/:id/confirm", bookingHandler.OwnerConfirmBooking)
+				owner.PUT("/bookings/:id/cancel", bookingHandler.OwnerCancelBooking)
+				owner.PUT("/bookings/:id/no-show", bookingHandler.MarkNoShow)
+				owner.POST("/tournaments", tournamentHandler.CreateTournament)
+				owner.GET("/tournaments", tournamentHandler.GetOwnerTournaments)
+				owner.POST("/tournaments/:id/generate", tournamentHandler.GenerateTournament)
+				owner.PUT("/tournaments/:id/matches/:matchId/result", tournamentHandler.RecordTournamentResult)
+				owner.PUT("/tournaments/:id/cancel", tournamentHandler.CancelTournament)
+				owner.POST("/leagues", leagueHandler.CreateLeague)
+				owner.GET("/leagues", leagueHandler.GetOwnerLeagues)
+				owner.POST("/leagues/:id/start", leagueHandler.StartLeague)
+				owner.PUT("/leagues/:id/fixtures/:fixtureId/result", leagueHandler.RecordLeagueResult)
+				owner.POST("/leagues/:id/next-season", leagueHandler.StartNextSeason)
+				owner.POST("/coaches", classHandler.CreateCoach)
+				owner.GET("/coaches", classHandler.GetCoaches)
+				owner.PUT("/coaches/:id", classHandler.UpdateCoach)
+				owner.POST("/classes", classHandler.CreateClass)
+				owner.GET("/classes", classHandler.GetOwnerClasses)
+				owner.PUT("/classes/:id/cancel", classHandler.CancelClass)
+				owner.PUT("/classes/sessions/:id/cancel", classHandler.CancelClassSession)
+				owner.POST("/classes/packs", classHandler.SellClassPack)
+				owner.PUT("/classes/enrollments/:id/payment", classHandler.RecordClassPayment)
+				owner.PUT("/classes/enrollments/:id/refund", classHandler.RecordClassRefund)
+				owner.POST("/payments/:id/refunds", middleware.OwnerOrAdminRequired(), paymentHandler.RefundPayment)
+				owner.GET("/payments/:id/refunds", middleware.OwnerOrAdminRequired(), paymentHandler.GetPaymentRefunds)
+			}
+
+			// Reservas
+			bookings := protected.Group("/bookings")
+			{
+				bookings.POST("", bookingHandler.CreateBooking)
+				bookings.GET("", bookingHandler.GetUserBookings)
+				bookings.GET("/:id", bookingHandler.GetBookingByID)
+				bookings.PUT("/:id/cancel", bookingHandler.CancelBooking)
+				bookings.GET("/:id/history", bookingHandler.GetBookingHistory)
+				bookings.PUT("/:id/split", bookingHandler.SplitBooking)
+				bookings.GET("/:id/payments", paymentHandler.GetBookingPayments)
+				bookings.POST("/:id/participants", bookingHandler.InviteParticipant)
+				bookings.GET("/:id/participants", bookingHandler.GetBookingParticipants)
+				bookings.DELETE("/:id/participants/:participantId", bookingHandler.RemoveParticipant)
+				bookings.POST("/:id/open-match", bookingHandler.PublishOpenMatch)
+				bookings.DELETE("/:id/open-match", bookingHandler.CloseOpenMatch)
+				bookings.POST("/:id/result", playerHandler.RecordMatchResult)
+				bookings.GET("/:id/result", playerHandler.GetMatchResult)
+				bookings.PUT("/:id/result/confirm", playerHandler.ConfirmMatchResult)
+				bookings.PUT("/:id/result/dispute", playerHandler.DisputeMatchResult)
+
+				// Turnos fijos
+				bookings.POST("/series", bookingHandler.CreateBookingSeries)
+				bookings.GET("/series", bookingHandler.GetUserSeries)
+				bookings.GET("/series/:id", bookingHandler.GetSeriesByID)
+				bookings.PUT("/series/:id/cancel", bookingHandler.CancelSeriesRemainder)
+				bookings.PUT("/series/:id/occurrences/:bookingId/cancel", bookingHandler.CancelSeriesOccurrence)
+			}
+
+			// Partidos abiertos
+			matches := protected.Group("/matches")
+			{
+				matches.GET("/open", bookingHandler.SearchOpenMatches)
+				matches.POST("/:id/join", bookingHandler.JoinOpenMatch)
+			}
+
+			// Invitaciones a reservas
+			invitations := protected.Group("/invitations")
+			{
+				invitations.GET("", bookingHandler.GetInvitations)
+				invitations.PUT("/:id/accept", bookingHandler.AcceptInvitation)
+				invitations.PUT("/:id/decline", bookingHandler.DeclineInvitation)
+			}
+
+			// Reseñas
+			reviews := protected.Group("/reviews")
+			{
+				reviews.POST("/courts/:id", reviewHandler.CreateReview)
+				reviews.PUT("/:id", reviewHandler.UpdateReview)
+				reviews.DELETE("/:id", reviewHandler.DeleteReview)
+			}
+
+			// Pagos
+			payments := protected.Group("/payments")
+			{
+				payments.POST("/preference", paymentHandler.CreatePreference)
+				payments.GET("/:id/status", paymentHandler.GetPaymentStatus)
+			}
+
+			// Lista de espera
+			waitlist := protected.Group("/waitlist")
+			{
+				waitlist.POST("", waitlistHandler.JoinWaitlist)
+				waitlist.GET("", waitlistHandler.GetUserWaitlist)
+				waitlist.DELETE("/:id", waitlistHandler.LeaveWaitlist)
+			}
+
+			// Notificaciones
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.GetNotifications)
+				notifications.PUT("/:id/read", notificationHandler.MarkNotificationRead)
+			}
+
+			// Inscripción a torneos
+			protected.POST("/tournaments/:id/register", tournamentHandler.RegisterTournament)
+			protected.DELETE("/tournaments/:id/register", tournamentHandler.WithdrawTournament)
+			protected.POST("/leagues/:id/teams", leagueHandler.RegisterLeagueTeam)
+			protected.POST("/leagues/:id/fixtures/:fixtureId/booking", leagueHandler.BookLeagueFixture)
+			protected.POST("/leagues/:id/fixtures/:fixtureId/result", leagueHandler.ReportLeagueResult)
+			protected.PUT("/leagues/:id/fixtures/:fixtureId/confirm", leagueHandler.ConfirmLeagueResult)
+			protected.PUT("/leagues/:id/fixtures/:fixtureId/dispute", leagueHandler.DisputeLeagueResult)
+			protected.POST("/classes/sessions/:id/enroll", classHandler.EnrollClass)
+			protected.GET("/classes/enrollments", classHandler.GetMyClassEnrollments)
+			protected.DELETE("/classes/enrollments/:id", classHandler.CancelClassEnrollment)
+			protected.GET("/classes/packs", classHandler.GetMyClassPacks)
+			protected.GET("/classes/coaching", classHandler.GetCoachSessions)
+			protected.GET("/classes/sessions/:id/roster", classHandler.GetSessionRoster)
+			protected.PUT("/classes/sessions/:id/attendance", classHandler.MarkClassAttendance)
+
+			// Perfil de jugador y rating
+			players := protected.Group("/players")
+			{
+				players.GET("/me", playerHandler.GetMyProfile)
+				players.PUT("/me", playerHandler.UpdateMyProfile)
+				players.GET("/me/rating-history", playerHandler.GetMyRatingHistory)
+				players.GET("/partners", playerHandler.SuggestPartners)
+				players.GET("/:id", playerHandler.GetPlayerProfile)
+			}
+
+			// Administración
+			admin := protected.Group("/admin")
+			admin.Use(middleware.AdminRequired())
+			{
+				admin.GET("/payments/reconciliation", paymentHandler.GetReconciliationReport)
+				admin.POST("/payments/reconcile", paymentHandler.ReconcilePayments)
+			}
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-padel-go/internal/config"
+	"backend-padel-go/internal/handlers"
+	"backend-padel-go/internal/models"
+	"backend-padel-go/internal/services"
+	"backend-padel-go/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+// newTestServer arma el router de la API sobre una base de prueba, con el proveedor de pagos simulado
+func newTestServer(t *testing.T) (*gin.Engine, *gorm.DB, *services.SandboxGateway) {
+	t.Helper()
+	r, db, gateway := newTestServerWithProvider(t, "sandbox")
+	return r, db, gateway.(*services.SandboxGateway)
+}
+
+func newTestServerWithProvider(t *testing.T, provider string) (*gin.Engine, *gorm.DB, services.PaymentGateway) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("PAYMENT_PROVIDER", provider)
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", testWebhookSecret)
+
+	db := testutil.NewDB(t)
+	cfg := config.Load()
+
+	courtService := services.NewCourtService(db)
+	gateway := services.NewPaymentGateway(cfg)
+	paymentService := services.NewPaymentService(db, gateway)
+	bookingService := services.NewBookingService(db, paymentService)
+
+	r := gin.New()
+	setupRoutes(r,
+		handlers.NewAuthHandler(services.NewAuthService(db)),
+		handlers.NewCourtHandler(courtService),
+		handlers.NewBookingHandler(bookingService),
+		handlers.NewPaymentHandler(paymentService),
+		handlers.NewReviewHandler(services.NewReviewService(db)),
+		handlers.NewWaitlistHandler(services.NewWaitlistService(db)),
+		handlers.NewNotificationHandler(services.NewNotificationService(db)),
+		handlers.NewPlayerHandler(services.NewPlayerService(db)),
+		handlers.NewTournamentHandler(services.NewTournamentService(db, bookingService)),
+		handlers.NewLeagueHandler(services.NewLeagueService(db, bookingService)),
+		handlers.NewClassHandler(services.NewClassService(db, bookingService)),
+		handlers.NewClubHandler(services.NewClubService(db, courtService)),
+	)
+
+	return r, db, gateway
+}
+
+func doJSON(r *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeData decodifica el campo data de una respuesta exitosa de la API
+func decodeData(t *testing.T, w *httptest.ResponseRecorder, dst interface{}) {
+	t.Helper()
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response body %q: %v", w.Body.String(), err)
+	}
+	if err := json.Unmarshal(resp.Data, dst); err != nil {
+		t.Fatalf("invalid response data %q: %v", resp.Data, err)
+	}
+}
+
+// registerUser registra un usuario por la API y devuelve su token de acceso
+func registerUser(t *testing.T, r *gin.Engine, email string) (string, uint) {
+	t.Helper()
+	w := doJSON(r, http.MethodPost, "/api/v1/auth/register", "", models.RegisterRequest{
+		Email:     email,
+		Password:  "secret123",
+		FirstName: "Test",
+		LastName:  "Player",
+		Phone:     "1122334455",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("register %s: status %d: %s", email, w.Code, w.Body.String())
+	}
+	var auth models.AuthResponse
+	decodeData(t, w, &auth)
+	return auth.AccessToken, auth.User.ID
+}
+
+func TestConcurrentBookingsForTheSameSlot(t *testing.T) {
+	r, db, _ := newTestServer(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	court := testutil.CreateCourt(t, db, owner.ID)
+
+	const players = 8
+	tokens := make([]string, players)
+	for i := range tokens {
+		tokens[i], _ = registerUser(t, r, fmt.Sprintf("player%d@test.com", i))
+	}
+
+	req := models.CreateBookingRequest{
+		CourtID:   court.ID,
+		Date:      time.Now().AddDate(0, 0, 2).Format("2006-01-02"),
+		StartTime: "18:00",
+		EndTime:   "19:30",
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, players)
+	start := make(chan struct{})
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			codes[i] = doJSON(r, http.MethodPost, "/api/v1/bookings", tokens[i], req).Code
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		}
+	}
+	if created != 1 || conflicts != players-1 {
+		t.Fatalf("expected 1 booking created and %d conflicts, got status codes %v", players-1, codes)
+	}
+
+	var count int64
+	db.Model(&models.Booking{}).Where("court_id = ? AND status = ?", court.ID, "pending").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 pending booking for the slot, got %d", count)
+	}
+}
+
+// signedWebhook arma la notificación de pago del proveedor firmada como lo hace MercadoPago
+func signedWebhook(eventID int, paymentID, requestID string) *http.Request {
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":     eventID,
+		"type":   "payment",
+		"action": "payment.created",
+		"data":   map[string]string{"id": paymentID},
+	})
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(fmt.Sprintf("id:%s;request-id:%s;ts:%s;", strings.ToLower(paymentID), requestID, ts)))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhook?type=payment&data.id="+paymentID, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-request-id", requestID)
+	req.Header.Set("x-signature", fmt.Sprintf("ts=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil))))
+	return req
+}
+
+func TestSandboxPaymentWebhookConfirmsBooking(t *testing.T) {
+	r, db, gateway := newTestServer(t)
+	owner := testutil.CreateUser(t, db, "owner@test.com", "owner")
+	court := testutil.CreateCourt(t, db, owner.ID)
+	token, _ := registerUser(t, r, "player@test.com")
+
+	w := doJSON(r, http.MethodPost, "/api/v1/bookings", token, models.CreateBookingRequest{
+		CourtID:   court.ID,
+		Date:      time.Now().AddDate(0, 0, 2).Format("2006-01-02"),
+		StartTime: "18:00",
+		EndTime:   "19:30",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create booking: status %d: %s", w.Code, w.Body.String())
+	}
+	var booking models.BookingResponse
+	decodeData(t, w, &booking)
+
+	w = doJSON(r, http.MethodPost, "/api/v1/payments/preference", token, models.CreatePreferenceRequest{BookingID: booking.ID, PayerEmail: "player@test.com"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create preference: status %d: %s", w.Code, w.Body.String())
+	}
+	var preference models.PreferenceResponse
+	decodeData(t, w, &preference)
+
+	// El jugador paga en el sandbox y el proveedor notifica el pago al webhook
+	gatewayPayment, err := gateway.SimulatePayment(preference.ID, "approved")
+	if err != nil {
+		t.Fatalf("simulate payment: %v", err)
+	}
+
+	unsigned := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhook?data.id="+gatewayPayment.ID, strings.NewReader(`{"id":1,"action":"payment.created","data":{"id":"`+gatewayPayment.ID+`"}}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, unsigned)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an unsigned webhook to be rejected, got status %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, signedWebhook(1001, gatewayPayment.ID, "req-1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("webhook: status %d: %s", w.Code, w.Body.String())
+	}
+
+	var stored models.Booking
+	db.First(&stored, booking.ID)
+	if stored.Status != "confirmed" {
+		t.Fatalf("expected the paid booking to be confirmed, got %s", stored.Status)
+	}
+	var event models.WebhookEvent
+	db.Where("event_id = ?", "1001").First(&event)
+	if event.Status != "processed" || event.ProcessedAt == nil {
+		t.Fatalf("expected the webhook event to be processed, got %+v", event)
+	}
+
+	// El proveedor reenvía la misma notificación: se acepta pero no se vuelve a procesar
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, signedWebhook(1001, gatewayPayment.ID, "req-2"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("replayed webhook: status %d: %s", w.Code, w.Body.String())
+	}
+
+	var events []models.WebhookEvent
+	db.Where("event_id = ?", "1001").Find(&events)
+	if len(events) != 1 || events[0].ProcessedAt == nil || !events[0].ProcessedAt.Equal(*event.ProcessedAt) {
+		t.Fatalf("expected the replayed webhook to be ignored, got %+v", events)
+	}
+	var confirmations int64
+	db.Model(&models.BookingStatusHistory{}).Where("booking_id = ? AND to_status = ?", booking.ID, "confirmed").Count(&confirmations)
+	if confirmations != 1 {
+		t.Fatalf("expected a single confirmation, got %d", confirmations)
+	}
+}
+
+func TestSandboxRouteRequiresSandboxProvider(t *testing.T) {
+	r, _, _ := newTestServerWithProvider(t, "mercadopago")
+	w := doJSON(r, http.MethodPost, "/api/v1/payments/sandbox/any", "", models.SandboxPaymentRequest{Status: "approved"})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected the sandbox route to be unavailable, got status %d", w.Code)
+	}
+
+	r, _, _ = newTestServerWithProvider(t, "sandbox")
+	w = doJSON(r, http.MethodPost, "/api/v1/payments/sandbox/any", "", models.SandboxPaymentRequest{Status: "approved"})
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "sandbox preference not found") {
+		t.Fatalf("expected the sandbox route to look up the preference, got status %d: %s", w.Code, w.Body.String())
+	}
+}